@@ -10,8 +10,11 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	clitool "github.com/urfave/cli/v2"
 	"os"
+	"strings"
 )
 
+const configPathFlag = "config"
+
 type FrogbotCommand interface {
 	// Run the command
 	Run(config utils.RepoAggregator, client vcsclient.VcsClient) error
@@ -100,5 +103,60 @@ func GetCommands() []*clitool.Command {
 			},
 			Flags: []clitool.Flag{},
 		},
+		{
+			Name:    "validate-config",
+			Aliases: []string{"vc"},
+			Usage:   "Validate the frogbot-config.yml file against the Frogbot configuration schema",
+			Action: func(ctx *clitool.Context) error {
+				return ValidateConfig(ctx.String(configPathFlag))
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  configPathFlag,
+					Usage: "Path to the frogbot-config.yml file. If not set, .frogbot/frogbot-config.yml is searched for in the current directory and its parents.",
+				},
+			},
+		},
+		{
+			Name:  "init",
+			Usage: "Detect the projects in the current repository and generate the frogbot-config.yml file and the CI pipeline files that run Frogbot",
+			Action: func(ctx *clitool.Context) error {
+				initCmd := &InitCmd{
+					CiProvider:     ctx.String(ciProviderFlag),
+					GitProvider:    ctx.String(gitProviderFlag),
+					RepoName:       ctx.String(repoNameFlag),
+					Branch:         ctx.String(branchFlag),
+					AcceptDefaults: ctx.Bool(acceptDefaultsFlag),
+					Force:          ctx.Bool(forceFlag),
+				}
+				return initCmd.Run()
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  ciProviderFlag,
+					Usage: fmt.Sprintf("The CI server that runs Frogbot. Supported values: %s", strings.Join(supportedCiProviders, ", ")),
+				},
+				&clitool.StringFlag{
+					Name:  gitProviderFlag,
+					Usage: fmt.Sprintf("The Git provider that hosts the repository, for Jenkins and JFrog Pipelines. Supported values: %s", strings.Join(supportedInitGitProviders, ", ")),
+				},
+				&clitool.StringFlag{
+					Name:  repoNameFlag,
+					Usage: "The repository name. If not set, the name of the current directory is suggested.",
+				},
+				&clitool.StringFlag{
+					Name:  branchFlag,
+					Usage: "The branch to scan and fix. If not set, the checked out branch is suggested.",
+				},
+				&clitool.BoolFlag{
+					Name:  acceptDefaultsFlag,
+					Usage: "Don't prompt, and use the suggested values and all the detected projects.",
+				},
+				&clitool.BoolFlag{
+					Name:  forceFlag,
+					Usage: "Overwrite existing files.",
+				},
+			},
+		},
 	}
 }
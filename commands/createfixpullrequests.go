@@ -8,7 +8,7 @@ import (
 	"github.com/jfrog/frogbot/commands/utils/packagehandlers"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
-	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	audit "github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/generic"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
@@ -18,10 +18,15 @@ import (
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
+// A hidden marker of the comment that asks to update an out-of-date fix branch, so that the comment is written once
+const outOfDateFixBranchCommentMarker = "Frogbot: out-of-date fix branch"
+
 type CreateFixPullRequestsCmd struct {
 	// The interface that Frogbot utilizes to format and style the displayed messages on the Git providers
 	utils.OutputWriter
@@ -35,8 +40,16 @@ type CreateFixPullRequestsCmd struct {
 	baseWd string
 	// The git client the command performs git operations with
 	gitManager *utils.GitManager
+	// Determines whether the scanned repository is already a clone of the Git provider's repository, which is fixed without cloning it again
+	reuseClone bool
 	// Determines whether to open a pull request for each vulnerability fix or to aggregate all fixes into one pull request
 	aggregateFixes bool
+	// The strategy of selecting the fix versions of the vulnerable dependencies
+	fixVersionStrategy string
+	// The code owners of the cloned repository, read if the code owners review the fix pull requests
+	codeOwners *utils.CodeOwners
+	// The scan results of the project's working directories before they're fixed, kept if the fixes are verified
+	preFixResults map[string]*audit.Results
 	// The current project technology
 	projectTech coreutils.Technology
 	// Stores all package manager handlers for detected issues
@@ -58,13 +71,18 @@ func (cfp *CreateFixPullRequestsCmd) Run(repoAggregator utils.RepoAggregator, cl
 }
 
 func (cfp *CreateFixPullRequestsCmd) scanAndFixRepository(repository *utils.Repository, branch string, client vcsclient.VcsClient) (err error) {
+	if repository, err = repository.WithBranchPolicies(branch); err != nil {
+		return
+	}
 	cfp.baseWd, err = os.Getwd()
 	if err != nil {
 		return
 	}
-	cfp.setCommandPrerequisites(repository, branch, client)
+	cfp.setCommandPrerequisites(repository)
 	for i := range repository.Projects {
-		cfp.details.Project = &repository.Projects[i]
+		cfp.details = utils.NewProjectScanDetails(client, repository, &repository.Projects[i]).
+			SetFailOnInstallationErrors(*repository.FailOnSecurityIssues).
+			SetBranch(branch)
 		cfp.projectTech = ""
 		if err = cfp.scanAndFixProject(repository); err != nil {
 			return
@@ -73,14 +91,9 @@ func (cfp *CreateFixPullRequestsCmd) scanAndFixRepository(repository *utils.Repo
 	return
 }
 
-func (cfp *CreateFixPullRequestsCmd) setCommandPrerequisites(repository *utils.Repository, branch string, client vcsclient.VcsClient) {
-	cfp.details = utils.NewScanDetails(client, &repository.Server, &repository.Git).
-		SetXrayGraphScanParams(repository.Watches, repository.JFrogProjectKey).
-		SetFailOnInstallationErrors(*repository.FailOnSecurityIssues).
-		SetBranch(branch).
-		SetFixableOnly(repository.FixableOnly).
-		SetMinSeverity(repository.MinSeverity)
+func (cfp *CreateFixPullRequestsCmd) setCommandPrerequisites(repository *utils.Repository) {
 	cfp.aggregateFixes = repository.Git.AggregateFixes
+	cfp.fixVersionStrategy = repository.Git.FixVersionStrategy
 	cfp.OutputWriter = utils.GetCompatibleOutputWriter(repository.GitProvider)
 }
 
@@ -90,12 +103,17 @@ func (cfp *CreateFixPullRequestsCmd) scanAndFixProject(repository *utils.Reposit
 	// The value is a map of vulnerable package names -> the details of the vulnerable packages.x
 	// That means we have a map of all the vulnerabilities that were found in a specific folder, along with their full details.
 	vulnerabilitiesByPathMap := make(map[string]map[string]*utils.VulnerabilityDetails)
-	projectFullPathWorkingDirs := getFullPathWorkingDirs(cfp.details.Project.WorkingDirs, cfp.baseWd)
+	cfp.preFixResults = nil
+	projectFullPathWorkingDirs, err := getFullPathWorkingDirs(cfp.details.Project, cfp.baseWd)
+	if err != nil {
+		return err
+	}
 	for _, fullPathWd := range projectFullPathWorkingDirs {
 		scanResults, err := cfp.scan(fullPathWd)
 		if err != nil {
 			return err
 		}
+		cfp.storePreFixResults(fullPathWd, scanResults)
 
 		if !cfp.dryRun {
 			if err = utils.UploadScanToGitProvider(scanResults, repository, cfp.details.Branch(), cfp.details.Client()); err != nil {
@@ -116,9 +134,28 @@ func (cfp *CreateFixPullRequestsCmd) scanAndFixProject(repository *utils.Reposit
 	if fixNeeded {
 		return cfp.fixVulnerablePackages(vulnerabilitiesByPathMap)
 	}
+	if !cfp.aggregateFixes && !cfp.dryRun {
+		// No fix is needed, but the open fix pull requests of the project may be resolved
+		return cfp.closeAllResolvedPullRequests(vulnerabilitiesByPathMap)
+	}
 	return nil
 }
 
+// closeAllResolvedPullRequests closes the open fix pull requests of the project, when none of its dependencies need to be fixed.
+// The pull requests are closed through the Git provider, so the repository isn't cloned.
+func (cfp *CreateFixPullRequestsCmd) closeAllResolvedPullRequests(vulnerabilitiesByWdMap map[string]map[string]*utils.VulnerabilityDetails) (err error) {
+	if cfp.gitManager == nil {
+		if cfp.gitManager, err = newGitManager(cfp.dryRun, cfp.dryRunRepoPath, "", cfp.details.Git, cfp.details.Client()); err != nil {
+			return
+		}
+	}
+	openPullRequests, err := cfp.listOpenPullRequestsIfNeeded()
+	if err != nil {
+		return
+	}
+	return cfp.closeResolvedPullRequests(vulnerabilitiesByWdMap, openPullRequests)
+}
+
 // Audit the dependencies of the current commit.
 func (cfp *CreateFixPullRequestsCmd) scan(currentWorkingDir string) (*audit.Results, error) {
 	// Audit commit code
@@ -146,21 +183,30 @@ func (cfp *CreateFixPullRequestsCmd) getVulnerabilitiesMap(scanResults *xrayutil
 
 func (cfp *CreateFixPullRequestsCmd) fixVulnerablePackages(vulnerabilitiesByWdMap map[string]map[string]*utils.VulnerabilityDetails) (err error) {
 	if cfp.gitManager == nil {
-		cfp.gitManager, err = utils.NewGitManager(cfp.dryRun, cfp.dryRunRepoPath, ".", "origin", cfp.details.Token, cfp.details.Username, cfp.details.Git)
-		if err != nil {
+		if cfp.gitManager, err = cfp.newGitManager(); err != nil {
 			return
 		}
 	}
-	clonedRepoDir, restoreBaseDir, err := cfp.cloneRepository()
-	if err != nil {
+	if !cfp.reuseClone {
+		clonedRepoDir, restoreBaseDir, e := cfp.cloneRepository()
+		if e != nil {
+			return e
+		}
+		defer func() {
+			// On dry run don't delete the folder as we want to validate results.
+			if !cfp.dryRun {
+				err = errors.Join(err, restoreBaseDir(), fileutils.RemoveTempDir(clonedRepoDir))
+			}
+		}()
+	} else if err = cfp.gitManager.ResetWorktree(); err != nil {
+		// Discard the files the scan of the clone created, such as installed dependencies, so they aren't committed with the fixes
 		return
 	}
-	defer func() {
-		// On dry run don't delete the folder as we want to validate results.
-		if !cfp.dryRun {
-			err = errors.Join(err, restoreBaseDir(), fileutils.RemoveTempDir(clonedRepoDir))
+	if cfp.details.Git.CodeOwnersReviewers {
+		if cfp.codeOwners, err = utils.ReadCodeOwners("."); err != nil {
+			return
 		}
-	}()
+	}
 
 	if cfp.aggregateFixes {
 		return cfp.fixIssuesSinglePR(vulnerabilitiesByWdMap)
@@ -168,48 +214,242 @@ func (cfp *CreateFixPullRequestsCmd) fixVulnerablePackages(vulnerabilitiesByWdMa
 	return cfp.fixIssuesSeparatePRs(vulnerabilitiesByWdMap)
 }
 
-func (cfp *CreateFixPullRequestsCmd) fixIssuesSeparatePRs(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) error {
-	var err error
-	for fullPath, vulnerabilities := range vulnerabilitiesMap {
-		if e := cfp.fixProjectVulnerabilities(fullPath, vulnerabilities); e != nil {
-			err = errors.Join(err, fmt.Errorf("the following errors occured while fixing vulnerabilities in %s:\n%s", fullPath, e))
+func (cfp *CreateFixPullRequestsCmd) newGitManager() (*utils.GitManager, error) {
+	return newGitManager(cfp.dryRun, cfp.dryRunRepoPath, ".", cfp.details.Git, cfp.details.Client())
+}
+
+// newGitManager returns a GitManager of the local repository in projectPath, or of a repository that isn't cloned yet if projectPath is empty.
+func newGitManager(dryRun bool, dryRunRepoPath, projectPath string, git *utils.Git, client vcsclient.VcsClient) (*utils.GitManager, error) {
+	gitManager, err := utils.NewGitManager(dryRun, dryRunRepoPath, projectPath, "origin", git.Token, git.Username, git)
+	if err != nil || dryRun || !git.SshAuth.IsSet() {
+		return gitManager, err
+	}
+	// The repository may be downloaded with an HTTPS remote, so the SSH URL is taken from the Git provider
+	repositoryInfo, err := client.GetRepositoryInfo(context.Background(), git.RepoOwner, git.RepoName)
+	if err != nil {
+		return nil, err
+	}
+	return gitManager.SetSshCloneUrl(repositoryInfo.CloneInfo.SSH), nil
+}
+
+// fixIssuesSeparatePRs fixes every vulnerable package in a separate pull request.
+// The most severe and applicable vulnerabilities are fixed first, until the limit of open pull requests is reached.
+// The pull requests that the new pull requests supersede, and the pull requests of vulnerabilities that are no longer reported, are closed.
+func (cfp *CreateFixPullRequestsCmd) fixIssuesSeparatePRs(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) (err error) {
+	fixes := prioritizeFixes(vulnerabilitiesMap)
+	openPullRequests, err := cfp.listOpenPullRequestsIfNeeded()
+	if err != nil {
+		return
+	}
+	remainingPullRequests := cfp.getRemainingPullRequests(vulnerabilitiesMap, openPullRequests)
+	for i, fix := range fixes {
+		if remainingPullRequests == 0 {
+			log.Info(fmt.Sprintf("The limit of open Frogbot pull requests was reached. Skipping the fixes of %d vulnerable dependencies...", len(fixes)-i))
+			break
+		}
+		created, e := cfp.fixProjectVulnerability(fix)
+		if e != nil {
+			err = errors.Join(err, fmt.Errorf("the following errors occured while fixing vulnerabilities in %s:\n%s", fix.fullProjectPath, e))
+		}
+		if created {
+			superseded, e := cfp.closeSupersededPullRequests(fix, openPullRequests)
+			err = errors.Join(err, e)
+			// A pull request that replaces an open pull request doesn't add to the open pull requests
+			if !superseded && remainingPullRequests > 0 {
+				remainingPullRequests--
+			}
+		}
+
+		// After fixing the current vulnerability, checkout to the base branch to start fixing the next vulnerability
+		log.Debug("Running git checkout to base branch:", cfp.details.Branch())
+		if e = cfp.gitManager.CheckoutLocalBranch(cfp.details.Branch()); e != nil {
+			return errors.Join(err, cfp.handleUpdatePackageErrors(e))
 		}
 	}
-	return err
+	return errors.Join(err, cfp.closeResolvedPullRequests(vulnerabilitiesMap, openPullRequests))
+}
+
+// A vulnerable package to fix, and the full path of the working directory it was found in
+type projectFix struct {
+	fullProjectPath string
+	vulnDetails     *utils.VulnerabilityDetails
+}
+
+// getProjectWorkingDir returns the working directory relative to the root directory of the repository, as written in the metadata of the fix pull requests.
+func (cfp *CreateFixPullRequestsCmd) getProjectWorkingDir(fullProjectPath string) string {
+	if workingDir := utils.GetRelativeWd(fullProjectPath, cfp.baseWd); workingDir != "" {
+		return filepath.ToSlash(workingDir)
+	}
+	return utils.RootDir
+}
+
+// prioritizeFixes sorts the fixes by the severity and the applicability of the vulnerabilities, from the most severe.
+func prioritizeFixes(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) (fixes []projectFix) {
+	for fullProjectPath, vulnerabilities := range vulnerabilitiesMap {
+		for _, vulnDetails := range vulnerabilities {
+			fixes = append(fixes, projectFix{fullProjectPath: fullProjectPath, vulnDetails: vulnDetails})
+		}
+	}
+	sort.Slice(fixes, func(i, j int) bool {
+		// The severity value of a vulnerability is higher when it's applicable
+		if fixes[i].vulnDetails.SeverityNumValue != fixes[j].vulnDetails.SeverityNumValue {
+			return fixes[i].vulnDetails.SeverityNumValue > fixes[j].vulnDetails.SeverityNumValue
+		}
+		if fixes[i].fullProjectPath != fixes[j].fullProjectPath {
+			return fixes[i].fullProjectPath < fixes[j].fullProjectPath
+		}
+		return fixes[i].vulnDetails.ImpactedDependencyName < fixes[j].vulnDetails.ImpactedDependencyName
+	})
+	return
+}
+
+// listOpenPullRequestsIfNeeded lists the open pull requests of the repository, if the number of open pull requests is limited, or if superseded pull requests may be closed.
+func (cfp *CreateFixPullRequestsCmd) listOpenPullRequestsIfNeeded() ([]vcsclient.PullRequestInfo, error) {
+	if cfp.dryRun && !cfp.isPullRequestsLimited() {
+		return nil, nil
+	}
+	return cfp.details.Client().ListOpenPullRequestsWithBody(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName)
+}
+
+func (cfp *CreateFixPullRequestsCmd) isPullRequestsLimited() bool {
+	return cfp.details.Git.MaxOpenPullRequests > 0 || cfp.details.Project.MaxOpenPullRequests > 0
+}
+
+// getRemainingPullRequests returns the number of pull requests that may be opened before reaching the limits of open Frogbot pull requests of the repository and the project.
+// Returns -1 if the number of open pull requests isn't limited.
+func (cfp *CreateFixPullRequestsCmd) getRemainingPullRequests(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails, openPullRequests []vcsclient.PullRequestInfo) int {
+	if !cfp.isPullRequestsLimited() {
+		return -1
+	}
+	repositoryLimit, projectLimit := cfp.details.Git.MaxOpenPullRequests, cfp.details.Project.MaxOpenPullRequests
+	// The open pull requests of the project are the fix pull requests of its scanned working directories
+	projectWorkingDirs := datastructures.MakeSet[string]()
+	for fullProjectPath := range vulnerabilitiesMap {
+		projectWorkingDirs.Add(cfp.getProjectWorkingDir(fullProjectPath))
+	}
+	var repositoryPullRequests, projectPullRequests int
+	for _, pullRequest := range openPullRequests {
+		// Frogbot pull requests are identified by the template of their branch, or by the footer of their body
+		if !cfp.gitManager.IsFrogbotBranch(pullRequest.Source.Name) && !strings.Contains(pullRequest.Body, utils.CommentGeneratedByFrogbot) {
+			continue
+		}
+		repositoryPullRequests++
+		if metadata := cfp.getFixPullRequestMetadata(pullRequest); metadata != nil && projectWorkingDirs.Exists(metadata.WorkingDir) {
+			projectPullRequests++
+		}
+	}
+	log.Debug(fmt.Sprintf("Found %d open Frogbot pull requests in the repository, %d of them fix the current project", repositoryPullRequests, projectPullRequests))
+	remaining := projectLimit - projectPullRequests
+	if repositoryLimit > 0 && (projectLimit == 0 || repositoryLimit-repositoryPullRequests < remaining) {
+		remaining = repositoryLimit - repositoryPullRequests
+	}
+	if remaining < 0 {
+		// The limit was already exceeded
+		return 0
+	}
+	return remaining
 }
 
-func (cfp *CreateFixPullRequestsCmd) fixProjectVulnerabilities(fullProjectPath string, vulnerabilities map[string]*utils.VulnerabilityDetails) (err error) {
+// fixProjectVulnerability fixes the vulnerable package in the working directory of the project, and returns true if a pull request was created.
+func (cfp *CreateFixPullRequestsCmd) fixProjectVulnerability(fix projectFix) (created bool, err error) {
 	// Update the working directory to the project's current working directory
-	projectWorkingDir := utils.GetRelativeWd(fullProjectPath, cfp.baseWd)
+	projectWorkingDir := utils.GetRelativeWd(fix.fullProjectPath, cfp.baseWd)
 
 	// 'CD' into the relevant working directory
 	if projectWorkingDir != "" {
 		restoreDir, err := utils.Chdir(projectWorkingDir)
 		if err != nil {
-			return err
+			return false, err
 		}
 		defer func() {
 			err = errors.Join(err, restoreDir())
 		}()
 	}
 
-	// Fix every vulnerability in a separate pull request and branch
-	for _, vulnerability := range vulnerabilities {
-		if e := cfp.fixSinglePackageAndCreatePR(vulnerability); e != nil {
-			err = errors.Join(err, cfp.handleUpdatePackageErrors(e))
-		}
+	if created, err = cfp.fixSinglePackageAndCreatePR(fix.vulnDetails, cfp.getProjectWorkingDir(fix.fullProjectPath)); err != nil {
+		err = cfp.handleUpdatePackageErrors(err)
+	}
+	return
+}
 
-		// After fixing the current vulnerability, checkout to the base branch to start fixing the next vulnerability
-		log.Debug("Running git checkout to base branch:", cfp.details.Branch())
-		if e := cfp.gitManager.CheckoutLocalBranch(cfp.details.Branch()); e != nil {
-			err = errors.Join(err, cfp.handleUpdatePackageErrors(e))
-			return
+// closeSupersededPullRequests closes the open pull requests that fix the same package of the project with another version, after a new fix pull request was created.
+// Returns true if any pull request was superseded.
+func (cfp *CreateFixPullRequestsCmd) closeSupersededPullRequests(fix projectFix, openPullRequests []vcsclient.PullRequestInfo) (superseded bool, err error) {
+	workingDir := cfp.getProjectWorkingDir(fix.fullProjectPath)
+	fixBranchName, err := cfp.gitManager.GenerateFixBranchName(cfp.details.Branch(), fix.vulnDetails.ImpactedDependencyName, fix.vulnDetails.SuggestedFixedVersion)
+	if err != nil {
+		return
+	}
+	var replacement *vcsclient.PullRequestInfo
+	for _, pullRequest := range openPullRequests {
+		metadata := cfp.getFixPullRequestMetadata(pullRequest)
+		if metadata == nil || metadata.WorkingDir != workingDir || metadata.ImpactedDependencyName != fix.vulnDetails.ImpactedDependencyName || pullRequest.Source.Name == fixBranchName {
+			continue
+		}
+		superseded = true
+		if replacement == nil {
+			if replacement, err = cfp.getOpenPullRequestBySourceBranch(fixBranchName); err != nil {
+				return
+			}
 		}
+		comment := fmt.Sprintf("This pull request was superseded by a pull request that updates %s to version %s.", fix.vulnDetails.ImpactedDependencyName, fix.vulnDetails.SuggestedFixedVersion)
+		if replacement != nil {
+			comment = fmt.Sprintf("This pull request was superseded by %s, which updates %s to version %s.", utils.GetPullRequestReference(cfp.details.GitProvider, replacement.ID), fix.vulnDetails.ImpactedDependencyName, fix.vulnDetails.SuggestedFixedVersion)
+		}
+		err = errors.Join(err, cfp.closeFixPullRequest(pullRequest, metadata, comment))
 	}
+	return
+}
 
+// closeResolvedPullRequests closes the open pull requests of the project that fix packages that are no longer reported as vulnerable.
+func (cfp *CreateFixPullRequestsCmd) closeResolvedPullRequests(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails, openPullRequests []vcsclient.PullRequestInfo) (err error) {
+	vulnerablePackagesByWd := make(map[string]map[string]*utils.VulnerabilityDetails)
+	for fullProjectPath, vulnerabilities := range vulnerabilitiesMap {
+		vulnerablePackagesByWd[cfp.getProjectWorkingDir(fullProjectPath)] = vulnerabilities
+	}
+	for _, pullRequest := range openPullRequests {
+		metadata := cfp.getFixPullRequestMetadata(pullRequest)
+		if metadata == nil {
+			continue
+		}
+		vulnerabilities, scanned := vulnerablePackagesByWd[metadata.WorkingDir]
+		if !scanned || vulnerabilities[metadata.ImpactedDependencyName] != nil {
+			continue
+		}
+		comment := fmt.Sprintf("Closing this pull request, as %s is no longer reported as vulnerable in the %s branch.", metadata.ImpactedDependencyName, cfp.details.Branch())
+		err = errors.Join(err, cfp.closeFixPullRequest(pullRequest, metadata, comment))
+	}
 	return
 }
 
+// getFixPullRequestMetadata returns the metadata of the pull request, if it fixes a single package in the current branch.
+func (cfp *CreateFixPullRequestsCmd) getFixPullRequestMetadata(pullRequest vcsclient.PullRequestInfo) *utils.FixPullRequestMetadata {
+	if pullRequest.Target.Name != cfp.details.Branch() {
+		return nil
+	}
+	return utils.ParseFixPullRequestMetadata(pullRequest.Body)
+}
+
+// closeFixPullRequest comments on the pull request, closes it, and deletes its branch.
+func (cfp *CreateFixPullRequestsCmd) closeFixPullRequest(pullRequest vcsclient.PullRequestInfo, metadata *utils.FixPullRequestMetadata, comment string) error {
+	log.Info(fmt.Sprintf("Closing pull request %d, which updates the dependency '%s' to version '%s'", pullRequest.ID, metadata.ImpactedDependencyName, metadata.FixVersion))
+	client := cfp.details.Client()
+	if err := client.AddPullRequestComment(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, comment, int(pullRequest.ID)); err != nil {
+		return err
+	}
+	// The title isn't listed with the open pull requests, so it's generated again
+	title := cfp.gitManager.GeneratePullRequestTitle(metadata.ImpactedDependencyName, metadata.FixVersion)
+	if err := client.UpdatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, title, pullRequest.Body, pullRequest.Target.Name, int(pullRequest.ID), vcsutils.Closed); err != nil {
+		return err
+	}
+	if err := cfp.gitManager.DeleteRemoteBranch(pullRequest.Source.Name); err != nil {
+		// The pull request is closed, so the remaining branch doesn't fail the fix
+		log.Warn(err.Error())
+	}
+	return nil
+}
+
+// fixMultiplePackages updates the vulnerable packages of the project, and returns the fixed vulnerabilities.
 func (cfp *CreateFixPullRequestsCmd) fixMultiplePackages(fullProjectPath string, vulnerabilities map[string]*utils.VulnerabilityDetails) (fixedVulnerabilities []*utils.VulnerabilityDetails, err error) {
 	// Update the working directory to the project's current working directory
 	projectWorkingDir := utils.GetRelativeWd(fullProjectPath, cfp.baseWd)
@@ -265,7 +505,7 @@ func (cfp *CreateFixPullRequestsCmd) handleUpdatePackageErrors(err error) error
 
 // Creates a branch for the fixed package and open pull request against the target branch.
 // In case a branch already exists on remote, we skip it.
-func (cfp *CreateFixPullRequestsCmd) fixSinglePackageAndCreatePR(vulnDetails *utils.VulnerabilityDetails) (err error) {
+func (cfp *CreateFixPullRequestsCmd) fixSinglePackageAndCreatePR(vulnDetails *utils.VulnerabilityDetails, projectWorkingDir string) (created bool, err error) {
 	fixVersion := vulnDetails.SuggestedFixedVersion
 	log.Debug("Attempting to fix", vulnDetails.ImpactedDependencyName, "with", fixVersion)
 	fixBranchName, err := cfp.gitManager.GenerateFixBranchName(cfp.details.Branch(), vulnDetails.ImpactedDependencyName, fixVersion)
@@ -276,56 +516,197 @@ func (cfp *CreateFixPullRequestsCmd) fixSinglePackageAndCreatePR(vulnDetails *ut
 	if err != nil {
 		return
 	}
+	var stalePullRequest *vcsclient.PullRequestInfo
 	if existsInRemote {
-		log.Info(fmt.Sprintf("A pull request updating the dependency '%s' to version '%s' already exists. Skipping...", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
-		return
+		if stalePullRequest, err = cfp.getStaleFixPullRequest(fixBranchName); err != nil {
+			return
+		}
+		if stalePullRequest == nil {
+			log.Info(fmt.Sprintf("A pull request updating the dependency '%s' to version '%s' already exists. Skipping...", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
+			return
+		}
+		log.Info(fmt.Sprintf("The branch of pull request %d is out of date with the %s branch. Recreating the fix on top of the %s branch...", stalePullRequest.ID, cfp.details.Branch(), cfp.details.Branch()))
 	}
 	if err = cfp.gitManager.CreateBranchAndCheckout(fixBranchName); err != nil {
-		return fmt.Errorf("failed while creating new branch: \n%s", err.Error())
+		return false, fmt.Errorf("failed while creating new branch: \n%s", err.Error())
 	}
 	if err = cfp.updatePackageToFixedVersion(vulnDetails); err != nil {
 		return
 	}
-	if err = cfp.openFixingPullRequest(fixBranchName, vulnDetails); err != nil {
-		return fmt.Errorf("failed while creating a fixing pull request for: %s with version: %s with error: \n%s",
+	assignments, err := cfp.commitFix(vulnDetails)
+	if err != nil {
+		return false, fmt.Errorf("failed while committing the fix of: %s with version: %s with error: \n%s",
+			vulnDetails.ImpactedDependencyName, fixVersion, err.Error())
+	}
+	// The fix is committed before it's verified, so the files the verification creates aren't committed
+	verification, err := cfp.verifyFixes(projectWorkingDir, vulnDetails)
+	if err != nil {
+		return
+	}
+	if verification.failed() && cfp.details.Git.FailedVerificationAction == utils.SkipFailedFixes {
+		log.Warn(fmt.Sprintf("Skipping the pull request updating dependency '%s' to version '%s', since the fix failed the verification", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
+		return
+	}
+	if err = cfp.openFixingPullRequest(fixBranchName, vulnDetails, projectWorkingDir, stalePullRequest, assignments, verification); err != nil {
+		return false, fmt.Errorf("failed while creating a fixing pull request for: %s with version: %s with error: \n%s",
 			vulnDetails.ImpactedDependencyName, fixVersion, err.Error())
 	}
+	if stalePullRequest != nil {
+		log.Info(fmt.Sprintf("Updated Pull Request updating dependency '%s' to version '%s'", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
+		return false, nil
+	}
 	log.Info(fmt.Sprintf("Created Pull Request updating dependency '%s' to version '%s'", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
-	return
+	return true, nil
 }
 
-func (cfp *CreateFixPullRequestsCmd) openFixingPullRequest(fixBranchName string, vulnDetails *utils.VulnerabilityDetails) (err error) {
+// getStaleFixPullRequest returns the open pull request of the existing fix branch, if the branch is out of date with the base branch and should be recreated.
+// If the fix branch includes commits that weren't made by Frogbot, the branch isn't recreated, and the pull request is commented on instead.
+func (cfp *CreateFixPullRequestsCmd) getStaleFixPullRequest(fixBranchName string) (*vcsclient.PullRequestInfo, error) {
+	status, err := cfp.gitManager.GetFixBranchStatus(cfp.details.Branch(), fixBranchName)
+	if err != nil || status.Unknown || !status.OutOfDate {
+		return nil, err
+	}
+	pullRequest, err := cfp.getOpenPullRequestBySourceBranch(fixBranchName)
+	if err != nil || pullRequest == nil {
+		// A fix branch without an open pull request is left as is
+		return nil, err
+	}
+	if len(status.ForeignCommits) > 0 {
+		log.Info(fmt.Sprintf("The branch of pull request %d is out of date with the %s branch, but includes commits that weren't made by Frogbot", pullRequest.ID, cfp.details.Branch()))
+		return nil, cfp.commentOutOfDatePullRequest(pullRequest)
+	}
+	return pullRequest, nil
+}
+
+// commentOutOfDatePullRequest asks to update the branch of the pull request, unless it was already asked.
+func (cfp *CreateFixPullRequestsCmd) commentOutOfDatePullRequest(pullRequest *vcsclient.PullRequestInfo) error {
+	client := cfp.details.Client()
+	comments, err := client.ListPullRequestComments(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, int(pullRequest.ID))
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		if strings.Contains(comment.Content, outOfDateFixBranchCommentMarker) {
+			return nil
+		}
+	}
+	comment := fmt.Sprintf("The %s branch has changed since this pull request was created. Frogbot didn't recreate the fix, since the pull request includes commits that weren't made by Frogbot. Please update the branch with the latest changes of %s.", cfp.details.Branch(), cfp.details.Branch())
+	return client.AddPullRequestComment(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, comment+utils.MarkdownComment(outOfDateFixBranchCommentMarker), int(pullRequest.ID))
+}
+
+// commitFix commits the fix of the vulnerable package, and returns the labels, reviewers and assignees of its pull request.
+func (cfp *CreateFixPullRequestsCmd) commitFix(vulnDetails *utils.VulnerabilityDetails) (assignments *utils.PullRequestAssignments, err error) {
 	log.Debug("Checking if there are changes to commit")
 	isClean, err := cfp.gitManager.IsClean()
 	if err != nil {
 		return
 	}
 	if isClean {
-		return fmt.Errorf("there were no changes to commit after fixing the package '%s'", vulnDetails.ImpactedDependencyName)
+		return nil, fmt.Errorf("there were no changes to commit after fixing the package '%s'", vulnDetails.ImpactedDependencyName)
 	}
-	commitMessage := cfp.gitManager.GenerateCommitMessage(vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion)
-	if err = cfp.gitManager.AddAllAndCommit(commitMessage); err != nil {
+	if assignments, err = cfp.getPullRequestAssignments(vulnDetails); err != nil {
 		return
 	}
-	if err = cfp.gitManager.Push(false, fixBranchName); err != nil {
+	commitMessage := cfp.gitManager.GenerateCommitMessage(vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion)
+	err = cfp.gitManager.AddAllAndCommit(commitMessage)
+	return
+}
+
+// openFixingPullRequest pushes the committed fix, and opens a pull request.
+// If the fix recreates the branch of an open pull request, the branch is force-pushed and the pull request is updated instead.
+// Fixes that failed the verification are explained in the pull request, which is converted to a draft.
+func (cfp *CreateFixPullRequestsCmd) openFixingPullRequest(fixBranchName string, vulnDetails *utils.VulnerabilityDetails, projectWorkingDir string, existingPullRequest *vcsclient.PullRequestInfo, assignments *utils.PullRequestAssignments, verification *fixVerification) (err error) {
+	if err = cfp.gitManager.Push(existingPullRequest != nil, fixBranchName); err != nil {
 		return
 	}
 	scanHash, err := utils.VulnerabilityDetailsToMD5Hash(vulnDetails)
 	if err != nil {
 		return err
 	}
-	pullRequestTitle, prBody := cfp.preparePullRequestDetails(scanHash, []formats.VulnerabilityOrViolationRow{*vulnDetails.VulnerabilityOrViolationRow})
-	log.Debug("Creating Pull Request form:", fixBranchName, " to:", cfp.details.Branch())
-	return cfp.details.Client().CreatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, fixBranchName, cfp.details.Branch(), pullRequestTitle, prBody)
+	pullRequestTitle, prBody := cfp.preparePullRequestDetails(scanHash, []formats.VulnerabilityOrViolationRow{*vulnDetails.VulnerabilityOrViolationRow}, cfp.getNonCompliantFixVersionsNote(vulnDetails)+getUnfixedVulnerabilitiesNote(vulnDetails)+verification.toMarkdown())
+	metadata := utils.FixPullRequestMetadata{ImpactedDependencyName: vulnDetails.ImpactedDependencyName, FixVersion: vulnDetails.SuggestedFixedVersion, WorkingDir: projectWorkingDir}
+	prBody += metadata.ToMarkdownComment()
+	if existingPullRequest != nil {
+		log.Debug("Updating Pull Request:", existingPullRequest.ID)
+		err = cfp.details.Client().UpdatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, pullRequestTitle, prBody, cfp.details.Branch(), int(existingPullRequest.ID), vcsutils.Open)
+	} else {
+		log.Debug("Creating Pull Request form:", fixBranchName, " to:", cfp.details.Branch())
+		err = cfp.details.Client().CreatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, fixBranchName, cfp.details.Branch(), pullRequestTitle, prBody)
+	}
+	if err != nil {
+		return
+	}
+	cfp.completePullRequest(fixBranchName, existingPullRequest, assignments, verification, vulnDetails)
+	return
+}
+
+// getPullRequestAssignments returns the labels, reviewers and assignees of the pull request that fixes the vulnerabilities.
+// Must be called before the fix is committed, as the code owners review the changed files.
+func (cfp *CreateFixPullRequestsCmd) getPullRequestAssignments(vulnerabilities ...*utils.VulnerabilityDetails) (*utils.PullRequestAssignments, error) {
+	var changedFiles []string
+	if cfp.codeOwners != nil {
+		var err error
+		if changedFiles, err = cfp.gitManager.GetChangedFiles(); err != nil {
+			return nil, err
+		}
+	}
+	return cfp.details.Git.GetPullRequestAssignments(utils.GetHighestSeverity(vulnerabilities...), cfp.codeOwners, changedFiles), nil
+}
+
+// completePullRequest sets the labels, reviewers and assignees of the fix pull request, and enables its auto-merge if it's eligible.
+// The pull requests of fixes that failed the verification are converted to drafts, and never merged automatically.
+// The pull request is already open, so failures are only logged.
+func (cfp *CreateFixPullRequestsCmd) completePullRequest(fixBranchName string, pullRequest *vcsclient.PullRequestInfo, assignments *utils.PullRequestAssignments, verification *fixVerification, vulnerabilities ...*utils.VulnerabilityDetails) {
+	draft := verification.failed()
+	autoMerge := !draft && cfp.details.Git.AutoMerge.IsEligible(vulnerabilities...)
+	if assignments.IsEmpty() && !autoMerge && !draft {
+		return
+	}
+	var err error
+	if pullRequest == nil {
+		// The ID of the created pull request isn't returned by the Git provider client
+		if pullRequest, err = cfp.getOpenPullRequestBySourceBranch(fixBranchName); err != nil || pullRequest == nil {
+			log.Warn(fmt.Sprintf("Couldn't find the pull request of the %s branch to complete its details: %v", fixBranchName, err))
+			return
+		}
+	}
+	if !assignments.IsEmpty() {
+		if err = utils.AssignPullRequest(context.Background(), cfp.details.Client(), cfp.details.RepoOwner, cfp.details.RepoName, int(pullRequest.ID), assignments); err != nil {
+			log.Warn(fmt.Sprintf("Failed to set the labels, reviewers and assignees of pull request %d: %s", pullRequest.ID, err.Error()))
+		}
+	}
+	if draft {
+		if err = utils.ConvertPullRequestToDraft(context.Background(), cfp.details.Client(), cfp.details.RepoOwner, cfp.details.RepoName, int(pullRequest.ID)); err != nil {
+			log.Warn(fmt.Sprintf("Failed to convert pull request %d to a draft: %s", pullRequest.ID, err.Error()))
+		}
+	}
+	if autoMerge {
+		if err = utils.EnableAutoMerge(context.Background(), cfp.details.Client(), cfp.details.RepoOwner, cfp.details.RepoName, int(pullRequest.ID), cfp.details.Git.AutoMerge.MergeMethod); err != nil {
+			log.Warn(fmt.Sprintf("Failed to enable the auto-merge of pull request %d: %s", pullRequest.ID, err.Error()))
+		}
+	}
 }
 
 // openAggregatedPullRequest handles the opening or updating of a pull request when the aggregate mode is active.
 // If a pull request is already open, Frogbot will update the branch and the pull request body.
-func (cfp *CreateFixPullRequestsCmd) openAggregatedPullRequest(fixBranchName string, pullRequestInfo *vcsclient.PullRequestInfo, vulnerabilities []*utils.VulnerabilityDetails) (err error) {
+// The fixes are committed before they're verified, so the files the verification creates aren't committed.
+func (cfp *CreateFixPullRequestsCmd) openAggregatedPullRequest(fixBranchName string, pullRequestInfo *vcsclient.PullRequestInfo, vulnerabilities []*utils.VulnerabilityDetails, fixesByPath map[string][]*utils.VulnerabilityDetails) (err error) {
+	assignments, err := cfp.getPullRequestAssignments(vulnerabilities...)
+	if err != nil {
+		return
+	}
 	commitMessage := cfp.gitManager.GenerateAggregatedCommitMessage(cfp.projectTech)
 	if err = cfp.gitManager.AddAllAndCommit(commitMessage); err != nil {
 		return
 	}
+	verification, err := cfp.verifyAggregatedFixes(fixesByPath)
+	if err != nil {
+		return
+	}
+	if verification.failed() && cfp.details.Git.FailedVerificationAction == utils.SkipFailedFixes {
+		log.Warn("Skipping the aggregated pull request, since the fixes failed the verification")
+		return
+	}
 	if err = cfp.gitManager.Push(true, fixBranchName); err != nil {
 		return
 	}
@@ -337,22 +718,28 @@ func (cfp *CreateFixPullRequestsCmd) openAggregatedPullRequest(fixBranchName str
 	for _, vulnerability := range vulnerabilities {
 		vulnerabilityRows = append(vulnerabilityRows, *vulnerability.VulnerabilityOrViolationRow)
 	}
-	pullRequestTitle, prBody := cfp.preparePullRequestDetails(scanHash, vulnerabilityRows)
+	pullRequestTitle, prBody := cfp.preparePullRequestDetails(scanHash, vulnerabilityRows, cfp.getNonCompliantFixVersionsNote(vulnerabilities...)+getUnfixedVulnerabilitiesNote(vulnerabilities...)+verification.toMarkdown())
 	if pullRequestInfo == nil {
 		log.Info("Creating Pull Request from:", fixBranchName, "to:", cfp.details.Branch())
-		return cfp.details.Client().CreatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, fixBranchName, cfp.details.Branch(), pullRequestTitle, prBody)
+		err = cfp.details.Client().CreatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, fixBranchName, cfp.details.Branch(), pullRequestTitle, prBody)
+	} else {
+		log.Info("Updating Pull Request from:", fixBranchName, "to:", cfp.details.Branch())
+		err = cfp.details.Client().UpdatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, pullRequestTitle, prBody, "", int(pullRequestInfo.ID), vcsutils.Open)
 	}
-	log.Info("Updating Pull Request from:", fixBranchName, "to:", cfp.details.Branch())
-	return cfp.details.Client().UpdatePullRequest(context.Background(), cfp.details.RepoOwner, cfp.details.RepoName, pullRequestTitle, prBody, "", int(pullRequestInfo.ID), vcsutils.Open)
+	if err != nil {
+		return
+	}
+	cfp.completePullRequest(fixBranchName, pullRequestInfo, assignments, verification, vulnerabilities...)
+	return
 }
 
-func (cfp *CreateFixPullRequestsCmd) preparePullRequestDetails(scanHash string, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) (string, string) {
+func (cfp *CreateFixPullRequestsCmd) preparePullRequestDetails(scanHash string, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, fixVersionsNote string) (string, string) {
 	if cfp.dryRun && cfp.aggregateFixes {
 		// For testings, don't compare pull request body as scan results order may change.
 		return utils.GetAggregatedPullRequestTitle(cfp.projectTech), ""
 	}
 
-	prBody := cfp.OutputWriter.VulnerabiltiesTitle(false) + "\n" + cfp.OutputWriter.VulnerabilitiesContent(vulnerabilitiesRows) + "\n---\n" + cfp.OutputWriter.UntitledForJasMsg() + cfp.OutputWriter.Footer()
+	prBody := cfp.OutputWriter.VulnerabiltiesTitle(false) + "\n" + cfp.OutputWriter.VulnerabilitiesContent(vulnerabilitiesRows) + fixVersionsNote + "\n---\n" + cfp.OutputWriter.UntitledForJasMsg() + cfp.OutputWriter.Footer()
 	if cfp.aggregateFixes {
 		return utils.GetAggregatedPullRequestTitle(cfp.projectTech), prBody + utils.MarkdownComment(fmt.Sprintf("Checksum: %s", scanHash))
 	}
@@ -362,6 +749,36 @@ func (cfp *CreateFixPullRequestsCmd) preparePullRequestDetails(scanHash string,
 	return pullRequestTitle, prBody
 }
 
+// getNonCompliantFixVersionsNote explains which dependencies are updated to fix versions that don't comply with the fix version strategy,
+// since some of their vulnerabilities have no complying fix version. Returns an empty string if all the fix versions comply.
+func (cfp *CreateFixPullRequestsCmd) getNonCompliantFixVersionsNote(vulnerabilities ...*utils.VulnerabilityDetails) string {
+	var dependencies []string
+	for _, vulnerability := range vulnerabilities {
+		if vulnerability.NonCompliantFixVersion {
+			dependencies = append(dependencies, fmt.Sprintf("- %s: %s → %s", vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion, vulnerability.SuggestedFixedVersion))
+		}
+	}
+	if len(dependencies) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n**Note:** Some vulnerabilities of the following dependencies have no fix version that complies with the '%s' fix version strategy. The dependencies were updated to their lowest fix versions instead, which may include breaking changes:\n%s\n", cfp.fixVersionStrategy, strings.Join(dependencies, "\n"))
+}
+
+// getUnfixedVulnerabilitiesNote lists the vulnerabilities of the updated dependencies that have no fix version Frogbot can update to,
+// so the suggested fix versions may not fix them. Returns an empty string if there are no such vulnerabilities.
+func getUnfixedVulnerabilitiesNote(vulnerabilities ...*utils.VulnerabilityDetails) string {
+	var unfixedVulnerabilities []string
+	for _, vulnerability := range vulnerabilities {
+		for _, unfixedVulnerability := range vulnerability.UnfixedVulnerabilities {
+			unfixedVulnerabilities = append(unfixedVulnerabilities, "- "+unfixedVulnerability)
+		}
+	}
+	if len(unfixedVulnerabilities) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n**Note:** The fixed versions of the following vulnerabilities don't name a version to update to, so the suggested fix versions may not fix them. Check them manually:\n%s\n", strings.Join(unfixedVulnerabilities, "\n"))
+}
+
 func (cfp *CreateFixPullRequestsCmd) cloneRepository() (tempWd string, restoreDir func() error, err error) {
 	if cfp.dryRunRepoPath != "" {
 		tempWd, err = cfp.getDryRunClonedRepo()
@@ -375,7 +792,7 @@ func (cfp *CreateFixPullRequestsCmd) cloneRepository() (tempWd string, restoreDi
 	log.Debug("Created temp working directory:", tempWd)
 
 	// Clone the content of the repo to the new working directory
-	if err = cfp.gitManager.Clone(tempWd, cfp.details.Branch()); err != nil {
+	if err = cfp.gitManager.SetSparseCheckoutDirs(cfp.details.Project.WorkingDirs).Clone(tempWd, cfp.details.Branch()); err != nil {
 		return
 	}
 
@@ -406,6 +823,8 @@ func (cfp *CreateFixPullRequestsCmd) getDryRunClonedRepo() (tempWd string, err e
 // Create a vulnerabilities map - a map with 'impacted package' as a key and all the necessary information of this vulnerability as value.
 func (cfp *CreateFixPullRequestsCmd) createVulnerabilitiesMap(scanResults *xrayutils.ExtendedScanResults, isMultipleRoots bool) (map[string]*utils.VulnerabilityDetails, error) {
 	vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+	// Impacted package -> its vulnerabilities that have no fix version Frogbot can update to
+	unfixedVulnerabilities := map[string][]string{}
 	for _, scanResult := range scanResults.XrayResults {
 		if len(scanResult.Vulnerabilities) > 0 {
 			vulnerabilities, err := xrayutils.PrepareVulnerabilities(scanResult.Vulnerabilities, scanResults, isMultipleRoots, true)
@@ -413,7 +832,7 @@ func (cfp *CreateFixPullRequestsCmd) createVulnerabilitiesMap(scanResults *xrayu
 				return nil, err
 			}
 			for i := range vulnerabilities {
-				if err = cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap); err != nil {
+				if err = cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap, unfixedVulnerabilities); err != nil {
 					return nil, err
 				}
 			}
@@ -423,25 +842,40 @@ func (cfp *CreateFixPullRequestsCmd) createVulnerabilitiesMap(scanResults *xrayu
 				return nil, err
 			}
 			for i := range violations {
-				if err = cfp.addVulnerabilityToFixVersionsMap(&violations[i], vulnerabilitiesMap); err != nil {
+				if err = cfp.addVulnerabilityToFixVersionsMap(&violations[i], vulnerabilitiesMap, unfixedVulnerabilities); err != nil {
 					return nil, err
 				}
 			}
 		}
 	}
+	cfp.setFixVersionsDetails(vulnerabilitiesMap, unfixedVulnerabilities)
 	log.Debug("Frogbot will attempt to resolve the following vulnerable dependencies:\n", strings.Join(maps.Keys(vulnerabilitiesMap), ",\n"))
 	return vulnerabilitiesMap, nil
 }
 
-func (cfp *CreateFixPullRequestsCmd) addVulnerabilityToFixVersionsMap(vulnerability *formats.VulnerabilityOrViolationRow, vulnerabilitiesMap map[string]*utils.VulnerabilityDetails) error {
+// setFixVersionsDetails sets the details of the fix version of each impacted package, once the fix versions of all its vulnerabilities are aggregated.
+func (cfp *CreateFixPullRequestsCmd) setFixVersionsDetails(vulnerabilitiesMap map[string]*utils.VulnerabilityDetails, unfixedVulnerabilities map[string][]string) {
+	for impactedPackage, vulnDetails := range vulnerabilitiesMap {
+		vulnDetails.UnfixedVulnerabilities = unfixedVulnerabilities[impactedPackage]
+		// The suggested fix version is the highest among the vulnerabilities, so it may not comply even if some of them have a complying fix version
+		vulnDetails.NonCompliantFixVersion = !utils.IsFixVersionCompliant(cfp.fixVersionStrategy, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion)
+	}
+}
+
+func (cfp *CreateFixPullRequestsCmd) addVulnerabilityToFixVersionsMap(vulnerability *formats.VulnerabilityOrViolationRow, vulnerabilitiesMap map[string]*utils.VulnerabilityDetails, unfixedVulnerabilities map[string][]string) error {
 	if len(vulnerability.FixedVersions) == 0 {
 		return nil
 	}
 	if cfp.projectTech == "" {
 		cfp.projectTech = vulnerability.Technology
 	}
-	vulnFixVersion := getMinimalFixVersion(vulnerability.ImpactedDependencyVersion, vulnerability.FixedVersions)
+	vulnFixVersion, compliant := getFixVersion(cfp.fixVersionStrategy, vulnerability.Technology, vulnerability.ImpactedDependencyVersion, vulnerability.FixedVersions)
 	if vulnFixVersion == "" {
+		if unresolvedFixVersions := getUnresolvedFixVersions(vulnerability.Technology, vulnerability.FixedVersions); len(unresolvedFixVersions) > 0 {
+			unfixedVulnerability := fmt.Sprintf("%s in %s:%s (fixed versions: %s)", getVulnerabilityId(vulnerability), vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion, strings.Join(unresolvedFixVersions, ", "))
+			log.Warn("Frogbot can't fix", unfixedVulnerability+", since its fixed versions don't name a version to update to. Update the dependency manually")
+			unfixedVulnerabilities[vulnerability.ImpactedDependencyName] = append(unfixedVulnerabilities[vulnerability.ImpactedDependencyName], unfixedVulnerability)
+		}
 		return nil
 	}
 	if vulnDetails, exists := vulnerabilitiesMap[vulnerability.ImpactedDependencyName]; exists {
@@ -458,6 +892,9 @@ func (cfp *CreateFixPullRequestsCmd) addVulnerabilityToFixVersionsMap(vulnerabil
 		newVulnDetails.SetIsDirectDependency(isDirectDependency)
 		vulnerabilitiesMap[vulnerability.ImpactedDependencyName] = newVulnDetails
 	}
+	if !compliant {
+		log.Info(fmt.Sprintf("No fix version of %s complies with the '%s' fix version strategy. The minimal fix version %s is suggested instead", vulnerability.ImpactedDependencyName, cfp.fixVersionStrategy, vulnFixVersion))
+	}
 	// Set the fixed version array to the relevant fixed version so that only that specific fixed version will be displayed
 	vulnerability.FixedVersions = []string{vulnerabilitiesMap[vulnerability.ImpactedDependencyName].SuggestedFixedVersion}
 	return nil
@@ -523,6 +960,7 @@ func (cfp *CreateFixPullRequestsCmd) aggregateFixAndOpenPullRequest(vulnerabilit
 	}
 	// Fix all packages in the same branch if expected error accrued, log and continue.
 	var fixedVulnerabilities []*utils.VulnerabilityDetails
+	fixesByPath := map[string][]*utils.VulnerabilityDetails{}
 	for fullPath, vulnerabilities := range vulnerabilitiesMap {
 		currentFixes, e := cfp.fixMultiplePackages(fullPath, vulnerabilities)
 		if e != nil {
@@ -530,9 +968,10 @@ func (cfp *CreateFixPullRequestsCmd) aggregateFixAndOpenPullRequest(vulnerabilit
 			continue
 		}
 		fixedVulnerabilities = append(fixedVulnerabilities, currentFixes...)
+		fixesByPath[fullPath] = currentFixes
 	}
 	updateRequired, e := cfp.isUpdateRequired(fixedVulnerabilities, existingPullRequestInfo)
-	if err != nil {
+	if e != nil {
 		err = errors.Join(err, e)
 		return
 	}
@@ -541,8 +980,8 @@ func (cfp *CreateFixPullRequestsCmd) aggregateFixAndOpenPullRequest(vulnerabilit
 		return
 	}
 	if len(fixedVulnerabilities) > 0 {
-		if e := cfp.openAggregatedPullRequest(aggregatedFixBranchName, existingPullRequestInfo, fixedVulnerabilities); e != nil {
-			err = errors.Join(err, fmt.Errorf("failed while creating aggreagted pull request. Error: \n%s", err.Error()))
+		if e := cfp.openAggregatedPullRequest(aggregatedFixBranchName, existingPullRequestInfo, fixedVulnerabilities, fixesByPath); e != nil {
+			err = errors.Join(err, fmt.Errorf("failed while creating aggregated pull request: %w", e))
 		}
 	}
 	log.Info("-----------------------------------------------------------------")
@@ -573,36 +1012,80 @@ func (cfp *CreateFixPullRequestsCmd) isUpdateRequired(fixedVulnerabilities []*ut
 	return
 }
 
-// getMinimalFixVersion find the minimal version that fixes the current impactedPackage;
-// fixVersions is a sorted array. The function returns the first version in the array, that is larger than impactedPackageVersion.
-func getMinimalFixVersion(impactedPackageVersion string, fixVersions []string) string {
-	// Trim 'v' prefix in case of Go package
-	currVersionStr := strings.TrimPrefix(impactedPackageVersion, "v")
-	currVersion := version.NewVersion(currVersionStr)
+// getFixVersionCandidates returns the lowest version of each fixed versions range, among the versions greater than the impacted version.
+func getFixVersionCandidates(technology coreutils.Technology, impactedPackageVersion string, fixVersions []string) (candidates []string) {
 	for _, fixVersion := range fixVersions {
-		fixVersionCandidate := parseVersionChangeString(fixVersion)
-		if currVersion.Compare(fixVersionCandidate) > 0 {
-			return fixVersionCandidate
+		versionRanges, err := utils.ParseVersionRanges(technology, fixVersion)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Skipping the fixed versions '%s': %s", fixVersion, err.Error()))
+			continue
+		}
+		for _, versionRange := range versionRanges {
+			if candidate := versionRange.MinimalVersion(); candidate != "" && utils.CompareVersions(candidate, impactedPackageVersion) > 0 {
+				candidates = append(candidates, candidate)
+			}
 		}
 	}
-	return ""
+	return
 }
 
-// 1.0         --> 1.0 ≤ x
-// (,1.0]      --> x ≤ 1.0
-// (,1.0)      --> x &lt; 1.0
-// [1.0]       --> x == 1.0
-// (1.0,)      --> 1.0 &lt; x
-// (1.0, 2.0)  --> 1.0 &lt; x &lt; 2.0
-// [1.0, 2.0]  --> 1.0 ≤ x ≤ 2.0
-func parseVersionChangeString(fixVersion string) string {
-	latestVersion := strings.Split(fixVersion, ",")[0]
-	if latestVersion[0] == '(' {
-		return ""
+// getUnresolvedFixVersions returns the fixed versions ranges that don't name a version Frogbot can update to, such as (1.2.3,),
+// and the fixed versions that can't be parsed.
+func getUnresolvedFixVersions(technology coreutils.Technology, fixVersions []string) (unresolved []string) {
+	for _, fixVersion := range fixVersions {
+		versionRanges, err := utils.ParseVersionRanges(technology, fixVersion)
+		if err != nil {
+			unresolved = append(unresolved, fixVersion)
+			continue
+		}
+		for _, versionRange := range versionRanges {
+			if versionRange.MinimalVersion() == "" {
+				unresolved = append(unresolved, versionRange.String())
+			}
+		}
+	}
+	return
+}
+
+// getVulnerabilityId returns the first CVE of the vulnerability, or its Xray issue ID if it has no CVEs.
+func getVulnerabilityId(vulnerability *formats.VulnerabilityOrViolationRow) string {
+	if len(vulnerability.Cves) > 0 && vulnerability.Cves[0].Id != "" {
+		return vulnerability.Cves[0].Id
+	}
+	return vulnerability.IssueId
+}
+
+// getMinimalFixVersion returns the lowest version that fixes the impacted package, among the versions greater than the impacted version.
+// Returns an empty string if there's no such version.
+func getMinimalFixVersion(technology coreutils.Technology, impactedPackageVersion string, fixVersions []string) (minimalFixVersion string) {
+	for _, candidate := range getFixVersionCandidates(technology, impactedPackageVersion, fixVersions) {
+		if minimalFixVersion == "" || utils.CompareVersions(candidate, minimalFixVersion) < 0 {
+			minimalFixVersion = candidate
+		}
+	}
+	return
+}
+
+// getFixVersion returns the fix version that the strategy selects, among the fix versions greater than the impacted version.
+// If none of them complies with the strategy, the minimal fix version is returned and compliant is false.
+func getFixVersion(strategy string, technology coreutils.Technology, impactedPackageVersion string, fixVersions []string) (fixVersion string, compliant bool) {
+	minimalFixVersion := getMinimalFixVersion(technology, impactedPackageVersion, fixVersions)
+	if minimalFixVersion == "" || strategy == "" || strategy == utils.MinimalFixVersion {
+		return minimalFixVersion, true
+	}
+	for _, candidate := range getFixVersionCandidates(technology, impactedPackageVersion, fixVersions) {
+		if !utils.IsFixVersionCompliant(strategy, impactedPackageVersion, candidate) {
+			continue
+		}
+		// The latest strategy selects the highest fix version, and the others select the lowest compliant one
+		if fixVersion == "" || (utils.CompareVersions(candidate, fixVersion) > 0) == (strategy == utils.LatestFixVersion) {
+			fixVersion = candidate
+		}
+	}
+	if fixVersion == "" {
+		return minimalFixVersion, false
 	}
-	latestVersion = strings.Trim(latestVersion, "[")
-	latestVersion = strings.Trim(latestVersion, "]")
-	return latestVersion
+	return fixVersion, true
 }
 
 // Skip build tools dependencies (for example, pip)
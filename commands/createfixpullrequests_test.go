@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/frogbot/commands/utils/packagehandlers"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -266,35 +267,6 @@ pr body
 	}
 }
 
-// /      1.0         --> 1.0 ≤ x
-// /      (,1.0]      --> x ≤ 1.0
-// /      (,1.0)      --> x < 1.0
-// /      [1.0]       --> x == 1.0
-// /      (1.0,)      --> 1.0 < x
-// /      (1.0, 2.0)   --> 1.0 < x < 2.0
-// /      [1.0, 2.0]   --> 1.0 ≤ x ≤ 2.0
-func TestParseVersionChangeString(t *testing.T) {
-	tests := []struct {
-		versionChangeString string
-		expectedVersion     string
-	}{
-		{"1.2.3", "1.2.3"},
-		{"[1.2.3]", "1.2.3"},
-		{"[1.2.3, 2.0.0]", "1.2.3"},
-
-		{"(,1.2.3]", ""},
-		{"(,1.2.3)", ""},
-		{"(1.2.3,)", ""},
-		{"(1.2.3, 2.0.0)", ""},
-	}
-
-	for _, test := range tests {
-		t.Run(test.versionChangeString, func(t *testing.T) {
-			assert.Equal(t, test.expectedVersion, parseVersionChangeString(test.versionChangeString))
-		})
-	}
-}
-
 func TestGenerateFixBranchName(t *testing.T) {
 	tests := []struct {
 		baseBranch      string
@@ -373,16 +345,108 @@ func TestGetMinimalFixVersion(t *testing.T) {
 		{impactedVersionPackage: "v1.6.2", fixVersions: []string{"1.5.3", "1.6.1", "1.6.22", "1.7.0"}, expected: "1.6.22"},
 		{impactedVersionPackage: "1.7.1", fixVersions: []string{"1.5.3", "1.6.1", "1.6.22", "1.7.0"}, expected: ""},
 		{impactedVersionPackage: "1.7.1", fixVersions: []string{"2.5.3"}, expected: "2.5.3"},
+		{impactedVersionPackage: "1.6.2", fixVersions: []string{"1.7.0", "1.6.22"}, expected: "1.6.22"},
 		{impactedVersionPackage: "v1.7.1", fixVersions: []string{"0.5.3", "0.9.9"}, expected: ""},
+		// Intervals with exclusive bounds and multiple ranges
+		{impactedVersionPackage: "1.2.3", fixVersions: []string{"(1.2.3,)", "(1.2.3,1.2.8]"}, expected: "1.2.8"},
+		{impactedVersionPackage: "1.2.3", fixVersions: []string{"(,1.2.8]"}, expected: ""},
+		{impactedVersionPackage: "1.2.3", fixVersions: []string{"[1.1.9,1.2.0),[1.2.5,2.0.0),[2.1.0,)"}, expected: "1.2.5"},
+		{impactedVersionPackage: "2.0.1", fixVersions: []string{"[1.2.5,2.0.0),[2.1.0,)", "[3.0.0]"}, expected: "2.1.0"},
+		{impactedVersionPackage: "1.2.3", fixVersions: []string{"(1.2.3,)"}, expected: ""},
 	}
 	for _, test := range tests {
 		t.Run(test.expected, func(t *testing.T) {
-			expected := getMinimalFixVersion(test.impactedVersionPackage, test.fixVersions)
+			expected := getMinimalFixVersion(coreutils.Maven, test.impactedVersionPackage, test.fixVersions)
 			assert.Equal(t, test.expected, expected)
 		})
 	}
 }
 
+func TestGetFixVersion(t *testing.T) {
+	fixVersions := []string{"[1.2.9]", "[1.3.1]", "[1.4.0]", "[2.0.1]", "[3.1.0]"}
+	tests := []struct {
+		strategy           string
+		impactedVersion    string
+		expectedFixVersion string
+		expectedCompliant  bool
+	}{
+		{strategy: utils.MinimalFixVersion, impactedVersion: "1.2.5", expectedFixVersion: "1.2.9", expectedCompliant: true},
+		{strategy: "", impactedVersion: "1.3.5", expectedFixVersion: "1.4.0", expectedCompliant: true},
+		{strategy: utils.SameMajorFixVersion, impactedVersion: "1.3.5", expectedFixVersion: "1.4.0", expectedCompliant: true},
+		{strategy: utils.SameMinorFixVersion, impactedVersion: "1.3.0", expectedFixVersion: "1.3.1", expectedCompliant: true},
+		// No fix version of the same minor version, so the minimal fix version is returned
+		{strategy: utils.SameMinorFixVersion, impactedVersion: "1.3.5", expectedFixVersion: "1.4.0", expectedCompliant: false},
+		{strategy: utils.SameMajorFixVersion, impactedVersion: "2.5.0", expectedFixVersion: "3.1.0", expectedCompliant: false},
+		{strategy: utils.LatestFixVersion, impactedVersion: "1.2.5", expectedFixVersion: "3.1.0", expectedCompliant: true},
+		{strategy: utils.LatestFixVersion, impactedVersion: "3.2.0", expectedFixVersion: "", expectedCompliant: true},
+	}
+	for _, test := range tests {
+		t.Run(test.strategy+"-"+test.impactedVersion, func(t *testing.T) {
+			fixVersion, compliant := getFixVersion(test.strategy, coreutils.Npm, test.impactedVersion, fixVersions)
+			assert.Equal(t, test.expectedFixVersion, fixVersion)
+			assert.Equal(t, test.expectedCompliant, compliant)
+		})
+	}
+}
+
+func TestNonCompliantFixVersions(t *testing.T) {
+	cfp := &CreateFixPullRequestsCmd{fixVersionStrategy: utils.SameMajorFixVersion}
+	vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+	for _, vulnerability := range []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", FixedVersions: []string{"[1.2.6]"}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "minimist"}}}},
+		{ImpactedDependencyName: "json5", ImpactedDependencyVersion: "1.0.1", FixedVersions: []string{"[2.2.2]"}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "json5"}}}},
+		// Both a compliant and a non-compliant vulnerability on the same package
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.15", FixedVersions: []string{"[4.17.21]"}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "lodash"}}}},
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.15", FixedVersions: []string{"[5.0.0]"}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "lodash"}}}},
+		{ImpactedDependencyName: "semver", ImpactedDependencyVersion: "5.7.1", FixedVersions: []string{"[6.3.1]"}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "semver"}}}},
+		{ImpactedDependencyName: "semver", ImpactedDependencyVersion: "5.7.1", FixedVersions: []string{"[5.7.2]"}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "semver"}}}},
+	} {
+		vulnerability := vulnerability
+		assert.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&vulnerability, vulnerabilitiesMap, map[string][]string{}))
+	}
+	cfp.setFixVersionsDetails(vulnerabilitiesMap, map[string][]string{})
+	assert.False(t, vulnerabilitiesMap["minimist"].NonCompliantFixVersion)
+	assert.True(t, vulnerabilitiesMap["json5"].NonCompliantFixVersion)
+	// The compliance is checked against the aggregated fix version, regardless of the order of the vulnerabilities
+	assert.Equal(t, "5.0.0", vulnerabilitiesMap["lodash"].SuggestedFixedVersion)
+	assert.True(t, vulnerabilitiesMap["lodash"].NonCompliantFixVersion)
+	assert.Equal(t, "6.3.1", vulnerabilitiesMap["semver"].SuggestedFixedVersion)
+	assert.True(t, vulnerabilitiesMap["semver"].NonCompliantFixVersion)
+
+	// A fix version that complies with the strategy isn't reported, even if it was set as non-compliant before the aggregation
+	vulnerabilitiesMap["semver"].SuggestedFixedVersion = "5.7.2"
+	cfp.setFixVersionsDetails(vulnerabilitiesMap, map[string][]string{})
+	assert.False(t, vulnerabilitiesMap["semver"].NonCompliantFixVersion)
+
+	assert.Empty(t, cfp.getNonCompliantFixVersionsNote(vulnerabilitiesMap["minimist"]))
+	note := cfp.getNonCompliantFixVersionsNote(vulnerabilitiesMap["minimist"], vulnerabilitiesMap["json5"])
+	assert.Contains(t, note, "'sameMajor' fix version strategy")
+	assert.Contains(t, note, "- json5: 1.0.1 → 2.2.2")
+	assert.NotContains(t, note, "minimist")
+}
+
+func TestUnfixedVulnerabilities(t *testing.T) {
+	cfp := &CreateFixPullRequestsCmd{}
+	vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+	unfixedVulnerabilities := map[string][]string{}
+	for _, vulnerability := range []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", FixedVersions: []string{"(1.2.5,)"}, IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2021-44906"}}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "minimist"}}}},
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", FixedVersions: []string{"[1.2.6]"}, IssueId: "XRAY-2", ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "minimist"}}}},
+		{ImpactedDependencyName: "json5", ImpactedDependencyVersion: "1.0.1", FixedVersions: []string{"(,0.5.0]"}, IssueId: "XRAY-3", ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "json5"}}}},
+	} {
+		vulnerability := vulnerability
+		assert.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&vulnerability, vulnerabilitiesMap, unfixedVulnerabilities))
+	}
+	// json5 has no fix version to update to, so it isn't fixed
+	assert.NotContains(t, vulnerabilitiesMap, "json5")
+	assert.Equal(t, []string{"XRAY-3 in json5:1.0.1 (fixed versions: (,0.5.0])"}, unfixedVulnerabilities["json5"])
+	assert.Equal(t, []string{"CVE-2021-44906 in minimist:1.2.5 (fixed versions: (1.2.5,))"}, unfixedVulnerabilities["minimist"])
+
+	assert.Empty(t, getUnfixedVulnerabilitiesNote(vulnerabilitiesMap["minimist"]))
+	vulnerabilitiesMap["minimist"].UnfixedVulnerabilities = unfixedVulnerabilities["minimist"]
+	assert.Contains(t, getUnfixedVulnerabilitiesNote(vulnerabilitiesMap["minimist"]), "- CVE-2021-44906 in minimist:1.2.5 (fixed versions: (1.2.5,))")
+}
+
 func TestCreateVulnerabilitiesMap(t *testing.T) {
 	cfp := &CreateFixPullRequestsCmd{}
 
@@ -635,7 +699,7 @@ func TestPreparePullRequestDetails(t *testing.T) {
 		},
 	}
 	expectedPrBody := "[![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/v2/vulnerabilitiesFixBannerPR.png)](https://github.com/jfrog/frogbot#readme)\n\n## 📦 Vulnerable Dependencies \n\n### ✍️ Summary\n\n<div align=\"center\">\n\n\n| SEVERITY                | DIRECT DEPENDENCIES                  | IMPACTED DEPENDENCY                   | FIXED VERSIONS                       |\n| :---------------------: | :----------------------------------: | :-----------------------------------: | :---------------------------------: | \n| ![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/v2/applicableHighSeverity.png)<br>    High |  | package1:1.0.0 | 1.0.0<br><br>2.0.0 |\n\n</div>\n\n## 👇 Details\n\n\n\n\n- **Severity** 🔥 High\n- **Package Name:** package1\n- **Current Version:** 1.0.0\n- **Fixed Versions:** 1.0.0,2.0.0\n- **CVE:** CVE-2022-1234\n\n**Description:**\n\nsummary\n\n\n\n\n---\n\n<div align=\"center\">\n\n**Frogbot** also supports **Contextual Analysis**. This feature is included as part of the [JFrog Advanced Security](https://jfrog.com/xray/) package, which isn't enabled on your system.\n\n</div>\n\n<div align=\"center\">\n\n[JFrog Frogbot](https://github.com/jfrog/frogbot#readme)\n\n</div>\n"
-	prTitle, prBody := cfp.preparePullRequestDetails("hash", vulnerabilities)
+	prTitle, prBody := cfp.preparePullRequestDetails("hash", vulnerabilities, "")
 	assert.Equal(t, "[🐸 Frogbot] Update version of package1 to 1.0.0", prTitle)
 	assert.Equal(t, expectedPrBody, prBody)
 	vulnerabilities = append(vulnerabilities, formats.VulnerabilityOrViolationRow{
@@ -648,12 +712,12 @@ func TestPreparePullRequestDetails(t *testing.T) {
 	})
 	cfp.aggregateFixes = true
 	expectedPrBody = "[![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/v2/vulnerabilitiesFixBannerPR.png)](https://github.com/jfrog/frogbot#readme)\n\n## 📦 Vulnerable Dependencies \n\n### ✍️ Summary\n\n<div align=\"center\">\n\n\n| SEVERITY                | DIRECT DEPENDENCIES                  | IMPACTED DEPENDENCY                   | FIXED VERSIONS                       |\n| :---------------------: | :----------------------------------: | :-----------------------------------: | :---------------------------------: | \n| ![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/v2/applicableHighSeverity.png)<br>    High |  | package1:1.0.0 | 1.0.0<br><br>2.0.0 |\n| ![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/v2/applicableCriticalSeverity.png)<br>Critical |  | package2:2.0.0 | 2.0.0<br><br>3.0.0 |\n\n</div>\n\n## 👇 Details\n\n\n<details>\n<summary> <b>package1 1.0.0</b> </summary>\n<br>\n\n- **Severity** 🔥 High\n- **Package Name:** package1\n- **Current Version:** 1.0.0\n- **Fixed Versions:** 1.0.0,2.0.0\n- **CVE:** CVE-2022-1234\n\n**Description:**\n\nsummary\n\n\n\n</details>\n\n\n<details>\n<summary> <b>package2 2.0.0</b> </summary>\n<br>\n\n- **Severity** 💀 Critical\n- **Package Name:** package2\n- **Current Version:** 2.0.0\n- **Fixed Versions:** 2.0.0,3.0.0\n- **CVE:** CVE-2022-4321\n\n**Description:**\n\nsummary\n\n\n\n</details>\n\n\n---\n\n<div align=\"center\">\n\n**Frogbot** also supports **Contextual Analysis**. This feature is included as part of the [JFrog Advanced Security](https://jfrog.com/xray/) package, which isn't enabled on your system.\n\n</div>\n\n<div align=\"center\">\n\n[JFrog Frogbot](https://github.com/jfrog/frogbot#readme)\n\n</div>\n\n[comment]: <> (Checksum: hash)\n"
-	prTitle, prBody = cfp.preparePullRequestDetails("hash", vulnerabilities)
+	prTitle, prBody = cfp.preparePullRequestDetails("hash", vulnerabilities, "")
 	assert.Equal(t, utils.GetAggregatedPullRequestTitle(""), prTitle)
 	assert.Equal(t, expectedPrBody, prBody)
 	cfp.OutputWriter = &utils.SimplifiedOutput{}
 	expectedPrBody = "**🚨 This automated pull request was created by Frogbot and fixes the below:**\n\n\n---\n## 📦 Vulnerable Dependencies\n---\n\n### ✍️ Summary \n\n\n| SEVERITY                | DIRECT DEPENDENCIES                  | IMPACTED DEPENDENCY                   | FIXED VERSIONS                       |\n| :---------------------: | :----------------------------------: | :-----------------------------------: | :---------------------------------: | \n| High |   | package1:1.0.0 | 1.0.0, 2.0.0 |\n| Critical |   | package2:2.0.0 | 2.0.0, 3.0.0 |\n\n---\n### 👇 Details\n---\n\n\n#### package1 1.0.0\n\n\n- **Severity** 🔥 High\n- **Package Name:** package1\n- **Current Version:** 1.0.0\n- **Fixed Versions:** 1.0.0,2.0.0\n- **CVE:** CVE-2022-1234\n\n**Description:**\n\nsummary\n\n\n\n\n#### package2 2.0.0\n\n\n- **Severity** 💀 Critical\n- **Package Name:** package2\n- **Current Version:** 2.0.0\n- **Fixed Versions:** 2.0.0,3.0.0\n- **CVE:** CVE-2022-4321\n\n**Description:**\n\nsummary\n\n\n\n\n---\n\n\n**Frogbot** also supports **Contextual Analysis**. This feature is included as part of the [JFrog Advanced Security](https://jfrog.com/xray/) package, which isn't enabled on your system.\n\n[JFrog Frogbot](https://github.com/jfrog/frogbot#readme)\n[comment]: <> (Checksum: hash)\n"
-	prTitle, prBody = cfp.preparePullRequestDetails("hash", vulnerabilities)
+	prTitle, prBody = cfp.preparePullRequestDetails("hash", vulnerabilities, "")
 	assert.Equal(t, utils.GetAggregatedPullRequestTitle(""), prTitle)
 	assert.Equal(t, expectedPrBody, prBody)
 }
@@ -686,3 +750,162 @@ func verifyDependencyFileDiff(baseBranch string, fixBranch string, packageDescri
 	}
 	return
 }
+
+func TestPrioritizeFixes(t *testing.T) {
+	newVulnDetails := func(name string, severity, applicable string) *utils.VulnerabilityDetails {
+		return utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyName: name,
+			SeverityNumValue:       xrayutils.GetSeverity(severity, applicable).NumValue(),
+		}, "1.0.0")
+	}
+	fixes := prioritizeFixes(map[string]map[string]*utils.VulnerabilityDetails{
+		"/repo/frontend": {
+			"minimist": newVulnDetails("minimist", "Medium", xrayutils.ApplicableStringValue),
+			"lodash":   newVulnDetails("lodash", "Critical", xrayutils.NotApplicableStringValue),
+		},
+		"/repo/backend": {
+			"express": newVulnDetails("express", "High", xrayutils.ApplicabilityUndeterminedStringValue),
+			"axios":   newVulnDetails("axios", "High", xrayutils.ApplicableStringValue),
+		},
+	})
+	var names []string
+	for _, fix := range fixes {
+		names = append(names, fix.vulnDetails.ImpactedDependencyName)
+	}
+	// Applicable vulnerabilities precede the vulnerabilities of the same severity, and not applicable vulnerabilities follow all the others
+	assert.Equal(t, []string{"axios", "express", "minimist", "lodash"}, names)
+	assert.Equal(t, "/repo/backend", fixes[0].fullProjectPath)
+}
+
+func TestGetRemainingPullRequests(t *testing.T) {
+	vulnerabilitiesMap := map[string]map[string]*utils.VulnerabilityDetails{
+		"/repo": {
+			"lodash":   utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "lodash"}, "4.17.21"),
+			"minimist": utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist"}, "1.2.6"),
+		},
+	}
+	tests := []struct {
+		name                string
+		repositoryLimit     int
+		projectLimit        int
+		expectedRemaining   int
+		expectListRequested bool
+	}{
+		{name: "unlimited", expectedRemaining: -1},
+		{name: "repositoryLimit", repositoryLimit: 5, expectedRemaining: 2, expectListRequested: true},
+		{name: "projectLimit", projectLimit: 2, expectedRemaining: 1, expectListRequested: true},
+		{name: "bothLimits", repositoryLimit: 4, projectLimit: 3, expectedRemaining: 1, expectListRequested: true},
+		{name: "exceededLimit", repositoryLimit: 1, projectLimit: 3, expectedRemaining: 0, expectListRequested: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := &utils.Repository{Params: utils.Params{Git: utils.Git{ClientInfo: utils.ClientInfo{RepoName: "frogbot"}, MaxOpenPullRequests: test.repositoryLimit}}}
+			project := &utils.Project{MaxOpenPullRequests: test.projectLimit}
+			client := mockVcsClient(t)
+			details := utils.NewProjectScanDetails(client, repository, project).SetBranch("main")
+			gitManager, err := utils.NewGitManager(true, "", "", "origin", "", "", &repository.Git)
+			require.NoError(t, err)
+			lodashFixBranch, err := gitManager.GenerateFixBranchName("main", "lodash", "4.17.21")
+			require.NoError(t, err)
+			expressFixBranch, err := gitManager.GenerateFixBranchName("main", "express", "4.18.2")
+			require.NoError(t, err)
+			projectMetadata := &utils.FixPullRequestMetadata{ImpactedDependencyName: "lodash", FixVersion: "4.17.21", WorkingDir: utils.RootDir}
+			otherProjectMetadata := &utils.FixPullRequestMetadata{ImpactedDependencyName: "express", FixVersion: "4.18.2", WorkingDir: "backend"}
+			if test.expectListRequested {
+				client.EXPECT().ListOpenPullRequestsWithBody(context.Background(), "", "frogbot").Return([]vcsclient.PullRequestInfo{
+					// A Frogbot pull request of the project
+					{ID: 1, Source: vcsclient.BranchInfo{Name: lodashFixBranch}, Target: vcsclient.BranchInfo{Name: "main"}, Body: projectMetadata.ToMarkdownComment()},
+					// Frogbot pull requests of other projects
+					{ID: 2, Source: vcsclient.BranchInfo{Name: expressFixBranch}, Target: vcsclient.BranchInfo{Name: "main"}, Body: otherProjectMetadata.ToMarkdownComment()},
+					// A Frogbot pull request with a branch that doesn't match the template
+					{ID: 3, Source: vcsclient.BranchInfo{Name: "security-fixes"}, Body: utils.CommentGeneratedByFrogbot},
+					{ID: 4, Source: vcsclient.BranchInfo{Name: "feature"}, Body: "New feature"},
+				}, nil)
+			}
+			cfp := CreateFixPullRequestsCmd{details: details, gitManager: gitManager, dryRun: true, baseWd: "/repo"}
+			openPullRequests, err := cfp.listOpenPullRequestsIfNeeded()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRemaining, cfp.getRemainingPullRequests(vulnerabilitiesMap, openPullRequests))
+		})
+	}
+}
+
+func TestCloseSupersededAndResolvedPullRequests(t *testing.T) {
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{ClientInfo: utils.ClientInfo{RepoName: "frogbot", GitProvider: vcsutils.GitHub}}}}
+	client := mockVcsClient(t)
+	details := utils.NewProjectScanDetails(client, repository, &utils.Project{}).SetBranch("main")
+	gitManager, err := utils.NewGitManager(true, "", "", "origin", "", "", &repository.Git)
+	require.NoError(t, err)
+	cfp := CreateFixPullRequestsCmd{details: details, gitManager: gitManager, baseWd: "/repo"}
+
+	newFixBranch, err := gitManager.GenerateFixBranchName("main", "lodash", "4.17.21")
+	require.NoError(t, err)
+	newPullRequestMetadata := utils.FixPullRequestMetadata{ImpactedDependencyName: "lodash", FixVersion: "4.17.21", WorkingDir: "frontend"}
+	oldPullRequestMetadata := utils.FixPullRequestMetadata{ImpactedDependencyName: "lodash", FixVersion: "4.17.20", WorkingDir: "frontend"}
+	resolvedPullRequestMetadata := utils.FixPullRequestMetadata{ImpactedDependencyName: "minimist", FixVersion: "1.2.6", WorkingDir: "frontend"}
+	otherProjectMetadata := utils.FixPullRequestMetadata{ImpactedDependencyName: "minimist", FixVersion: "1.2.6", WorkingDir: "backend"}
+	openPullRequests := []vcsclient.PullRequestInfo{
+		{ID: 1, Body: oldPullRequestMetadata.ToMarkdownComment(), Source: vcsclient.BranchInfo{Name: "frogbot-lodash-old"}, Target: vcsclient.BranchInfo{Name: "main"}},
+		{ID: 2, Body: newPullRequestMetadata.ToMarkdownComment(), Source: vcsclient.BranchInfo{Name: newFixBranch}, Target: vcsclient.BranchInfo{Name: "main"}},
+		{ID: 3, Body: resolvedPullRequestMetadata.ToMarkdownComment(), Source: vcsclient.BranchInfo{Name: "frogbot-minimist"}, Target: vcsclient.BranchInfo{Name: "main"}},
+		// A fix of another project, which wasn't scanned
+		{ID: 4, Body: otherProjectMetadata.ToMarkdownComment(), Source: vcsclient.BranchInfo{Name: "frogbot-minimist-backend"}, Target: vcsclient.BranchInfo{Name: "main"}},
+		// A fix of another base branch
+		{ID: 5, Body: oldPullRequestMetadata.ToMarkdownComment(), Source: vcsclient.BranchInfo{Name: "frogbot-lodash-dev"}, Target: vcsclient.BranchInfo{Name: "dev"}},
+		{ID: 6, Body: "New feature", Source: vcsclient.BranchInfo{Name: "feature"}, Target: vcsclient.BranchInfo{Name: "main"}},
+	}
+
+	// The old lodash fix is superseded by the new pull request
+	client.EXPECT().ListOpenPullRequestsWithBody(context.Background(), "", "frogbot").Return(openPullRequests, nil)
+	client.EXPECT().AddPullRequestComment(context.Background(), "", "frogbot", "This pull request was superseded by #2, which updates lodash to version 4.17.21.", 1).Return(nil)
+	client.EXPECT().UpdatePullRequest(context.Background(), "", "frogbot", gitManager.GeneratePullRequestTitle("lodash", "4.17.20"), openPullRequests[0].Body, "main", 1, vcsutils.Closed).Return(nil)
+	lodashDetails := utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "lodash"}, "4.17.21")
+	superseded, err := cfp.closeSupersededPullRequests(projectFix{fullProjectPath: filepath.Join("/repo", "frontend"), vulnDetails: lodashDetails}, openPullRequests)
+	assert.NoError(t, err)
+	assert.True(t, superseded)
+
+	// The minimist fix is resolved, since minimist is no longer vulnerable in the scanned project
+	client.EXPECT().AddPullRequestComment(context.Background(), "", "frogbot", "Closing this pull request, as minimist is no longer reported as vulnerable in the main branch.", 3).Return(nil)
+	client.EXPECT().UpdatePullRequest(context.Background(), "", "frogbot", gitManager.GeneratePullRequestTitle("minimist", "1.2.6"), openPullRequests[2].Body, "main", 3, vcsutils.Closed).Return(nil)
+	assert.NoError(t, cfp.closeResolvedPullRequests(map[string]map[string]*utils.VulnerabilityDetails{filepath.Join("/repo", "frontend"): {"lodash": lodashDetails}}, openPullRequests[1:]))
+}
+
+func TestCommentOutOfDatePullRequest(t *testing.T) {
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{ClientInfo: utils.ClientInfo{RepoName: "frogbot"}}}}
+	client := mockVcsClient(t)
+	cfp := CreateFixPullRequestsCmd{details: utils.NewProjectScanDetails(client, repository, &utils.Project{}).SetBranch("main")}
+	pullRequest := &vcsclient.PullRequestInfo{ID: 3}
+
+	// The pull request is commented on once
+	client.EXPECT().ListPullRequestComments(context.Background(), "", "frogbot", 3).Return([]vcsclient.CommentInfo{{Content: "LGTM"}}, nil)
+	expectedComment := "The main branch has changed since this pull request was created. Frogbot didn't recreate the fix, since the pull request includes commits that weren't made by Frogbot. Please update the branch with the latest changes of main." + utils.MarkdownComment(outOfDateFixBranchCommentMarker)
+	client.EXPECT().AddPullRequestComment(context.Background(), "", "frogbot", expectedComment, 3).Return(nil)
+	assert.NoError(t, cfp.commentOutOfDatePullRequest(pullRequest))
+
+	client.EXPECT().ListPullRequestComments(context.Background(), "", "frogbot", 3).Return([]vcsclient.CommentInfo{{Content: expectedComment}}, nil)
+	assert.NoError(t, cfp.commentOutOfDatePullRequest(pullRequest))
+}
+
+// packageJsonUpdater updates the dependencies by rewriting package.json, without running the package manager
+type packageJsonUpdater struct{}
+
+func (pu *packageJsonUpdater) UpdateDependency(vulnDetails *utils.VulnerabilityDetails) error {
+	return os.WriteFile("package.json", []byte(fmt.Sprintf(`{"dependencies": {"%s": "%s"}}`, vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion)), 0600)
+}
+
+func TestAggregateFixAndOpenPullRequestFailure(t *testing.T) {
+	gitManager := newVerificationTestRepository(t)
+	baseWd, err := os.Getwd()
+	require.NoError(t, err)
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{ClientInfo: utils.ClientInfo{RepoName: "frogbot"}}}}
+	details := utils.NewProjectScanDetails(mockVcsClient(t), repository, &utils.Project{}).SetBranch("master")
+	cfp := CreateFixPullRequestsCmd{details: details, gitManager: gitManager, baseWd: baseWd, aggregateFixes: true,
+		handlers: map[coreutils.Technology]packagehandlers.PackageHandler{coreutils.Npm: &packageJsonUpdater{}}}
+	vulnerabilitiesMap := map[string]map[string]*utils.VulnerabilityDetails{
+		baseWd: {"minimist": utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", Technology: coreutils.Npm}, "1.2.6")},
+	}
+	// The repository has no remote, so pushing the fix branch fails
+	err = cfp.aggregateFixAndOpenPullRequest(vulnerabilitiesMap, "frogbot-update-npm-dependencies", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed while creating aggregated pull request: ")
+}
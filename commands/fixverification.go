@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/gofrog/datastructures"
+	audit "github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/generic"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// The number of output lines of a failed verify command that are attached to the pull request, counted from the end of the output
+	verifyCommandOutputMaxLines = 50
+	// The time to wait for the output of a verify command to close, after the command is stopped
+	verifyCommandWaitDelay = 10 * time.Second
+)
+
+// fixVerification is the outcome of verifying the fixes of a project, after its vulnerable dependencies were updated.
+// A nil verification means that the fixes weren't verified.
+type fixVerification struct {
+	// The fixed dependencies whose vulnerable versions are still found by the rescan, as name:version
+	unresolved []string
+	// The issues found by the rescan, which weren't found before the fixes
+	introduced []formats.VulnerabilityOrViolationRow
+	// The errors that prevented verifying the fixes
+	errors []string
+	// The verify commands of the projects that failed after the fixes
+	failedCommands []failedVerifyCommand
+}
+
+type failedVerifyCommand struct {
+	command string
+	err     string
+	output  string
+}
+
+func (fv *fixVerification) failed() bool {
+	return fv != nil && (len(fv.unresolved) > 0 || len(fv.introduced) > 0 || len(fv.errors) > 0 || len(fv.failedCommands) > 0)
+}
+
+// merge adds the outcome of verifying the fixes of another project to the verification.
+func (fv *fixVerification) merge(other *fixVerification) *fixVerification {
+	if fv == nil {
+		return other
+	}
+	if other != nil {
+		fv.unresolved = append(fv.unresolved, other.unresolved...)
+		fv.introduced = append(fv.introduced, other.introduced...)
+		fv.errors = append(fv.errors, other.errors...)
+		fv.failedCommands = append(fv.failedCommands, other.failedCommands...)
+	}
+	return fv
+}
+
+// summary describes why the verification failed, in a single line per reason.
+func (fv *fixVerification) summary() string {
+	var reasons []string
+	for _, dependency := range fv.unresolved {
+		reasons = append(reasons, fmt.Sprintf("The vulnerable version of %s is still found after the fix", dependency))
+	}
+	for _, issue := range fv.introduced {
+		reasons = append(reasons, fmt.Sprintf("The fix introduces %s %s in %s:%s", issue.Severity, issue.IssueId, issue.ImpactedDependencyName, issue.ImpactedDependencyVersion))
+	}
+	reasons = append(reasons, fv.errors...)
+	for _, failedCommand := range fv.failedCommands {
+		reasons = append(reasons, fmt.Sprintf("The verify command '%s' failed: %s", failedCommand.command, failedCommand.err))
+	}
+	return strings.Join(reasons, "\n")
+}
+
+// toMarkdown returns the warning added to the pull request of fixes that failed the verification, or an empty string if they passed it.
+func (fv *fixVerification) toMarkdown() string {
+	if !fv.failed() {
+		return ""
+	}
+	var markdown strings.Builder
+	markdown.WriteString("\n**⚠️ Warning:** Frogbot couldn't verify this fix, so it may be incomplete or break the project. Please review it carefully.\n")
+	for _, reason := range strings.Split(fv.summary(), "\n") {
+		markdown.WriteString("- " + reason + "\n")
+	}
+	for _, failedCommand := range fv.failedCommands {
+		if failedCommand.output == "" {
+			continue
+		}
+		markdown.WriteString(fmt.Sprintf("\n<details>\n<summary>The output of '%s'</summary>\n\n```\n%s\n```\n\n</details>\n", failedCommand.command, failedCommand.output))
+	}
+	return markdown.String()
+}
+
+// storePreFixResults keeps the scan results of the project's working directory, to compare them with the results of rescanning it after it's fixed.
+func (cfp *CreateFixPullRequestsCmd) storePreFixResults(fullProjectPath string, scanResults *audit.Results) {
+	if !cfp.details.Git.VerifyFixes {
+		return
+	}
+	if cfp.preFixResults == nil {
+		cfp.preFixResults = map[string]*audit.Results{}
+	}
+	cfp.preFixResults[cfp.getProjectWorkingDir(fullProjectPath)] = scanResults
+}
+
+// verifyFixes verifies the committed fixes of the project in the current directory.
+// If the fixes are verified by a rescan, they pass it if the vulnerable versions of the fixed dependencies aren't found anymore, and no new issues are found.
+// If the project has a verify command, such as 'npm test', the fixes pass it if the command succeeds.
+// The worktree is reset after the verification, to remove the files it created, such as build outputs.
+// Returns nil if the fixes aren't verified.
+func (cfp *CreateFixPullRequestsCmd) verifyFixes(projectWorkingDir string, fixedVulnerabilities ...*utils.VulnerabilityDetails) (*fixVerification, error) {
+	preFixResults, rescan := cfp.preFixResults[projectWorkingDir]
+	verifyCommand := strings.TrimSpace(cfp.details.Project.VerifyCommand)
+	if (!rescan && verifyCommand == "") || len(fixedVulnerabilities) == 0 {
+		return nil, nil
+	}
+	verification := &fixVerification{}
+	if rescan {
+		verification.rescan(cfp.details, preFixResults, fixedVulnerabilities)
+	}
+	if verifyCommand != "" {
+		verification.runVerifyCommand(verifyCommand, cfp.details.Project.GetVerifyCommandTimeout())
+	}
+	if verification.failed() {
+		log.Warn("The fixes failed the verification:\n" + verification.summary())
+	} else {
+		log.Info("The fixes passed the verification")
+	}
+	return verification, cfp.gitManager.ResetWorktree()
+}
+
+// verifyAggregatedFixes verifies the committed fixes of each of the project's working directories, and merges the outcomes.
+func (cfp *CreateFixPullRequestsCmd) verifyAggregatedFixes(fixesByPath map[string][]*utils.VulnerabilityDetails) (verification *fixVerification, err error) {
+	for fullPath, fixes := range fixesByPath {
+		var current *fixVerification
+		if current, err = cfp.verifyProjectFixes(fullPath, fixes); err != nil {
+			return
+		}
+		verification = verification.merge(current)
+	}
+	return
+}
+
+func (cfp *CreateFixPullRequestsCmd) verifyProjectFixes(fullProjectPath string, fixes []*utils.VulnerabilityDetails) (verification *fixVerification, err error) {
+	if projectWorkingDir := utils.GetRelativeWd(fullProjectPath, cfp.baseWd); projectWorkingDir != "" {
+		var restoreDir func() error
+		if restoreDir, err = utils.Chdir(projectWorkingDir); err != nil {
+			return
+		}
+		defer func() {
+			err = errors.Join(err, restoreDir())
+		}()
+	}
+	return cfp.verifyFixes(cfp.getProjectWorkingDir(fullProjectPath), fixes...)
+}
+
+// rescan scans the project in the current directory again, and compares the results with the results before the fixes.
+func (fv *fixVerification) rescan(scanDetails *utils.ScanDetails, preFixResults *audit.Results, fixedVulnerabilities []*utils.VulnerabilityDetails) {
+	log.Info("Rescanning the project to verify the fixes...")
+	currentDir, err := os.Getwd()
+	if err != nil {
+		fv.errors = append(fv.errors, "Rescanning the project failed: "+err.Error())
+		return
+	}
+	postFixResults, err := runInstallAndAudit(scanDetails, currentDir)
+	if err != nil {
+		fv.errors = append(fv.errors, "Rescanning the project failed: "+err.Error())
+		return
+	}
+	if err = fv.compare(preFixResults, postFixResults, fixedVulnerabilities); err != nil {
+		fv.errors = append(fv.errors, "Comparing the scan results failed: "+err.Error())
+	}
+}
+
+// runVerifyCommand runs the verify command of the project in the current directory, and keeps its output if it fails or doesn't complete within the timeout.
+// The command is split by whitespaces and run without a shell.
+func (fv *fixVerification) runVerifyCommand(verifyCommand string, timeout time.Duration) {
+	log.Info(fmt.Sprintf("Running '%s' to verify the fixes...", verifyCommand))
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	parts := strings.Fields(verifyCommand)
+	command := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	// Processes the command starts may keep its output open after it's stopped
+	command.WaitDelay = verifyCommandWaitDelay
+	output, err := command.CombinedOutput()
+	if err == nil {
+		return
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("the command didn't complete within %s", timeout)
+	}
+	log.Debug(fmt.Sprintf("The output of '%s':\n%s", verifyCommand, output))
+	fv.failedCommands = append(fv.failedCommands, failedVerifyCommand{command: verifyCommand, err: err.Error(), output: getOutputTail(string(output), verifyCommandOutputMaxLines)})
+}
+
+// getOutputTail returns the last lines of a command output, which usually explain why the command failed.
+func getOutputTail(output string, maxLines int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) <= maxLines {
+		return strings.Join(lines, "\n")
+	}
+	return fmt.Sprintf("... (%d lines omitted)\n", len(lines)-maxLines) + strings.Join(lines[len(lines)-maxLines:], "\n")
+}
+
+// compare finds the fixed dependencies whose vulnerable versions are still found after the fixes, and the issues introduced by the fixes.
+func (fv *fixVerification) compare(preFixResults, postFixResults *audit.Results, fixedVulnerabilities []*utils.VulnerabilityDetails) (err error) {
+	postFixRows, err := getScanVulnerabilitiesRows(postFixResults)
+	if err != nil {
+		return
+	}
+	vulnerableVersions := datastructures.MakeSet[string]()
+	for _, vulnerability := range fixedVulnerabilities {
+		vulnerableVersions.Add(vulnerability.ImpactedDependencyName + ":" + vulnerability.ImpactedDependencyVersion)
+	}
+	unresolved := datastructures.MakeSet[string]()
+	for _, row := range postFixRows {
+		if dependency := row.ImpactedDependencyName + ":" + row.ImpactedDependencyVersion; vulnerableVersions.Exists(dependency) && !unresolved.Exists(dependency) {
+			unresolved.Add(dependency)
+			fv.unresolved = append(fv.unresolved, dependency)
+		}
+	}
+	fv.introduced, err = createNewIssuesRows(preFixResults, postFixResults)
+	return
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"errors"
+	"github.com/go-git/go-git/v5"
+	"github.com/jfrog/frogbot/commands/utils"
+	audit "github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/generic"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newVerificationScanResults(vulnerabilities ...services.Vulnerability) *audit.Results {
+	return &audit.Results{ExtendedScanResults: &xrayutils.ExtendedScanResults{XrayResults: []services.ScanResponse{{Vulnerabilities: vulnerabilities}}}}
+}
+
+func TestFixVerificationCompare(t *testing.T) {
+	preFixResults := newVerificationScanResults(
+		services.Vulnerability{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"npm://minimist:1.2.5": {}}},
+		services.Vulnerability{IssueId: "XRAY-2", Severity: "Low", Components: map[string]services.Component{"npm://json5:1.0.1": {}}},
+	)
+	fixedVulnerabilities := []*utils.VulnerabilityDetails{
+		utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}, "1.2.6"),
+		utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "json5", ImpactedDependencyVersion: "1.0.1"}, "2.2.2"),
+	}
+
+	// The fixes resolve both vulnerabilities
+	verification := &fixVerification{}
+	require.NoError(t, verification.compare(preFixResults, newVerificationScanResults(), fixedVulnerabilities))
+	assert.False(t, verification.failed())
+	assert.Empty(t, verification.toMarkdown())
+
+	// The vulnerable version of json5 is still found, and the fix of minimist introduces a new vulnerability
+	verification = &fixVerification{}
+	require.NoError(t, verification.compare(preFixResults, newVerificationScanResults(
+		services.Vulnerability{IssueId: "XRAY-2", Severity: "Low", Components: map[string]services.Component{"npm://json5:1.0.1": {}}},
+		services.Vulnerability{IssueId: "XRAY-3", Severity: "Critical", Components: map[string]services.Component{"npm://minimist:1.2.6": {}}},
+	), fixedVulnerabilities))
+	assert.True(t, verification.failed())
+	assert.Equal(t, []string{"json5:1.0.1"}, verification.unresolved)
+	require.Len(t, verification.introduced, 1)
+	assert.Equal(t, "XRAY-3", verification.introduced[0].IssueId)
+	assert.Equal(t, "The vulnerable version of json5:1.0.1 is still found after the fix\nThe fix introduces Critical XRAY-3 in minimist:1.2.6", verification.summary())
+	assert.Contains(t, verification.toMarkdown(), "- The fix introduces Critical XRAY-3 in minimist:1.2.6\n")
+}
+
+func TestFixVerificationMerge(t *testing.T) {
+	var verification *fixVerification
+	assert.False(t, verification.failed())
+	assert.Empty(t, verification.toMarkdown())
+
+	verification = verification.merge(&fixVerification{})
+	assert.False(t, verification.failed())
+	verification = verification.merge(nil).merge(&fixVerification{errors: []string{"Rescanning the project failed: " + errors.New("npm install failed").Error()}})
+	assert.True(t, verification.failed())
+	assert.Equal(t, "Rescanning the project failed: npm install failed", verification.summary())
+}
+
+func TestVerifyFixesDisabled(t *testing.T) {
+	cfp := &CreateFixPullRequestsCmd{details: &utils.ScanDetails{Git: &utils.Git{}, Project: &utils.Project{}}}
+	cfp.storePreFixResults("/repo", newVerificationScanResults())
+	assert.Nil(t, cfp.preFixResults)
+	verification, err := cfp.verifyFixes(utils.RootDir, utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist"}, "1.2.6"))
+	assert.NoError(t, err)
+	assert.Nil(t, verification)
+}
+
+// newVerificationTestRepository creates a repository with a single commit, and changes the current directory to it until the test ends.
+func newVerificationTestRepository(t *testing.T) *utils.GitManager {
+	repoPath := t.TempDir()
+	_, err := git.PlainInit(repoPath, false)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "package.json"), []byte("{}"), 0600))
+	gitManager, err := utils.NewGitManager(false, "", repoPath, "origin", "", "", &utils.Git{AuthorName: "Frogbot", EmailAuthor: "frogbot@jfrog.com"})
+	require.NoError(t, err)
+	require.NoError(t, gitManager.AddAllAndCommit("Upgrade dependencies"))
+	restoreDir, err := utils.Chdir(repoPath)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, restoreDir())
+	})
+	return gitManager
+}
+
+func TestVerifyFixesWithVerifyCommand(t *testing.T) {
+	fixedVulnerability := utils.NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}, "1.2.6")
+	cfp := &CreateFixPullRequestsCmd{details: &utils.ScanDetails{Git: &utils.Git{}, Project: &utils.Project{VerifyCommand: "mkdir target"}}, gitManager: newVerificationTestRepository(t)}
+	verification, err := cfp.verifyFixes(utils.RootDir, fixedVulnerability)
+	require.NoError(t, err)
+	require.NotNil(t, verification)
+	assert.False(t, verification.failed())
+	// The outputs of the verification are removed, so they aren't committed with the next fix
+	assert.NoDirExists(t, "target")
+
+	cfp.details.Project.VerifyCommand = "go no-such-command"
+	verification, err = cfp.verifyFixes(utils.RootDir, fixedVulnerability)
+	require.NoError(t, err)
+	assert.True(t, verification.failed())
+	require.Len(t, verification.failedCommands, 1)
+	assert.Equal(t, "go no-such-command", verification.failedCommands[0].command)
+	assert.Contains(t, verification.failedCommands[0].output, "no-such-command")
+	assert.Contains(t, verification.summary(), "The verify command 'go no-such-command' failed: exit status")
+	assert.Contains(t, verification.toMarkdown(), "<summary>The output of 'go no-such-command'</summary>")
+}
+
+func TestRunVerifyCommandTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("The sleep executable wasn't found")
+	}
+	verification := &fixVerification{}
+	verification.runVerifyCommand("sleep 10", 100*time.Millisecond)
+	require.Len(t, verification.failedCommands, 1)
+	assert.Equal(t, "the command didn't complete within 100ms", verification.failedCommands[0].err)
+}
+
+func TestGetOutputTail(t *testing.T) {
+	assert.Equal(t, "line 1\nline 2", getOutputTail("line 1\nline 2\n", 2))
+	assert.Equal(t, "... (2 lines omitted)\nline 3\nline 4", getOutputTail("line 1\nline 2\nline 3\nline 4", 2))
+}
@@ -0,0 +1,402 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	ciProviderFlag     = "ci"
+	gitProviderFlag    = "git-provider"
+	repoNameFlag       = "repo-name"
+	branchFlag         = "branch"
+	acceptDefaultsFlag = "yes"
+	forceFlag          = "force"
+
+	defaultInitBranch = "master"
+)
+
+type ciProvider string
+
+const (
+	githubActions  ciProvider = "github-actions"
+	gitlabCi       ciProvider = "gitlab-ci"
+	azurePipelines ciProvider = "azure-pipelines"
+	jenkins        ciProvider = "jenkins"
+	jfrogPipelines ciProvider = "jfrog-pipelines"
+)
+
+var supportedCiProviders = []string{string(githubActions), string(gitlabCi), string(azurePipelines), string(jenkins), string(jfrogPipelines)}
+
+// Git providers which can run Frogbot from a CI server that isn't tied to a specific provider, such as Jenkins
+var supportedInitGitProviders = []string{string(utils.GitHub), string(utils.GitLab), string(utils.BitbucketServer), string(utils.BitbucketCloud), string(utils.AzureRepos), string(utils.Gitea)}
+
+// The templates use custom delimiters, since the generated files include ${{ }} and {{ }} expressions of the CI servers
+const (
+	initTemplateLeftDelim  = "{%"
+	initTemplateRightDelim = "%}"
+)
+
+//go:embed resources/init
+var initTemplates embed.FS
+
+type pipelineFile struct {
+	// Path relative to the repository root
+	path     string
+	template string
+}
+
+var pipelineFiles = map[ciProvider][]pipelineFile{
+	githubActions: {
+		{path: filepath.Join(".github", "workflows", "frogbot-scan-pull-request.yml"), template: "github-actions-scan-pr.yml.tmpl"},
+		{path: filepath.Join(".github", "workflows", "frogbot-scan-and-fix.yml"), template: "github-actions-scan-and-fix.yml.tmpl"},
+	},
+	gitlabCi:       {{path: ".gitlab-ci.yml", template: "gitlab-ci.yml.tmpl"}},
+	azurePipelines: {{path: "frogbot-azure-pipelines.yml", template: "azure-pipelines.yml.tmpl"}},
+	jenkins:        {{path: "Jenkinsfile", template: "Jenkinsfile.tmpl"}},
+	jfrogPipelines: {{path: "frogbot-pipelines.yml", template: "jfrog-pipelines.yml.tmpl"}},
+}
+
+// InitCmd detects the projects in a repository and generates the frogbot-config.yml file, along with the CI pipeline files that run Frogbot.
+// Unlike the other commands, it runs locally and requires no JFrog Platform or Git provider details.
+type InitCmd struct {
+	// The root directory of the repository
+	BaseDir     string
+	CiProvider  string
+	GitProvider string
+	RepoName    string
+	Branch      string
+	// Don't prompt, and use the default answers for all questions
+	AcceptDefaults bool
+	// Overwrite existing files
+	Force  bool
+	Input  io.Reader
+	Output io.Writer
+	reader *bufio.Reader
+}
+
+// Template parameters of the generated files
+type initTemplateData struct {
+	RepoName     string
+	Branch       string
+	GitProvider  string
+	Projects     []utils.Project
+	Technologies []coreutils.Technology
+	// The ref checked out by the GitHub Actions workflow
+	CheckoutRef string
+}
+
+func (itd initTemplateData) HasTech(techs ...string) bool {
+	for _, tech := range techs {
+		if slices.Contains(itd.Technologies, coreutils.Technology(tech)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (itd initTemplateData) WithCheckoutRef(ref string) initTemplateData {
+	itd.CheckoutRef = ref
+	return itd
+}
+
+type pipelinesRuntime struct {
+	Language string
+	Version  string
+}
+
+// PipelinesRuntime returns the JFrog Pipelines runtime image that fits the repository technologies, or nil to use the default image
+func (itd initTemplateData) PipelinesRuntime() *pipelinesRuntime {
+	switch {
+	case itd.HasTech("npm", "yarn"):
+		return &pipelinesRuntime{Language: "node", Version: "16"}
+	case itd.HasTech("maven", "gradle"):
+		return &pipelinesRuntime{Language: "java", Version: "11"}
+	case itd.HasTech("go"):
+		return &pipelinesRuntime{Language: "go", Version: "1.19"}
+	case itd.HasTech("dotnet", "nuget"):
+		return &pipelinesRuntime{Language: "dotnet", Version: "6"}
+	}
+	return nil
+}
+
+func (ic *InitCmd) Run() (err error) {
+	if ic.BaseDir == "" {
+		ic.BaseDir = "."
+	}
+	if ic.BaseDir, err = filepath.Abs(ic.BaseDir); err != nil {
+		return
+	}
+	if ic.Input == nil {
+		ic.Input = os.Stdin
+	}
+	if ic.Output == nil {
+		ic.Output = os.Stdout
+	}
+	ic.reader = bufio.NewReader(ic.Input)
+
+	detectedDirs, err := utils.DetectProjectDirs(ic.BaseDir, utils.DefaultExcludedDirs)
+	if err != nil {
+		return
+	}
+	if len(detectedDirs) == 0 {
+		return fmt.Errorf("no projects of a supported technology were found in %s", ic.BaseDir)
+	}
+
+	data, err := ic.getTemplateData(detectedDirs)
+	if err != nil {
+		return
+	}
+	if len(data.Projects) == 0 {
+		return errors.New("no projects were selected, nothing to generate")
+	}
+
+	files, err := ic.renderFiles(data)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if err = ic.writeFile(file.path, file.content); err != nil {
+			return
+		}
+	}
+	log.Info("Frogbot is configured. Commit the generated files, and set the secrets described at the top of the pipeline files.")
+	return
+}
+
+func (ic *InitCmd) getTemplateData(detectedDirs []utils.DetectedProjectDir) (data initTemplateData, err error) {
+	if ic.CiProvider, err = ic.choose("Which CI server will run Frogbot?", supportedCiProviders, ic.CiProvider, string(githubActions)); err != nil {
+		return
+	}
+	if ic.GitProvider, err = ic.getGitProvider(); err != nil {
+		return
+	}
+	if ic.RepoName == "" {
+		if ic.RepoName, err = ic.ask("Repository name", filepath.Base(ic.BaseDir)); err != nil {
+			return
+		}
+	}
+	if ic.Branch == "" {
+		if ic.Branch, err = ic.ask("Branch to scan and fix", getCurrentBranch(ic.BaseDir)); err != nil {
+			return
+		}
+	}
+	data = initTemplateData{RepoName: ic.RepoName, Branch: ic.Branch, GitProvider: ic.GitProvider}
+
+	for _, project := range utils.ProjectsFromDetectedDirs(ic.BaseDir, detectedDirs) {
+		var include bool
+		if include, project.InstallCommand, err = ic.confirmProject(project, detectedDirs); err != nil {
+			return
+		}
+		if !include {
+			continue
+		}
+		data.Projects = append(data.Projects, project)
+		for _, detectedDir := range detectedDirs {
+			if !slices.Contains(project.WorkingDirs, detectedDir.WorkingDir) {
+				continue
+			}
+			for _, tech := range detectedDir.Technologies {
+				if !slices.Contains(data.Technologies, tech) {
+					data.Technologies = append(data.Technologies, tech)
+				}
+			}
+		}
+	}
+	return
+}
+
+// getGitProvider returns the Git provider implied by the CI server, or asks for it when the CI server works with any provider
+func (ic *InitCmd) getGitProvider() (string, error) {
+	switch ciProvider(ic.CiProvider) {
+	case githubActions:
+		return string(utils.GitHub), nil
+	case gitlabCi:
+		return string(utils.GitLab), nil
+	case azurePipelines:
+		return string(utils.AzureRepos), nil
+	}
+	return ic.choose("Which Git provider hosts the repository?", supportedInitGitProviders, ic.GitProvider, string(utils.GitHub))
+}
+
+func (ic *InitCmd) confirmProject(project utils.Project, detectedDirs []utils.DetectedProjectDir) (include bool, installCommand string, err error) {
+	var technologies []string
+	for _, detectedDir := range detectedDirs {
+		if slices.Contains(project.WorkingDirs, detectedDir.WorkingDir) {
+			for _, tech := range detectedDir.Technologies {
+				technologies = append(technologies, tech.ToFormal())
+			}
+		}
+	}
+	ic.printf("\nDetected a %s project in: %s\n", strings.Join(technologies, ", "), strings.Join(project.WorkingDirs, ", "))
+	if include, err = ic.confirm("Include this project?"); err != nil || !include {
+		return
+	}
+	installCommand, err = ic.ask("Command to install the project dependencies (leave empty if not needed)", project.InstallCommand)
+	return
+}
+
+type renderedFile struct {
+	path    string
+	content []byte
+}
+
+func (ic *InitCmd) renderFiles(data initTemplateData) (files []renderedFile, err error) {
+	templates, err := template.New("init").
+		Delims(initTemplateLeftDelim, initTemplateRightDelim).
+		Funcs(template.FuncMap{"quote": strconv.Quote}).
+		ParseFS(initTemplates, "resources/init/*.tmpl")
+	if err != nil {
+		return
+	}
+
+	configContent, err := executeTemplate(templates, "frogbot-config.yml.tmpl", data)
+	if err != nil {
+		return
+	}
+	// Make sure the generated file is loaded by Frogbot as is
+	if err = utils.ValidateConfigSchema(configContent); err != nil {
+		return
+	}
+	files = append(files, renderedFile{path: filepath.Join(utils.FrogbotConfigDir, utils.FrogbotConfigFile), content: configContent})
+
+	for _, pipeline := range pipelineFiles[ciProvider(ic.CiProvider)] {
+		var content []byte
+		if content, err = executeTemplate(templates, pipeline.template, data); err != nil {
+			return
+		}
+		files = append(files, renderedFile{path: pipeline.path, content: content})
+	}
+	return
+}
+
+func executeTemplate(templates *template.Template, name string, data initTemplateData) ([]byte, error) {
+	var content bytes.Buffer
+	if err := templates.ExecuteTemplate(&content, name, data); err != nil {
+		return nil, fmt.Errorf("failed to generate the file from the %s template: %s", name, err.Error())
+	}
+	return content.Bytes(), nil
+}
+
+func (ic *InitCmd) writeFile(relativePath string, content []byte) (err error) {
+	fullPath := filepath.Join(ic.BaseDir, relativePath)
+	if _, err = os.Stat(fullPath); err == nil && !ic.Force {
+		if ic.AcceptDefaults {
+			return fmt.Errorf("%s already exists. Run the command with --%s to overwrite it", relativePath, forceFlag)
+		}
+		var overwrite bool
+		if overwrite, err = ic.confirm(fmt.Sprintf("%s already exists. Overwrite it?", relativePath)); err != nil {
+			return
+		}
+		if !overwrite {
+			log.Info("Skipping", relativePath)
+			return
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return
+	}
+	if err = os.WriteFile(fullPath, content, 0644); err != nil {
+		return
+	}
+	log.Info("Created", relativePath)
+	return
+}
+
+// getCurrentBranch returns the branch checked out in the repository, or the default branch if it can't be determined
+func getCurrentBranch(baseDir string) string {
+	repository, err := git.PlainOpenWithOptions(baseDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return defaultInitBranch
+	}
+	head, err := repository.Head()
+	if err != nil || !head.Name().IsBranch() {
+		return defaultInitBranch
+	}
+	return head.Name().Short()
+}
+
+func (ic *InitCmd) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(ic.Output, format, args...)
+}
+
+// ask prints the question and returns the answer, or the default value if the answer is empty
+func (ic *InitCmd) ask(question, defaultValue string) (string, error) {
+	if ic.AcceptDefaults {
+		return defaultValue, nil
+	}
+	if defaultValue != "" {
+		ic.printf("%s [%s]: ", question, defaultValue)
+	} else {
+		ic.printf("%s: ", question)
+	}
+	answer, err := ic.reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+func (ic *InitCmd) confirm(question string) (bool, error) {
+	for {
+		answer, err := ic.ask(question+" (y/n)", "y")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		ic.printf("Please answer y or n.\n")
+	}
+}
+
+// choose returns the preset value if it was provided by a flag, or asks to choose one of the options by its name or number
+func (ic *InitCmd) choose(question string, options []string, preset, defaultOption string) (string, error) {
+	if preset != "" {
+		if !slices.Contains(options, preset) {
+			return "", fmt.Errorf("'%s' is not supported. The supported values are: %s", preset, strings.Join(options, ", "))
+		}
+		return preset, nil
+	}
+	if !ic.AcceptDefaults {
+		ic.printf("%s\n", question)
+		for i, option := range options {
+			ic.printf("  %d. %s\n", i+1, option)
+		}
+	}
+	for {
+		answer, err := ic.ask("Choose", defaultOption)
+		if err != nil {
+			return "", err
+		}
+		if index, e := strconv.Atoi(answer); e == nil && index > 0 && index <= len(options) {
+			return options[index-1], nil
+		}
+		if slices.Contains(options, answer) {
+			return answer, nil
+		}
+		ic.printf("Please choose one of the listed options.\n")
+	}
+}
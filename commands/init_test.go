@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"bytes"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createInitTestRepo(t *testing.T) string {
+	baseDir := t.TempDir()
+	for _, file := range []string{"package.json", "api/go.mod", "app/app.csproj", "node_modules/lodash/package.json"} {
+		fullPath := filepath.Join(baseDir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		assert.NoError(t, os.WriteFile(fullPath, []byte{}, 0644))
+	}
+	return baseDir
+}
+
+func readGeneratedConfig(t *testing.T, baseDir string) utils.RepoAggregator {
+	content, err := os.ReadFile(filepath.Join(baseDir, utils.FrogbotConfigDir, utils.FrogbotConfigFile))
+	assert.NoError(t, err)
+	assert.NoError(t, utils.ValidateConfigSchema(content))
+	var config utils.RepoAggregator
+	assert.NoError(t, yaml.Unmarshal(content, &config))
+	return config
+}
+
+func TestInitCmdAcceptDefaults(t *testing.T) {
+	for _, ci := range supportedCiProviders {
+		t.Run(ci, func(t *testing.T) {
+			baseDir := createInitTestRepo(t)
+			initCmd := &InitCmd{BaseDir: baseDir, CiProvider: ci, RepoName: "my-repo", AcceptDefaults: true, Output: &bytes.Buffer{}}
+			assert.NoError(t, initCmd.Run())
+
+			config := readGeneratedConfig(t, baseDir)
+			assert.Len(t, config, 1)
+			assert.Equal(t, "my-repo", config[0].RepoName)
+			assert.Equal(t, []string{defaultInitBranch}, config[0].Branches)
+			assert.ElementsMatch(t, []utils.Project{
+				{WorkingDirs: []string{utils.RootDir, "api"}},
+				{WorkingDirs: []string{"app"}, InstallCommand: "dotnet restore"},
+			}, config[0].Projects)
+
+			for _, pipeline := range pipelineFiles[ciProvider(ci)] {
+				content, err := os.ReadFile(filepath.Join(baseDir, pipeline.path))
+				assert.NoError(t, err)
+				var parsed interface{}
+				if ci != string(jenkins) {
+					assert.NoError(t, yaml.Unmarshal(content, &parsed), pipeline.path)
+				}
+			}
+
+			// Existing files aren't overwritten without --force
+			assert.ErrorContains(t, initCmd.Run(), "already exists")
+			initCmd.Force = true
+			assert.NoError(t, initCmd.Run())
+		})
+	}
+}
+
+func TestInitCmdInteractive(t *testing.T) {
+	baseDir := createInitTestRepo(t)
+	// CI server, Git provider, repository name, branch, then include and install command for each project
+	answers := []string{"4", "bitbucketServer", "", "main", "y", "npm ci", "n"}
+	output := &bytes.Buffer{}
+	initCmd := &InitCmd{BaseDir: baseDir, Input: strings.NewReader(strings.Join(answers, "\n") + "\n"), Output: output}
+	assert.NoError(t, initCmd.Run())
+
+	config := readGeneratedConfig(t, baseDir)
+	assert.Equal(t, filepath.Base(baseDir), config[0].RepoName)
+	assert.Equal(t, []string{"main"}, config[0].Branches)
+	assert.Equal(t, []utils.Project{{WorkingDirs: []string{utils.RootDir, "api"}, InstallCommand: "npm ci"}}, config[0].Projects)
+	assert.Contains(t, output.String(), "Detected a npm, Go project in: ., api")
+
+	jenkinsfile, err := os.ReadFile(filepath.Join(baseDir, "Jenkinsfile"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(jenkinsfile), `JF_GIT_PROVIDER = "bitbucketServer"`)
+}
+
+func TestInitCmdNoProjects(t *testing.T) {
+	initCmd := &InitCmd{BaseDir: t.TempDir(), AcceptDefaults: true, Output: &bytes.Buffer{}}
+	assert.ErrorContains(t, initCmd.Run(), "no projects")
+}
+
+func TestInitCmdUnsupportedCiProvider(t *testing.T) {
+	initCmd := &InitCmd{BaseDir: createInitTestRepo(t), CiProvider: "travis", AcceptDefaults: true, Output: &bytes.Buffer{}}
+	assert.ErrorContains(t, initCmd.Run(), "'travis' is not supported")
+}
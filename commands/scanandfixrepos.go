@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
@@ -38,6 +39,9 @@ func (saf *ScanAndFixRepositories) scanAndFixSingleRepository(repoConfig *utils.
 }
 
 func (saf *ScanAndFixRepositories) downloadAndRunScanAndFix(repository *utils.Repository, branch string, client vcsclient.VcsClient) (err error) {
+	if repository.IsShallowOrSparseClone() && !saf.dryRun {
+		return saf.cloneAndRunScanAndFix(repository, branch, client)
+	}
 	wd, cleanup, err := utils.DownloadRepoToTempDir(client, branch, &repository.Git)
 	if err != nil {
 		return
@@ -57,3 +61,36 @@ func (saf *ScanAndFixRepositories) downloadAndRunScanAndFix(repository *utils.Re
 	cfp := CreateFixPullRequestsCmd{dryRun: saf.dryRun, dryRunRepoPath: wd}
 	return cfp.scanAndFixRepository(repository, branch, client)
 }
+
+// cloneAndRunScanAndFix clones the branch partially, and both scans and fixes the same clone.
+func (saf *ScanAndFixRepositories) cloneAndRunScanAndFix(repository *utils.Repository, branch string, client vcsclient.VcsClient) (err error) {
+	gitManager, err := newGitManager(false, "", "", &repository.Git, client)
+	if err != nil {
+		return
+	}
+	var workingDirs []string
+	for _, project := range repository.Projects {
+		workingDirs = append(workingDirs, project.WorkingDirs...)
+	}
+	wd, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, fileutils.RemoveTempDir(wd))
+	}()
+	if err = gitManager.SetSparseCheckoutDirs(workingDirs).Clone(wd, branch); err != nil {
+		return
+	}
+
+	restoreDir, err := utils.Chdir(wd)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, restoreDir())
+	}()
+
+	cfp := CreateFixPullRequestsCmd{gitManager: gitManager, reuseClone: true}
+	return cfp.scanAndFixRepository(repository, branch, client)
+}
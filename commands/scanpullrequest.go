@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
@@ -25,6 +26,7 @@ const (
 	installationCmdFailedErr = "Couldn't run the installation command on the base branch. Assuming new project in the source branch: "
 	noGitHubEnvErr           = "frogbot did not scan this PR, because a GitHub Environment named 'frogbot' does not exist. Please refer to the Frogbot documentation for instructions on how to create the Environment"
 	noGitHubEnvReviewersErr  = "frogbot did not scan this PR, because the existing GitHub Environment named 'frogbot' doesn't have reviewers selected. Please refer to the Frogbot documentation for instructions on how to create the Environment"
+	projectTitle             = "\n# 📁 Project: %s\n"
 )
 
 type ScanPullRequestCmd struct{}
@@ -76,15 +78,20 @@ func scanPullRequest(repoConfig *utils.Repository, client vcsclient.VcsClient) e
 	if len(repoConfig.Branches) == 0 {
 		return &utils.ErrMissingEnv{VariableName: utils.GitBaseBranchEnv}
 	}
+	// Apply the policies of the target branch
+	repoConfig, err := repoConfig.WithBranchPolicies(repoConfig.Branches[0])
+	if err != nil {
+		return err
+	}
 
 	// Audit PR code
-	vulnerabilitiesRows, iacRows, err := auditPullRequest(repoConfig, client)
+	issues, err := auditPullRequest(repoConfig, client)
 	if err != nil {
 		return err
 	}
 
 	// Create a pull request message
-	message := createPullRequestMessage(vulnerabilitiesRows, iacRows, repoConfig.OutputWriter)
+	message := createProjectsPullRequestMessage(issues, repoConfig.OutputWriter)
 
 	// Add comment to the pull request
 	if err = client.AddPullRequestComment(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, message, repoConfig.PullRequestID); err != nil {
@@ -92,52 +99,62 @@ func scanPullRequest(repoConfig *utils.Repository, client vcsclient.VcsClient) e
 	}
 
 	// Fail the Frogbot task if a security issue is found and Frogbot isn't configured to avoid the failure.
-	if repoConfig.FailOnSecurityIssues != nil && *repoConfig.FailOnSecurityIssues && len(vulnerabilitiesRows) > 0 {
+	if repoConfig.FailOnSecurityIssues != nil && *repoConfig.FailOnSecurityIssues && hasVulnerabilities(issues) {
 		err = errors.New(securityIssueFoundErr)
 	}
 	return err
 }
 
-func auditPullRequest(repoConfig *utils.Repository, client vcsclient.VcsClient) ([]formats.VulnerabilityOrViolationRow, []formats.IacSecretsRow, error) {
-	var vulnerabilitiesRows []formats.VulnerabilityOrViolationRow
-	var iacRows []formats.IacSecretsRow
+// The issues found in a single project of the repository
+type projectIssues struct {
+	projectLabel    string
+	vulnerabilities []formats.VulnerabilityOrViolationRow
+	iacs            []formats.IacSecretsRow
+}
+
+func auditPullRequest(repoConfig *utils.Repository, client vcsclient.VcsClient) ([]projectIssues, error) {
+	var issues []projectIssues
 	targetBranch := repoConfig.Branches[0]
 	for i := range repoConfig.Projects {
-		scanDetails := utils.NewScanDetails(client, &repoConfig.Server, &repoConfig.Git).
-			SetProject(&repoConfig.Projects[i]).
-			SetXrayGraphScanParams(repoConfig.Watches, repoConfig.JFrogProjectKey).
-			SetMinSeverity(repoConfig.MinSeverity).
-			SetFixableOnly(repoConfig.FixableOnly)
+		scanDetails := utils.NewProjectScanDetails(client, repoConfig, &repoConfig.Projects[i])
 		sourceResults, err := auditSource(scanDetails)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		repoConfig.SetEntitledForJas(sourceResults.ExtendedScanResults.EntitledForJas)
+		currentIssues := projectIssues{projectLabel: repoConfig.Projects[i].Label()}
 		if repoConfig.IncludeAllVulnerabilities {
 			log.Info("Frogbot is configured to show all vulnerabilities")
-			allIssuesRows, err := getScanVulnerabilitiesRows(sourceResults)
-			if err != nil {
-				return nil, nil, err
+			if currentIssues.vulnerabilities, err = getScanVulnerabilitiesRows(sourceResults); err != nil {
+				return nil, err
 			}
-			vulnerabilitiesRows = append(vulnerabilitiesRows, allIssuesRows...)
-			iacRows = append(iacRows, xrayutils.PrepareIacs(sourceResults.ExtendedScanResults.IacScanResults)...)
+			currentIssues.iacs = xrayutils.PrepareIacs(sourceResults.ExtendedScanResults.IacScanResults)
+			issues = append(issues, currentIssues)
 			continue
 		}
 		// Audit target code
 		scanDetails.SetFailOnInstallationErrors(*repoConfig.FailOnSecurityIssues).SetBranch(targetBranch)
 		targetResults, err := auditTarget(scanDetails)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
-		newIssuesRows, err := createNewIssuesRows(targetResults, sourceResults)
-		if err != nil {
-			return nil, nil, err
+		if currentIssues.vulnerabilities, err = createNewIssuesRows(targetResults, sourceResults); err != nil {
+			return nil, err
 		}
-		vulnerabilitiesRows = append(vulnerabilitiesRows, newIssuesRows...)
-		iacRows = append(iacRows, createNewIacRows(targetResults.ExtendedScanResults.IacScanResults, sourceResults.ExtendedScanResults.IacScanResults)...)
+		currentIssues.iacs = createNewIacRows(targetResults.ExtendedScanResults.IacScanResults, sourceResults.ExtendedScanResults.IacScanResults)
+		issues = append(issues, currentIssues)
 	}
 	log.Info("Xray scan completed")
-	return vulnerabilitiesRows, iacRows, nil
+	return issues, nil
+}
+
+func hasVulnerabilities(issues []projectIssues) bool {
+	for _, currentIssues := range issues {
+		if len(currentIssues.vulnerabilities) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func createNewIacRows(targetIacResults, sourceIacResults []xrayutils.IacOrSecretResult) []formats.IacSecretsRow {
@@ -240,11 +257,19 @@ func auditSource(scanSetup *utils.ScanDetails) (auditResults *audit.Results, err
 	if err != nil {
 		return
 	}
-	fullPathWds := getFullPathWorkingDirs(scanSetup.WorkingDirs, wd)
+	fullPathWds, err := getFullPathWorkingDirs(scanSetup.Project, wd)
+	if err != nil {
+		return
+	}
 	return runInstallAndAudit(scanSetup, fullPathWds...)
 }
 
-func getFullPathWorkingDirs(workingDirs []string, baseWd string) []string {
+// getFullPathWorkingDirs resolves the project working directories, including glob patterns and auto-discovered projects, under baseWd.
+func getFullPathWorkingDirs(project *utils.Project, baseWd string) ([]string, error) {
+	workingDirs, err := utils.ResolveWorkingDirs(project, baseWd)
+	if err != nil {
+		return nil, err
+	}
 	var fullPathWds []string
 	if len(workingDirs) != 0 {
 		for _, workDir := range workingDirs {
@@ -257,7 +282,7 @@ func getFullPathWorkingDirs(workingDirs []string, baseWd string) []string {
 	} else {
 		fullPathWds = append(fullPathWds, baseWd)
 	}
-	return fullPathWds
+	return fullPathWds, nil
 }
 
 func auditTarget(scanSetup *utils.ScanDetails) (auditResults *audit.Results, err error) {
@@ -274,7 +299,10 @@ func auditTarget(scanSetup *utils.ScanDetails) (auditResults *audit.Results, err
 			err = e
 		}
 	}()
-	fullPathWds := getFullPathWorkingDirs(scanSetup.Project.WorkingDirs, wd)
+	fullPathWds, err := getFullPathWorkingDirs(scanSetup.Project, wd)
+	if err != nil {
+		return
+	}
 	return runInstallAndAudit(scanSetup, fullPathWds...)
 }
 
@@ -306,6 +334,11 @@ func runInstallAndAudit(scanSetup *utils.ScanDetails, workDirs ...string) (audit
 }
 
 func runInstallIfNeeded(scanSetup *utils.ScanDetails, workDir string) (err error) {
+	if scanSetup.InstallCommandName == "" && scanSetup.AutoDiscover {
+		if scanSetup, err = scanSetup.WithDiscoveredInstallCommand(workDir); err != nil {
+			return
+		}
+	}
 	if scanSetup.InstallCommandName == "" {
 		return nil
 	}
@@ -378,6 +411,29 @@ func getNewVulnerabilities(targetScan, sourceScan services.ScanResponse, auditRe
 	return
 }
 
+// createProjectsPullRequestMessage creates the pull request message. If the repository includes multiple projects,
+// the issues are listed under the label of the project they were found in.
+func createProjectsPullRequestMessage(issues []projectIssues, writer utils.OutputWriter) string {
+	if len(issues) == 1 {
+		return createPullRequestMessage(issues[0].vulnerabilities, issues[0].iacs, writer)
+	}
+	var contentBuilder strings.Builder
+	for _, currentIssues := range issues {
+		if len(currentIssues.vulnerabilities) == 0 && len(currentIssues.iacs) == 0 {
+			continue
+		}
+		contentBuilder.WriteString(fmt.Sprintf(projectTitle, currentIssues.projectLabel))
+		if len(currentIssues.vulnerabilities) > 0 {
+			contentBuilder.WriteString(writer.VulnerabilitiesContent(currentIssues.vulnerabilities))
+		}
+		contentBuilder.WriteString(writer.IacContent(currentIssues.iacs))
+	}
+	if contentBuilder.Len() == 0 {
+		return writer.NoVulnerabilitiesTitle() + writer.UntitledForJasMsg() + writer.Footer()
+	}
+	return writer.VulnerabiltiesTitle(true) + contentBuilder.String() + writer.UntitledForJasMsg() + writer.Footer()
+}
+
 func createPullRequestMessage(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, iacRows []formats.IacSecretsRow, writer utils.OutputWriter) string {
 	if len(vulnerabilitiesRows) == 0 && len(iacRows) == 0 {
 		return writer.NoVulnerabilitiesTitle() + writer.UntitledForJasMsg() + writer.Footer()
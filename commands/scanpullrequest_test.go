@@ -467,6 +467,37 @@ func TestCreatePullRequestMessage(t *testing.T) {
 	assert.Equal(t, expectedMessage, message)
 }
 
+func TestCreateProjectsPullRequestMessage(t *testing.T) {
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		{
+			Severity:                  "High",
+			ImpactedDependencyName:    "minimist",
+			ImpactedDependencyVersion: "1.2.5",
+			FixedVersions:             []string{"[1.2.6]"},
+			Components:                []formats.ComponentRow{{Name: "minimist", Version: "1.2.5"}},
+		},
+	}
+	writerOutput := &utils.StandardOutput{}
+
+	// A single project isn't labelled
+	issues := []projectIssues{{projectLabel: "frontend", vulnerabilities: vulnerabilities}}
+	assert.Equal(t, createPullRequestMessage(vulnerabilities, nil, writerOutput), createProjectsPullRequestMessage(issues, writerOutput))
+
+	// Only projects with issues are listed
+	issues = append(issues, projectIssues{projectLabel: "backend"}, projectIssues{projectLabel: "payments", vulnerabilities: vulnerabilities})
+	message := createProjectsPullRequestMessage(issues, writerOutput)
+	assert.True(t, strings.HasPrefix(message, writerOutput.VulnerabiltiesTitle(true)))
+	assert.Contains(t, message, fmt.Sprintf(projectTitle, "frontend")+writerOutput.VulnerabilitiesContent(vulnerabilities))
+	assert.Contains(t, message, fmt.Sprintf(projectTitle, "payments")+writerOutput.VulnerabilitiesContent(vulnerabilities))
+	assert.NotContains(t, message, fmt.Sprintf(projectTitle, "backend"))
+	assert.True(t, hasVulnerabilities(issues))
+
+	// No issues in any of the projects
+	issues = []projectIssues{{projectLabel: "frontend"}, {projectLabel: "backend"}}
+	assert.Equal(t, createPullRequestMessage(nil, nil, writerOutput), createProjectsPullRequestMessage(issues, writerOutput))
+	assert.False(t, hasVulnerabilities(issues))
+}
+
 func TestRunInstallIfNeeded(t *testing.T) {
 	scanSetup := utils.ScanDetails{
 		Project: &utils.Project{},
@@ -747,7 +778,8 @@ func TestGetFullPathWorkingDirs(t *testing.T) {
 		WorkingDirs: []string{filepath.Join("a", "b"), filepath.Join("a", "b", "c"), ".", filepath.Join("c", "d", "e", "f")},
 	}
 	baseWd := "tempDir"
-	fullPathWds := getFullPathWorkingDirs(sampleProject.WorkingDirs, baseWd)
+	fullPathWds, err := getFullPathWorkingDirs(&sampleProject, baseWd)
+	assert.NoError(t, err)
 	expectedWds := []string{filepath.Join("tempDir", "a", "b"), filepath.Join("tempDir", "a", "b", "c"), "tempDir", filepath.Join("tempDir", "c", "d", "e", "f")}
 	for _, expectedWd := range expectedWds {
 		assert.Contains(t, fullPathWds, expectedWd)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
 )
 
 var errPullRequestScan = "pull request %d in the %s repository returned the following error: \n%s"
@@ -86,13 +87,14 @@ func isFrogbotRescanComment(comment string) bool {
 
 func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.Repository, client vcsclient.VcsClient) (err error) {
 	// Download the pull request source ("from") branch
+	sourceOwner, sourceRepo := getPullRequestBranchRepository(pr.Source, repo)
 	params := utils.Params{
 		Git: utils.Git{
 			ClientInfo: utils.ClientInfo{
 				GitProvider: repo.GitProvider,
-				VcsInfo:     vcsclient.VcsInfo{APIEndpoint: repo.APIEndpoint, Token: repo.Token},
-				RepoOwner:   repo.RepoOwner,
-				RepoName:    pr.Source.Repository,
+				VcsInfo:     vcsclient.VcsInfo{APIEndpoint: repo.APIEndpoint, Token: repo.Token, Username: repo.Username},
+				RepoOwner:   sourceOwner,
+				RepoName:    sourceRepo,
 				Branches:    []string{pr.Source.Name}},
 		}}
 	frogbotParams := &utils.Repository{
@@ -115,6 +117,7 @@ func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.Reposit
 		err = errors.Join(err, restoreDir())
 	}()
 	// The target branch (to) will be downloaded as part of the Frogbot scanPullRequest execution
+	targetOwner, targetRepo := getPullRequestBranchRepository(pr.Target, repo)
 	params = utils.Params{
 		Scan: utils.Scan{
 			FailOnSecurityIssues:      repo.FailOnSecurityIssues,
@@ -124,10 +127,10 @@ func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.Reposit
 		Git: utils.Git{
 			ClientInfo: utils.ClientInfo{
 				GitProvider: repo.GitProvider,
-				VcsInfo:     vcsclient.VcsInfo{APIEndpoint: repo.APIEndpoint, Token: repo.Token},
-				RepoOwner:   repo.RepoOwner,
+				VcsInfo:     vcsclient.VcsInfo{APIEndpoint: repo.APIEndpoint, Token: repo.Token, Username: repo.Username},
+				RepoOwner:   targetOwner,
 				Branches:    []string{pr.Target.Name},
-				RepoName:    pr.Target.Repository,
+				RepoName:    targetRepo,
 			},
 			PullRequestID: int(pr.ID),
 		},
@@ -135,6 +138,7 @@ func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.Reposit
 			Watches:         repo.Watches,
 			JFrogProjectKey: repo.JFrogProjectKey,
 		},
+		BranchPolicies: repo.BranchPolicies,
 	}
 
 	frogbotParams = &utils.Repository{
@@ -144,3 +148,14 @@ func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.Reposit
 	}
 	return scanPullRequest(frogbotParams, client)
 }
+
+// getPullRequestBranchRepository returns the owner and the name of the repository of the pull request source or target branch.
+// Bitbucket Cloud returns the full name of the repository, which starts with the workspace that owns it.
+func getPullRequestBranchRepository(branch vcsclient.BranchInfo, repo utils.Repository) (owner, name string) {
+	if repo.GitProvider == vcsutils.BitbucketCloud {
+		if owner, name, found := strings.Cut(branch.Repository, "/"); found {
+			return owner, name
+		}
+	}
+	return repo.RepoOwner, branch.Repository
+}
@@ -2,9 +2,12 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"testing"
 	"time"
@@ -102,6 +105,47 @@ func TestShouldNotScanPullRequestError(t *testing.T) {
 	assert.False(t, shouldScan)
 }
 
+func TestShouldScanPullRequestBitbucketCloud(t *testing.T) {
+	// A Bitbucket Cloud stand-in with a single pull request, which was already scanned by Frogbot
+	comment, err := json.Marshal(utils.GetCompatibleOutputWriter(vcsutils.BitbucketCloud).NoVulnerabilitiesTitle() + "text \n table\n text text text")
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/repositories/jfrog/frogbot/pullrequests/":
+			_, err = w.Write([]byte(`{"values": [{"id": 1, "source": {"branch": {"name": "feature"}, "repository": {"full_name": "contributor/frogbot"}},
+				"destination": {"branch": {"name": "main"}, "repository": {"full_name": "jfrog/frogbot"}}}]}`))
+		case "/repositories/jfrog/frogbot/pullrequests/1/comments/":
+			_, err = w.Write([]byte(`{"values": [{"id": 1, "content": {"raw": ` + string(comment) + `}, "created_on": "2023-06-01T10:00:00.000000+00:00"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	client, err := vcsclient.NewClientBuilder(vcsutils.BitbucketCloud).ApiEndpoint(server.URL).Username("frogbot-user").Token("app-password").Build()
+	assert.NoError(t, err)
+	repo := utils.Repository{
+		OutputWriter: utils.GetCompatibleOutputWriter(vcsutils.BitbucketCloud),
+		Params:       utils.Params{Git: utils.Git{ClientInfo: utils.ClientInfo{GitProvider: vcsutils.BitbucketCloud, RepoOwner: "jfrog", RepoName: "frogbot"}}},
+	}
+
+	pullRequests, err := client.ListOpenPullRequests(context.Background(), repo.RepoOwner, repo.RepoName)
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 1)
+	shouldScan, err := shouldScanPullRequest(repo, client, int(pullRequests[0].ID))
+	assert.NoError(t, err)
+	assert.False(t, shouldScan)
+
+	// The repositories of the pull request branches are returned with their workspaces
+	owner, name := getPullRequestBranchRepository(pullRequests[0].Source, repo)
+	assert.Equal(t, "contributor", owner)
+	assert.Equal(t, "frogbot", name)
+	owner, name = getPullRequestBranchRepository(pullRequests[0].Target, repo)
+	assert.Equal(t, "jfrog", owner)
+	assert.Equal(t, "frogbot", name)
+}
+
 func TestScanAllPullRequestsMultiRepo(t *testing.T) {
 	server, restoreEnv := verifyEnv(t)
 	defer restoreEnv()
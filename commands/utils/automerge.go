@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/froggit-go/vcsclient"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+	"strings"
+)
+
+// The types of version updates, by the first version segment they change
+const (
+	MajorUpdate = "major"
+	MinorUpdate = "minor"
+	PatchUpdate = "patch"
+)
+
+// The methods the Git providers merge pull requests with
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// AutoMerge is the policy of the fix pull requests that Frogbot enables the auto-merge of the Git provider on.
+// The pull requests are merged by the Git provider once their required checks pass.
+type AutoMerge struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// The types of version updates that may be merged automatically. Defaults to patch updates only.
+	UpdateTypes []string `yaml:"updateTypes,omitempty"`
+	// If true, only fixes of vulnerabilities that the contextual analysis found not applicable may be merged automatically
+	NotApplicableOnly bool `yaml:"notApplicableOnly,omitempty"`
+	// merge, squash or rebase. Defaults to merge.
+	MergeMethod string `yaml:"mergeMethod,omitempty"`
+}
+
+func (am *AutoMerge) setDefaultsIfNeeded() (err error) {
+	if !am.Enabled {
+		if am.Enabled, err = getBoolEnv(GitAutoMergeEnv, false); err != nil {
+			return
+		}
+	}
+	if len(am.UpdateTypes) == 0 {
+		if am.UpdateTypes = getListEnv(GitAutoMergeUpdateTypesEnv); len(am.UpdateTypes) == 0 {
+			am.UpdateTypes = []string{PatchUpdate}
+		}
+	}
+	for i, updateType := range am.UpdateTypes {
+		am.UpdateTypes[i] = strings.ToLower(updateType)
+		if !slices.Contains([]string{MajorUpdate, MinorUpdate, PatchUpdate}, am.UpdateTypes[i]) {
+			return fmt.Errorf("the auto-merge update type '%s' is invalid. The following values are accepted: %s, %s or %s", updateType, PatchUpdate, MinorUpdate, MajorUpdate)
+		}
+	}
+	if !am.NotApplicableOnly {
+		if am.NotApplicableOnly, err = getBoolEnv(GitAutoMergeNotApplicableOnlyEnv, false); err != nil {
+			return
+		}
+	}
+	if am.MergeMethod == "" {
+		if am.MergeMethod = strings.ToLower(getTrimmedEnv(GitAutoMergeMethodEnv)); am.MergeMethod == "" {
+			am.MergeMethod = MergeMethodMerge
+		}
+	}
+	if !slices.Contains([]string{MergeMethodMerge, MergeMethodSquash, MergeMethodRebase}, am.MergeMethod) {
+		return fmt.Errorf("the auto-merge method '%s' is invalid. The following values are accepted: %s, %s or %s", am.MergeMethod, MergeMethodMerge, MergeMethodSquash, MergeMethodRebase)
+	}
+	return
+}
+
+// IsEligible returns true if the pull request that fixes the vulnerabilities may be merged automatically.
+func (am *AutoMerge) IsEligible(vulnerabilities ...*VulnerabilityDetails) bool {
+	if !am.Enabled || len(vulnerabilities) == 0 {
+		return false
+	}
+	for _, vulnerability := range vulnerabilities {
+		if am.NotApplicableOnly && vulnerability.Applicable != xrayutils.NotApplicableStringValue {
+			log.Debug(fmt.Sprintf("Auto-merge isn't enabled, since the contextual analysis status of %s is '%s'", vulnerability.ImpactedDependencyName, vulnerability.Applicable))
+			return false
+		}
+		updateType := GetVersionUpdateType(vulnerability.ImpactedDependencyVersion, vulnerability.SuggestedFixedVersion)
+		if !slices.Contains(am.UpdateTypes, updateType) {
+			log.Debug(fmt.Sprintf("Auto-merge isn't enabled, since updating %s from %s to %s is a %s update", vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion, vulnerability.SuggestedFixedVersion, updateType))
+			return false
+		}
+	}
+	return true
+}
+
+// GetVersionUpdateType returns the type of the update between the versions, by the first version segment that changes.
+// Versions that aren't dot-separated are considered a major update.
+func GetVersionUpdateType(currentVersion, fixVersion string) string {
+	currentSegments, fixSegments := getVersionSegments(currentVersion), getVersionSegments(fixVersion)
+	if len(currentSegments) == 0 || len(fixSegments) == 0 {
+		return MajorUpdate
+	}
+	for i, updateType := range []string{MajorUpdate, MinorUpdate} {
+		if getVersionSegment(currentSegments, i) != getVersionSegment(fixSegments, i) {
+			return updateType
+		}
+	}
+	return PatchUpdate
+}
+
+// getVersionSegments returns the dot-separated segments of the version, without its 'v' prefix and its pre-release and build suffixes.
+func getVersionSegments(version string) []string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if suffixStart := strings.IndexAny(version, "-+"); suffixStart >= 0 {
+		version = version[:suffixStart]
+	}
+	if version == "" {
+		return nil
+	}
+	return strings.Split(version, ".")
+}
+
+// getVersionSegment returns the segment of the version, without leading zeros. Missing segments are 0.
+func getVersionSegment(segments []string, index int) string {
+	if index >= len(segments) {
+		return "0"
+	}
+	if segment := strings.TrimLeft(segments[index], "0"); segment != "" {
+		return segment
+	}
+	return "0"
+}
+
+// pullRequestAutoMerger is implemented by the clients of the Git providers which support merging pull requests automatically.
+type pullRequestAutoMerger interface {
+	EnableAutoMerge(ctx context.Context, owner, repository string, pullRequestID int, mergeMethod string) error
+}
+
+// EnableAutoMerge enables the auto-merge of the pull request, which is merged once its required checks pass.
+// If the Git provider doesn't support it, a warning is logged.
+func EnableAutoMerge(ctx context.Context, client vcsclient.VcsClient, owner, repository string, pullRequestID int, mergeMethod string) error {
+	autoMerger, ok := client.(pullRequestAutoMerger)
+	if !ok {
+		log.Warn("Auto-merge of pull requests isn't supported by the Git provider")
+		return nil
+	}
+	log.Info(fmt.Sprintf("Enabling the auto-merge of pull request %d", pullRequestID))
+	return autoMerger.EnableAutoMerge(ctx, owner, repository, pullRequestID, mergeMethod)
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestGetVersionUpdateType(t *testing.T) {
+	tests := []struct {
+		currentVersion     string
+		fixVersion         string
+		expectedUpdateType string
+	}{
+		{currentVersion: "1.2.3", fixVersion: "1.2.4", expectedUpdateType: PatchUpdate},
+		{currentVersion: "v1.2.3", fixVersion: "v1.2.3-1", expectedUpdateType: PatchUpdate},
+		{currentVersion: "1.2", fixVersion: "1.2.0.1", expectedUpdateType: PatchUpdate},
+		{currentVersion: "1.02.3", fixVersion: "1.2.9", expectedUpdateType: PatchUpdate},
+		{currentVersion: "1.2.3", fixVersion: "1.3.0", expectedUpdateType: MinorUpdate},
+		{currentVersion: "0.9.1+build", fixVersion: "0.10.0", expectedUpdateType: MinorUpdate},
+		{currentVersion: "1.2.3", fixVersion: "2.0.0", expectedUpdateType: MajorUpdate},
+		{currentVersion: "1.2.3", fixVersion: "", expectedUpdateType: MajorUpdate},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expectedUpdateType, GetVersionUpdateType(test.currentVersion, test.fixVersion), test.currentVersion+" -> "+test.fixVersion)
+	}
+}
+
+func TestAutoMergeIsEligible(t *testing.T) {
+	newDetails := func(version, fixVersion, applicable string) *VulnerabilityDetails {
+		return NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: version, Applicable: applicable}, fixVersion)
+	}
+	autoMerge := &AutoMerge{Enabled: true, UpdateTypes: []string{PatchUpdate}}
+	assert.True(t, autoMerge.IsEligible(newDetails("1.2.5", "1.2.6", xrayutils.ApplicableStringValue)))
+	assert.False(t, autoMerge.IsEligible(newDetails("1.2.5", "1.2.6", ""), newDetails("1.2.5", "1.3.0", "")))
+	assert.False(t, autoMerge.IsEligible())
+
+	autoMerge.NotApplicableOnly = true
+	assert.False(t, autoMerge.IsEligible(newDetails("1.2.5", "1.2.6", xrayutils.ApplicableStringValue)))
+	assert.True(t, autoMerge.IsEligible(newDetails("1.2.5", "1.2.6", xrayutils.NotApplicableStringValue)))
+
+	autoMerge.Enabled = false
+	assert.False(t, autoMerge.IsEligible(newDetails("1.2.5", "1.2.6", xrayutils.NotApplicableStringValue)))
+}
+
+func TestAutoMergeSetDefaults(t *testing.T) {
+	autoMerge := &AutoMerge{UpdateTypes: []string{"Patch", "MINOR"}}
+	assert.NoError(t, autoMerge.setDefaultsIfNeeded())
+	assert.Equal(t, &AutoMerge{UpdateTypes: []string{PatchUpdate, MinorUpdate}, MergeMethod: MergeMethodMerge}, autoMerge)
+
+	assert.Error(t, (&AutoMerge{UpdateTypes: []string{"prerelease"}}).setDefaultsIfNeeded())
+	assert.Error(t, (&AutoMerge{MergeMethod: "fast-forward"}).setDefaultsIfNeeded())
+}
+
+func TestGithubEnableAutoMerge(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, map[string]string{"/repos/jfrog/frogbot/pulls/3": `{"number": 3, "node_id": "PR_kwDOA"}`}, &requests)
+	defer server.Close()
+	client, err := newGithubClient(nil, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "github-token"}, http.DefaultTransport)
+	require.NoError(t, err)
+
+	assert.NoError(t, EnableAutoMerge(context.Background(), client, "jfrog", "frogbot", 3, MergeMethodSquash))
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPost, requests[0].method)
+	assert.Equal(t, "/graphql", requests[0].path)
+	assert.Equal(t, map[string]interface{}{"pullRequestId": "PR_kwDOA", "mergeMethod": "SQUASH"}, requests[0].body.(map[string]interface{})["variables"])
+}
+
+func TestGithubGraphqlUrl(t *testing.T) {
+	for apiEndpoint, expectedUrl := range map[string]string{
+		"":                                  "https://api.github.com/graphql",
+		"https://github.example.com/api/v3": "https://github.example.com/api/graphql",
+	} {
+		client, err := newGithubClient(nil, vcsclient.VcsInfo{APIEndpoint: apiEndpoint}, http.DefaultTransport)
+		require.NoError(t, err)
+		assert.Equal(t, expectedUrl, client.(*githubClient).graphqlUrl())
+	}
+}
+
+func TestGitlabEnableAutoMerge(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, map[string]string{
+		"/api/v4/projects/jfrog%2Ffrogbot/merge_requests/3": `{"iid": 3, "head_pipeline": {"id": 12}}`,
+		"/api/v4/projects/jfrog%2Ffrogbot/merge_requests/4": `{"iid": 4, "head_pipeline": null}`,
+	}, &requests)
+	defer server.Close()
+	client := newGitlabClient(nil, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "gitlab-token"}, http.DefaultTransport)
+	assert.Equal(t, gitProviderRequestTimeout, client.(*gitlabClient).httpClient.Timeout)
+
+	assert.NoError(t, EnableAutoMerge(context.Background(), client, "jfrog", "frogbot", 3, MergeMethodSquash))
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPut, path: "/api/v4/projects/jfrog/frogbot/merge_requests/3/merge", body: map[string]interface{}{"merge_when_pipeline_succeeds": true, "squash": true}},
+	}, requests)
+
+	// Merge requests without a pipeline would be merged immediately, so auto-merge isn't enabled
+	requests = nil
+	assert.NoError(t, EnableAutoMerge(context.Background(), client, "jfrog", "frogbot", 4, MergeMethodSquash))
+	assert.Empty(t, requests)
+}
+
+func TestGiteaEnableAutoMerge(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, nil, &requests)
+	defer server.Close()
+	client, err := newGiteaClient(vcsclient.VcsInfo{APIEndpoint: server.URL, Token: giteaTestToken}, http.DefaultTransport)
+	require.NoError(t, err)
+
+	assert.NoError(t, EnableAutoMerge(context.Background(), client, "jfrog", "frogbot", 3, MergeMethodRebase))
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPost, path: "/api/v1/repos/jfrog/frogbot/pulls/3/merge", body: map[string]interface{}{"Do": "rebase", "merge_when_checks_succeed": true}},
+	}, requests)
+}
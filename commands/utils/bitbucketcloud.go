@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const bitbucketCloudApiEndpoint = "https://api.bitbucket.org/2.0"
+
+// bitbucketCloudClient completes the Bitbucket Cloud client of froggit-go with the requests Frogbot relies on and the client doesn't support.
+type bitbucketCloudClient struct {
+	vcsclient.VcsClient
+	apiEndpoint string
+	username    string
+	token       string
+	httpClient  *http.Client
+}
+
+func newBitbucketCloudClient(client vcsclient.VcsClient, vcsInfo vcsclient.VcsInfo, transport http.RoundTripper) vcsclient.VcsClient {
+	apiEndpoint := strings.TrimSuffix(vcsInfo.APIEndpoint, "/")
+	if apiEndpoint == "" {
+		apiEndpoint = bitbucketCloudApiEndpoint
+	}
+	return &bitbucketCloudClient{VcsClient: client, apiEndpoint: apiEndpoint, username: vcsInfo.Username, token: vcsInfo.Token, httpClient: &http.Client{Timeout: gitProviderRequestTimeout, Transport: transport}}
+}
+
+// DownloadFileFromRepo downloads a file from the branch of the repository, or from its main branch if the branch is empty.
+func (bc *bitbucketCloudClient) DownloadFileFromRepo(ctx context.Context, owner, repository, branch, path string) ([]byte, int, error) {
+	if branch == "" {
+		mainBranch, statusCode, err := bc.getMainBranch(ctx, owner, repository)
+		if err != nil {
+			return nil, statusCode, err
+		}
+		branch = mainBranch
+	}
+	return bc.get(ctx, fmt.Sprintf("repositories/%s/%s/src/%s/%s", url.PathEscape(owner), url.PathEscape(repository), url.PathEscape(branch), strings.TrimPrefix(path, "/")))
+}
+
+func (bc *bitbucketCloudClient) getMainBranch(ctx context.Context, owner, repository string) (string, int, error) {
+	content, statusCode, err := bc.get(ctx, fmt.Sprintf("repositories/%s/%s", url.PathEscape(owner), url.PathEscape(repository)))
+	if err != nil {
+		return "", statusCode, err
+	}
+	var repositoryDetails struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	if err = json.Unmarshal(content, &repositoryDetails); err != nil {
+		return "", statusCode, err
+	}
+	if repositoryDetails.MainBranch.Name == "" {
+		return "", statusCode, fmt.Errorf("the main branch of the %s/%s repository is unknown", owner, repository)
+	}
+	return repositoryDetails.MainBranch.Name, statusCode, nil
+}
+
+func (bc *bitbucketCloudClient) get(ctx context.Context, apiPath string) (content []byte, statusCode int, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bc.apiEndpoint+"/"+apiPath, nil)
+	if err != nil {
+		return
+	}
+	req.SetBasicAuth(bc.username, bc.token)
+	resp, err := bc.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	statusCode = resp.StatusCode
+	if content, err = io.ReadAll(resp.Body); err != nil {
+		return
+	}
+	if statusCode != http.StatusOK {
+		err = newHttpStatusError("Bitbucket Cloud", apiPath, resp)
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const bitbucketCloudTestConfig = `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - main
+    scan:
+      minSeverity: High
+`
+
+// newBitbucketCloudStandIn serves the Bitbucket Cloud REST API requests Frogbot sends, for the frogbot repository in the jfrog workspace.
+func newBitbucketCloudStandIn(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "frogbot-user" || password != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var err error
+		switch r.URL.Path {
+		case "/repositories/jfrog/frogbot":
+			_, err = w.Write([]byte(`{"full_name": "jfrog/frogbot", "mainbranch": {"name": "main"}}`))
+		case "/repositories/jfrog/frogbot/src/main/.frogbot/frogbot-config.yml":
+			_, err = w.Write([]byte(bitbucketCloudTestConfig))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		assert.NoError(t, err)
+	}))
+}
+
+func TestBitbucketCloudDownloadFileFromRepo(t *testing.T) {
+	server := newBitbucketCloudStandIn(t)
+	defer server.Close()
+	vcsInfo := vcsclient.VcsInfo{APIEndpoint: server.URL, Username: "frogbot-user", Token: "app-password"}
+	froggitClient, err := vcsclient.NewClientBuilder(vcsutils.BitbucketCloud).ApiEndpoint(vcsInfo.APIEndpoint).Username(vcsInfo.Username).Token(vcsInfo.Token).Build()
+	assert.NoError(t, err)
+	client := newBitbucketCloudClient(froggitClient, vcsInfo, http.DefaultTransport)
+	assert.Equal(t, gitProviderRequestTimeout, client.(*bitbucketCloudClient).httpClient.Timeout)
+
+	// From the main branch
+	content, statusCode, err := client.DownloadFileFromRepo(context.Background(), "jfrog", "frogbot", "", ".frogbot/frogbot-config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, bitbucketCloudTestConfig, string(content))
+
+	// Missing file
+	_, statusCode, err = client.DownloadFileFromRepo(context.Background(), "jfrog", "frogbot", "dev", ".frogbot/frogbot-config.yml")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	_, err = readConfigFromTarget(client, &ClientInfo{RepoOwner: "jfrog", RepoName: "frogbot", Branches: []string{"dev"}})
+	assert.IsType(t, &ErrMissingConfig{}, err)
+}
+
+func TestGetFrogbotUtilsBitbucketCloud(t *testing.T) {
+	server := newBitbucketCloudStandIn(t)
+	defer server.Close()
+	SetEnvAndAssert(t, map[string]string{
+		JFrogUrlEnv:       "http://127.0.0.1:8081",
+		JFrogTokenEnv:     "token",
+		GitProvider:       string(BitbucketCloud),
+		GitApiEndpointEnv: server.URL,
+		GitRepoOwnerEnv:   "jfrog",
+		GitRepoEnv:        "frogbot",
+		GitTokenEnv:       "app-password",
+		GitUsernameEnv:    "frogbot-user",
+		GitBaseBranchEnv:  "main",
+	})
+	frogbotUtils, err := GetFrogbotUtils()
+	assert.NoError(t, err)
+	AssertSanitizedEnv(t)
+	assert.Len(t, frogbotUtils.Repositories, 1)
+	repository := frogbotUtils.Repositories[0]
+	assert.Equal(t, vcsutils.BitbucketCloud, repository.GitProvider)
+	assert.Equal(t, "High", repository.MinSeverity)
+	assert.IsType(t, &SimplifiedOutput{}, repository.OutputWriter)
+}
+
+func TestExtractClientInfoBitbucketCloudUsername(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{
+		GitProvider:     string(BitbucketCloud),
+		GitRepoOwnerEnv: "jfrog",
+		GitTokenEnv:     "app-password",
+	})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	_, err := extractClientInfo()
+	assert.ErrorContains(t, err, GitUsernameEnv)
+}
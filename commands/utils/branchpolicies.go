@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	xrutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// The settings a branch policy may override
+type branchPolicy struct {
+	Scan yaml.Node `yaml:"scan"`
+	Git  yaml.Node `yaml:"git"`
+}
+
+// WithBranchPolicies returns a copy of the repository with the settings of the branch policies whose patterns match the target branch.
+// Policies are applied in the order they appear in the frogbot-config.yml file, so a policy overrides the settings of the policies above it.
+// Patterns are globs, in which "*" matches within a single path segment of the branch name and "**" matches any number of segments.
+func (r *Repository) WithBranchPolicies(branch string) (*Repository, error) {
+	if r.BranchPolicies.Kind != yaml.MappingNode {
+		return r, nil
+	}
+	repository := *r
+	// Avoid changing settings shared with the original repository through pointers
+	if repository.FailOnSecurityIssues != nil {
+		failOnSecurityIssues := *repository.FailOnSecurityIssues
+		repository.FailOnSecurityIssues = &failOnSecurityIssues
+	}
+	for i := 0; i+1 < len(r.BranchPolicies.Content); i += 2 {
+		pattern, policyNode := r.BranchPolicies.Content[i].Value, r.BranchPolicies.Content[i+1]
+		if !matchPathSegments(strings.Split(pattern, "/"), strings.Split(branch, "/")) {
+			continue
+		}
+		log.Info(fmt.Sprintf("Applying the '%s' branch policy to the %s branch", pattern, branch))
+		if err := repository.Params.applyBranchPolicy(policyNode); err != nil {
+			return nil, fmt.Errorf("failed to apply the '%s' branch policy: %s", pattern, err.Error())
+		}
+	}
+	return &repository, nil
+}
+
+func (p *Params) applyBranchPolicy(policyNode *yaml.Node) (err error) {
+	var policy branchPolicy
+	if err = policyNode.Decode(&policy); err != nil {
+		return
+	}
+	if policy.Scan.Kind == yaml.MappingNode {
+		if err = policy.Scan.Decode(&p.Scan); err != nil {
+			return
+		}
+		if p.MinSeverity, err = xrutils.GetSeveritiesFormat(p.MinSeverity); err != nil {
+			return
+		}
+		if getYamlNode(&policy.Scan, []string{"projects"}) != nil {
+			for i := range p.Projects {
+				if err = p.Projects[i].setDefaultsIfNeeded(); err != nil {
+					return
+				}
+			}
+		}
+	}
+	if policy.Git.Kind == yaml.MappingNode {
+		if err = policy.Git.Decode(&p.Git); err != nil {
+			return
+		}
+		err = validateHashPlaceHolder(p.BranchNameTemplate)
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+const branchPoliciesConfig = `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - main
+        - release/1.0
+    scan:
+      minSeverity: Low
+      failOnSecurityIssues: false
+      projects:
+        - workingDirs:
+            - .
+    branchPolicies:
+      "release/*":
+        scan:
+          minSeverity: medium
+          failOnSecurityIssues: true
+        git:
+          aggregateFixes: false
+          commitMessageTemplate: "Release fix ${IMPACTED_PACKAGE}"
+      "release/1.*":
+        scan:
+          projects:
+            - workingDirs:
+                - release
+              installCommand: npm ci
+      "hotfix/**":
+        git:
+          branchNameTemplate: "hotfix-${BRANCH_NAME_HASH}"
+`
+
+func TestWithBranchPolicies(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{GitAggregateFixesEnv: "true"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	repoAggregator, err := BuildRepoAggregator([]byte(branchPoliciesConfig), &Git{ClientInfo: ClientInfo{RepoOwner: "jfrog"}}, &coreconfig.ServerDetails{})
+	assert.NoError(t, err)
+	repository := &repoAggregator[0]
+
+	// No matching policy
+	mainRepository, err := repository.WithBranchPolicies("main")
+	assert.NoError(t, err)
+	assert.Equal(t, repository, mainRepository)
+
+	releaseRepository, err := repository.WithBranchPolicies("release/1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Medium", releaseRepository.MinSeverity)
+	assert.True(t, *releaseRepository.FailOnSecurityIssues)
+	assert.False(t, releaseRepository.AggregateFixes)
+	assert.Equal(t, "Release fix ${IMPACTED_PACKAGE}", releaseRepository.CommitMessageTemplate)
+	assert.Len(t, releaseRepository.Projects, 1)
+	assert.Equal(t, []string{"release"}, releaseRepository.Projects[0].WorkingDirs)
+	assert.Equal(t, "npm", releaseRepository.Projects[0].InstallCommandName)
+	assert.Equal(t, []string{"ci"}, releaseRepository.Projects[0].InstallCommandArgs)
+	// Settings the policies don't set are kept
+	assert.Equal(t, "frogbot", releaseRepository.RepoName)
+	assert.Equal(t, "jfrog", releaseRepository.RepoOwner)
+
+	// The original repository is unchanged
+	assert.Equal(t, "Low", repository.MinSeverity)
+	assert.False(t, *repository.FailOnSecurityIssues)
+	assert.True(t, repository.AggregateFixes)
+	assert.Equal(t, []string{"."}, repository.Projects[0].WorkingDirs)
+
+	hotfixRepository, err := repository.WithBranchPolicies("hotfix/a/b")
+	assert.NoError(t, err)
+	assert.Equal(t, "hotfix-${BRANCH_NAME_HASH}", hotfixRepository.BranchNameTemplate)
+	assert.Equal(t, "Low", hotfixRepository.MinSeverity)
+}
+
+func TestWithBranchPoliciesInvalid(t *testing.T) {
+	repository := Repository{}
+	assert.NoError(t, unmarshalYamlNode(&repository.BranchPolicies, `"*":
+  git:
+    branchNameTemplate: "no-hash"
+`))
+	_, err := repository.WithBranchPolicies("main")
+	assert.ErrorContains(t, err, "failed to apply the '*' branch policy")
+
+	// Repository level Git settings can't be set per branch
+	err = ValidateConfigSchema([]byte(`- params:
+    git:
+      repoName: frogbot
+      branches:
+        - main
+    branchPolicies:
+      "release/*":
+        git:
+          repoName: other
+`))
+	assert.ErrorContains(t, err, "Additional property repoName is not allowed")
+}
+
+func unmarshalYamlNode(node *yaml.Node, content string) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return err
+	}
+	*node = *document.Content[0]
+	return nil
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// The namespace of the SSH signatures of git commits, as used by 'git commit -S' with gpg.format=ssh
+	sshSignatureNamespace     = "git"
+	sshSignatureHashAlgorithm = "sha512"
+	sshSignatureLineLength    = 70
+)
+
+// CommitSigningKey holds the private key that signs the commits of Frogbot.
+type CommitSigningKey struct {
+	// An ASCII armored OpenPGP private key, or a PEM encoded SSH private key
+	PrivateKey string
+	Passphrase string
+}
+
+func (ck *CommitSigningKey) IsSet() bool {
+	return ck.PrivateKey != ""
+}
+
+func (ck *CommitSigningKey) isOpenPgp() bool {
+	return strings.Contains(ck.PrivateKey, "PGP PRIVATE KEY BLOCK")
+}
+
+// readCommitSigningKeyFromEnv reads the signing key. The key may be set as the key itself, or as the path of the key file.
+func readCommitSigningKeyFromEnv() (signingKey CommitSigningKey, err error) {
+	if signingKey.PrivateKey, err = readPrivateKeyEnv(GitSigningKeyEnv); err != nil {
+		return
+	}
+	signingKey.Passphrase = os.Getenv(GitSigningKeyPassphraseEnv)
+	return
+}
+
+// commitSigner signs commits with either an OpenPGP key or an SSH key.
+type commitSigner struct {
+	openPgpKey *openpgp.Entity
+	sshKey     ssh.Signer
+}
+
+// newCommitSigner returns nil if no signing key is set.
+func newCommitSigner(signingKey CommitSigningKey) (*commitSigner, error) {
+	if !signingKey.IsSet() {
+		return nil, nil
+	}
+	if signingKey.isOpenPgp() {
+		openPgpKey, err := readOpenPgpKey(signingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the OpenPGP key set in %s: %s", GitSigningKeyEnv, err.Error())
+		}
+		return &commitSigner{openPgpKey: openPgpKey}, nil
+	}
+	var sshKey ssh.Signer
+	var err error
+	if signingKey.Passphrase == "" {
+		sshKey, err = ssh.ParsePrivateKey([]byte(signingKey.PrivateKey))
+	} else {
+		sshKey, err = ssh.ParsePrivateKeyWithPassphrase([]byte(signingKey.PrivateKey), []byte(signingKey.Passphrase))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the SSH key set in %s: %s", GitSigningKeyEnv, err.Error())
+	}
+	return &commitSigner{sshKey: sshKey}, nil
+}
+
+func readOpenPgpKey(signingKey CommitSigningKey) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(signingKey.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, errors.New("the key ring doesn't include a private key")
+	}
+	entity := entities[0]
+	if signingKey.Passphrase != "" {
+		err = entity.DecryptPrivateKeys([]byte(signingKey.Passphrase))
+	} else if entity.PrivateKey.Encrypted {
+		err = fmt.Errorf("the private key is encrypted. Set its passphrase in %s", GitSigningKeyPassphraseEnv)
+	}
+	return entity, err
+}
+
+// sign returns the armored signature of the encoded commit, which is set in the gpgsig header of the commit.
+func (cs *commitSigner) sign(content []byte) (string, error) {
+	if cs.openPgpKey == nil {
+		return sshSign(cs.sshKey, content)
+	}
+	var signature bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&signature, cs.openPgpKey, bytes.NewReader(content), nil); err != nil {
+		return "", err
+	}
+	return signature.String(), nil
+}
+
+// signCommit replaces the commit with a signed commit, and returns the hash of the signed commit.
+// go-git signs commits with OpenPGP keys only, so SSH signatures are added to the commits after they're created.
+func (cs *commitSigner) signCommit(objectStorer storer.EncodedObjectStorer, commit *object.Commit) (plumbing.Hash, error) {
+	unsigned := objectStorer.NewEncodedObject()
+	if err := commit.EncodeWithoutSignature(unsigned); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if commit.PGPSignature, err = cs.sign(content); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	signed := objectStorer.NewEncodedObject()
+	if err = commit.Encode(signed); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return objectStorer.SetEncodedObject(signed)
+}
+
+// sshSign returns the armored SSH signature of the message, in the format of 'ssh-keygen -Y sign'.
+// See https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+func sshSign(signer ssh.Signer, message []byte) (string, error) {
+	hash := sha512.Sum512(message)
+	signedData := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{sshSignatureNamespace, "", sshSignatureHashAlgorithm, hash[:]})...)
+	var signature *ssh.Signature
+	var err error
+	if algorithmSigner, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// Like ssh-keygen, sign with SHA-512, as SHA-1 RSA signatures are deprecated
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return "", err
+	}
+	blob := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{1, signer.PublicKey().Marshal(), sshSignatureNamespace, "", sshSignatureHashAlgorithm, ssh.Marshal(signature)})...)
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var armored bytes.Buffer
+	armored.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > sshSignatureLineLength {
+		armored.WriteString(encoded[:sshSignatureLineLength] + "\n")
+		encoded = encoded[sshSignatureLineLength:]
+	}
+	armored.WriteString(encoded + "\n-----END SSH SIGNATURE-----\n")
+	return armored.String(), nil
+}
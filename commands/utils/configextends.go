@@ -0,0 +1,359 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	extendsKey = "extends"
+	paramsKey  = "params"
+	// Shared configurations may extend other shared configurations, up to this depth
+	maxExtendsDepth     = 5
+	extendsFetchTimeout = 30 * time.Second
+)
+
+// extendsRepositoryRef points to a shared configuration file stored in a Git repository.
+type extendsRepositoryRef struct {
+	// The repository owner. Defaults to the owner of the scanned repository
+	Owner      string `yaml:"owner"`
+	Repository string `yaml:"repository"`
+	// Defaults to the default branch of the repository
+	Branch string `yaml:"branch"`
+	// Defaults to .frogbot/frogbot-config.yml
+	Path string `yaml:"path"`
+}
+
+func (er extendsRepositoryRef) String() string {
+	return fmt.Sprintf("%s/%s/%s@%s", er.Owner, er.Repository, er.Path, er.Branch)
+}
+
+// ConfigExtendsResolver downloads the shared configurations referenced by the 'extends' key of the repositories in the frogbot-config.yml file.
+type ConfigExtendsResolver struct {
+	// The client and details of the scanned Git repository, used to download shared configurations from other repositories.
+	// If the client is nil, only local files and URLs can be extended.
+	client     vcsclient.VcsClient
+	clientInfo *ClientInfo
+	// The root directory of the repository the frogbot-config.yml file was read from.
+	// If empty, the file was downloaded from the scanned repository, and so are the local files it extends.
+	repositoryRoot string
+	httpClient     *http.Client
+	// The files and URLs allowed to be extended, by the JF_CONFIG_ALLOWED_EXTENDS environment variable
+	allowedExtends []string
+	// Raw content of the shared configurations, by their reference
+	cache map[string][]byte
+	// The shared configuration each merged node was loaded from, to locate the issues in the merged params
+	sources map[*yaml.Node]string
+}
+
+func NewConfigExtendsResolver(client vcsclient.VcsClient, clientInfo *ClientInfo) *ConfigExtendsResolver {
+	return &ConfigExtendsResolver{client: client, clientInfo: clientInfo, httpClient: &http.Client{Timeout: extendsFetchTimeout}, allowedExtends: getListEnv(ConfigAllowedExtendsEnv), cache: map[string][]byte{}, sources: map[*yaml.Node]string{}}
+}
+
+func (cer *ConfigExtendsResolver) SetRepositoryRoot(repositoryRoot string) *ConfigExtendsResolver {
+	cer.repositoryRoot = repositoryRoot
+	return cer
+}
+
+// ResolveConfigExtends merges the shared configuration referenced by the 'extends' key of each repository in the frogbot-config.yml content
+// into the repository params. Params set in the repository override the shared ones. Mappings are merged key by key, while lists and values are replaced.
+// 'extends' may be a file path relative to the root of the repository or an HTTP(S) URL, allowed by the JF_CONFIG_ALLOWED_EXTENDS
+// environment variable, or a mapping that points to a file in a Git repository.
+// The merged content is validated against the Frogbot configuration schema before it is marshaled, so that the issues point to the lines
+// of the original files. Returns the merged content, or the content unchanged if no repository extends a shared configuration,
+// along with the environment variables which are ignored because the merged params set the same parameters.
+func (cer *ConfigExtendsResolver) ResolveConfigExtends(configFileContent []byte) (resolved []byte, envOverrides []ConfigIssue, err error) {
+	var document yaml.Node
+	if err = yaml.Unmarshal(configFileContent, &document); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the %s file:\n%s", FrogbotConfigFile, err.Error())
+	}
+	extended := false
+	if repositories := getYamlNode(&document, nil); repositories != nil && repositories.Kind == yaml.SequenceNode {
+		for _, repository := range repositories.Content {
+			if getYamlNode(repository, []string{extendsKey}) == nil {
+				continue
+			}
+			if err = cer.resolveRepositoryExtends(repository, 0, map[string]bool{}); err != nil {
+				return nil, nil, err
+			}
+			extended = true
+		}
+	}
+	if err = validateConfigDocument(&document, cer.sources); err != nil {
+		return nil, nil, err
+	}
+	envOverrides = checkDocumentEnvOverrides(&document, cer.sources)
+	if !extended {
+		return configFileContent, envOverrides, nil
+	}
+	resolved, err = yaml.Marshal(&document)
+	return
+}
+
+// resolveRepositoryExtends replaces the params of the repository node with its params merged into the params of the shared configuration.
+func (cer *ConfigExtendsResolver) resolveRepositoryExtends(repository *yaml.Node, depth int, visited map[string]bool) error {
+	extendsNode := removeMappingKey(repository, extendsKey)
+	if extendsNode == nil {
+		return nil
+	}
+	if depth >= maxExtendsDepth {
+		return fmt.Errorf("the shared configurations in %s are extended more than %d levels deep", FrogbotConfigFile, maxExtendsDepth)
+	}
+	ref, content, err := cer.loadSharedConfig(extendsNode)
+	if err != nil {
+		return err
+	}
+	if visited[ref] {
+		return fmt.Errorf("the shared configuration %s extends itself", ref)
+	}
+	visited[ref] = true
+	log.Debug("Extending the", FrogbotConfigFile, "params with the shared configuration from", ref)
+
+	base, err := getSharedRepositoryNode(ref, content)
+	if err != nil {
+		return err
+	}
+	cer.setNodeSources(base, ref)
+	if err = cer.resolveRepositoryExtends(base, depth+1, visited); err != nil {
+		return err
+	}
+	baseParams := getYamlNode(base, []string{paramsKey})
+	if baseParams == nil {
+		return nil
+	}
+	params := getYamlNode(repository, []string{paramsKey})
+	if params == nil {
+		setMappingKey(repository, paramsKey, baseParams)
+		return nil
+	}
+	setMappingKey(repository, paramsKey, mergeYamlNodes(baseParams, params))
+	return nil
+}
+
+// setNodeSources records the shared configuration the node and its descendants were loaded from.
+func (cer *ConfigExtendsResolver) setNodeSources(node *yaml.Node, ref string) {
+	cer.sources[node] = ref
+	for _, child := range node.Content {
+		cer.setNodeSources(child, ref)
+	}
+}
+
+// getSharedRepositoryNode returns the repository node of the shared configuration.
+// A shared configuration is either a single repository mapping, or a frogbot-config.yml file, of which the first repository is used.
+func getSharedRepositoryNode(ref string, content []byte) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse the shared configuration %s:\n%s", ref, err.Error())
+	}
+	root := getYamlNode(&document, nil)
+	if root != nil && root.Kind == yaml.SequenceNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the shared configuration %s is expected to include a 'params' section", ref)
+	}
+	return root, nil
+}
+
+// loadSharedConfig returns the reference and the raw content of the shared configuration the 'extends' node points to.
+func (cer *ConfigExtendsResolver) loadSharedConfig(extendsNode *yaml.Node) (ref string, content []byte, err error) {
+	var load func() ([]byte, error)
+	switch extendsNode.Kind {
+	case yaml.ScalarNode:
+		ref = strings.TrimSpace(extendsNode.Value)
+		if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+			if err = cer.checkUrlAllowed(ref); err != nil {
+				return
+			}
+			load = func() ([]byte, error) { return cer.downloadFromUrl(ref) }
+		} else {
+			if ref, err = cer.checkFileAllowed(ref); err != nil {
+				return
+			}
+			load = func() ([]byte, error) { return cer.readRepositoryFile(ref) }
+		}
+	case yaml.MappingNode:
+		var repositoryRef extendsRepositoryRef
+		if err = extendsNode.Decode(&repositoryRef); err != nil {
+			return
+		}
+		if repositoryRef.Repository == "" {
+			err = fmt.Errorf("line %d: the '%s' key must include the repository of the shared configuration", extendsNode.Line, extendsKey)
+			return
+		}
+		if repositoryRef.Owner == "" && cer.clientInfo != nil {
+			repositoryRef.Owner = cer.clientInfo.RepoOwner
+		}
+		if repositoryRef.Path == "" {
+			repositoryRef.Path = FrogbotConfigDir + "/" + FrogbotConfigFile
+		}
+		ref = repositoryRef.String()
+		load = func() ([]byte, error) { return cer.downloadFromRepository(repositoryRef) }
+	default:
+		err = fmt.Errorf("line %d: the '%s' key must be a file path, a URL, or a repository mapping", extendsNode.Line, extendsKey)
+		return
+	}
+	if cached, exists := cer.cache[ref]; exists {
+		return ref, cached, nil
+	}
+	if content, err = load(); err != nil {
+		err = fmt.Errorf("failed to load the shared configuration %s:\n%s", ref, err.Error())
+		return
+	}
+	cer.cache[ref] = content
+	return
+}
+
+// checkFileAllowed returns the cleaned path of a shared configuration file, if it is relative to the root of the repository
+// and allowed by the JF_CONFIG_ALLOWED_EXTENDS environment variable.
+func (cer *ConfigExtendsResolver) checkFileAllowed(filePath string) (string, error) {
+	slashPath := filepath.ToSlash(filePath)
+	if filepath.IsAbs(filePath) || path.IsAbs(slashPath) || filepath.VolumeName(filePath) != "" {
+		return "", fmt.Errorf("the shared configuration %s must be relative to the root of the repository", filePath)
+	}
+	for _, segment := range strings.Split(slashPath, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("the shared configuration %s must be inside the repository", filePath)
+		}
+	}
+	cleanPath := path.Clean(slashPath)
+	if !cer.isExtendsAllowed(cleanPath) {
+		return "", fmt.Errorf("the shared configuration %s isn't allowed to be extended. Add it to the %s environment variable to allow it", filePath, ConfigAllowedExtendsEnv)
+	}
+	return cleanPath, nil
+}
+
+// checkUrlAllowed checks that the URL of a shared configuration is allowed by the JF_CONFIG_ALLOWED_EXTENDS environment variable.
+// The URL is matched without its credentials, query and fragment, which therefore aren't allowed.
+func (cer *ConfigExtendsResolver) checkUrlAllowed(rawUrl string) error {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return fmt.Errorf("the shared configuration URL %s is invalid: %s", rawUrl, err.Error())
+	}
+	if parsedUrl.User != nil || parsedUrl.RawQuery != "" || parsedUrl.Fragment != "" {
+		return fmt.Errorf("the shared configuration URL %s may not include credentials, a query or a fragment", rawUrl)
+	}
+	if !cer.isExtendsAllowed(parsedUrl.Scheme + "://" + parsedUrl.Host + parsedUrl.EscapedPath()) {
+		return fmt.Errorf("the shared configuration %s isn't allowed to be extended. Add it to the %s environment variable to allow it", rawUrl, ConfigAllowedExtendsEnv)
+	}
+	return nil
+}
+
+func (cer *ConfigExtendsResolver) isExtendsAllowed(ref string) bool {
+	refSegments := strings.Split(ref, "/")
+	for _, pattern := range cer.allowedExtends {
+		if matchPathSegments(strings.Split(filepath.ToSlash(pattern), "/"), refSegments) {
+			return true
+		}
+	}
+	return false
+}
+
+// readRepositoryFile returns the content of a file, relative to the root of the repository the frogbot-config.yml file was read from.
+func (cer *ConfigExtendsResolver) readRepositoryFile(filePath string) ([]byte, error) {
+	if cer.repositoryRoot == "" {
+		if cer.clientInfo == nil || cer.clientInfo.RepoName == "" {
+			return nil, errors.New("the root of the repository is unknown")
+		}
+		var branch string
+		if len(cer.clientInfo.Branches) > 0 {
+			branch = cer.clientInfo.Branches[0]
+		}
+		return cer.downloadFromRepository(extendsRepositoryRef{Owner: cer.clientInfo.RepoOwner, Repository: cer.clientInfo.RepoName, Branch: branch, Path: filePath})
+	}
+	root, err := filepath.EvalSymlinks(cer.repositoryRoot)
+	if err != nil {
+		return nil, err
+	}
+	fullPath, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(filePath)))
+	if err != nil {
+		return nil, err
+	}
+	// Symbolic links may point outside the repository
+	if relativePath, err := filepath.Rel(root, fullPath); err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is outside the repository", filePath)
+	}
+	return os.ReadFile(fullPath)
+}
+
+func (cer *ConfigExtendsResolver) downloadFromUrl(url string) (content []byte, err error) {
+	resp, err := cer.httpClient.Get(url)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (cer *ConfigExtendsResolver) downloadFromRepository(ref extendsRepositoryRef) ([]byte, error) {
+	if cer.client == nil {
+		return nil, errors.New("shared configurations can be downloaded from a Git repository only when the Git provider details are set")
+	}
+	content, statusCode, err := cer.client.DownloadFileFromRepo(context.Background(), ref.Owner, ref.Repository, ref.Branch, ref.Path)
+	if statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s wasn't found in the %s repository", ref.Path, ref.Repository)
+	}
+	return content, err
+}
+
+// mergeYamlNodes returns a new node with the override node merged into the base node.
+// Mappings are merged recursively, while any other node in override replaces the base node.
+func mergeYamlNodes(base, override *yaml.Node) *yaml.Node {
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+	merged := *base
+	merged.Content = append([]*yaml.Node{}, base.Content...)
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		if baseValue := getYamlNode(&merged, []string{key.Value}); baseValue != nil {
+			setMappingKey(&merged, key.Value, mergeYamlNodes(baseValue, value))
+			continue
+		}
+		merged.Content = append(merged.Content, key, value)
+	}
+	return &merged
+}
+
+// setMappingKey sets the value of an existing key in the mapping node, or appends the key if it doesn't exist.
+func setMappingKey(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// removeMappingKey removes the key from the mapping node, and returns its value, or nil if the key doesn't exist.
+func removeMappingKey(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sharedConfig = `- params:
+    git:
+      repoName: frogbot-defaults
+      branches:
+        - main
+      commitMessageTemplate: "Upgrade ${IMPACTED_PACKAGE}"
+    scan:
+      minSeverity: High
+      failOnSecurityIssues: false
+      fixableOnly: true
+      projects:
+        - workingDirs:
+            - shared
+`
+
+// Serves files from a map of owner/repository/branch/path to content
+type fileVcsClient struct {
+	vcsclient.VcsClient
+	files map[string]string
+}
+
+func (fc *fileVcsClient) DownloadFileFromRepo(_ context.Context, owner, repository, branch, path string) ([]byte, int, error) {
+	content, exists := fc.files[fmt.Sprintf("%s/%s/%s/%s", owner, repository, branch, path)]
+	if !exists {
+		return nil, http.StatusNotFound, fmt.Errorf("%s not found", path)
+	}
+	return []byte(content), http.StatusOK, nil
+}
+
+func resolveAndUnmarshal(t *testing.T, resolver *ConfigExtendsResolver, configContent string) RepoAggregator {
+	resolved, _, err := resolver.ResolveConfigExtends([]byte(configContent))
+	assert.NoError(t, err)
+	var config RepoAggregator
+	assert.NoError(t, yaml.Unmarshal(resolved, &config))
+	return config
+}
+
+func assertExtendedConfig(t *testing.T, config RepoAggregator) {
+	assert.Len(t, config, 1)
+	repository := config[0]
+	// Set in the repository
+	assert.Equal(t, "my-repo", repository.RepoName)
+	assert.Equal(t, "Low", repository.MinSeverity)
+	// Inherited from the shared configuration
+	assert.Equal(t, []string{"main"}, repository.Branches)
+	assert.Equal(t, "Upgrade ${IMPACTED_PACKAGE}", repository.CommitMessageTemplate)
+	assert.True(t, repository.FixableOnly)
+	assert.False(t, *repository.FailOnSecurityIssues)
+	assert.Equal(t, []Project{{WorkingDirs: []string{"shared"}}}, repository.Projects)
+}
+
+func TestResolveConfigExtendsLocalFile(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{ConfigAllowedExtendsEnv: "shared/*.yml"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	repositoryRoot := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(repositoryRoot, "shared"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(repositoryRoot, "shared", "frogbot-defaults.yml"), []byte(sharedConfig), 0644))
+	configContent := `- extends: shared/frogbot-defaults.yml
+  params:
+    git:
+      repoName: my-repo
+    scan:
+      minSeverity: Low
+`
+	// The path is resolved from the root of the repository, rather than from the working directory
+	config := resolveAndUnmarshal(t, NewConfigExtendsResolver(nil, nil).SetRepositoryRoot(repositoryRoot), configContent)
+	assertExtendedConfig(t, config)
+
+	// A config downloaded from the scanned repository extends the files of that repository
+	client := &fileVcsClient{files: map[string]string{"jfrog/my-repo/master/shared/frogbot-defaults.yml": sharedConfig}}
+	config = resolveAndUnmarshal(t, NewConfigExtendsResolver(client, &ClientInfo{RepoOwner: "jfrog", RepoName: "my-repo", Branches: []string{"master"}}), configContent)
+	assertExtendedConfig(t, config)
+}
+
+func TestResolveConfigExtendsNotAllowed(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{ConfigAllowedExtendsEnv: "shared/*.yml, https://config.example.com/**, **/link.yml"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	repositoryRoot := t.TempDir()
+	outsideConfigPath := filepath.Join(t.TempDir(), "outside.yml")
+	assert.NoError(t, os.WriteFile(outsideConfigPath, []byte(sharedConfig), 0644))
+	if os.Symlink(outsideConfigPath, filepath.Join(repositoryRoot, "link.yml")) != nil {
+		// Symbolic links may be unsupported, such as on Windows without the required privileges
+		assert.NoError(t, os.WriteFile(filepath.Join(repositoryRoot, "link.yml"), []byte("extends: ../outside.yml\n"), 0644))
+	}
+
+	tests := []struct {
+		name        string
+		extends     string
+		expectedErr string
+	}{
+		{name: "Not allowed file", extends: "other/frogbot-defaults.yml", expectedErr: "isn't allowed to be extended"},
+		{name: "Absolute path", extends: outsideConfigPath, expectedErr: "must be relative to the root of the repository"},
+		{name: "Parent directory", extends: "shared/../../outside.yml", expectedErr: "must be inside the repository"},
+		{name: "Link outside the repository", extends: "link.yml", expectedErr: "outside"},
+		{name: "Not allowed URL", extends: "https://evil.example.com/frogbot-defaults.yml", expectedErr: "isn't allowed to be extended"},
+		{name: "URL with credentials", extends: "https://config.example.com@evil.example.com/frogbot-defaults.yml", expectedErr: "may not include credentials"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := NewConfigExtendsResolver(nil, nil).SetRepositoryRoot(repositoryRoot).ResolveConfigExtends([]byte("- extends: " + test.extends + "\n"))
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestResolveConfigExtendsUrl(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/frogbot-defaults.yml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// A shared configuration with a single params section
+		_, _ = w.Write([]byte(`params:
+  git:
+    branches:
+      - main
+    commitMessageTemplate: "Upgrade ${IMPACTED_PACKAGE}"
+  scan:
+    minSeverity: High
+    failOnSecurityIssues: false
+    fixableOnly: true
+    projects:
+      - workingDirs:
+          - shared
+`))
+	}))
+	defer server.Close()
+	SetEnvAndAssert(t, map[string]string{ConfigAllowedExtendsEnv: server.URL + "/*.yml"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+
+	configContent := fmt.Sprintf(`- extends: %[1]s/frogbot-defaults.yml
+  params:
+    git:
+      repoName: my-repo
+    scan:
+      minSeverity: Low
+- extends: %[1]s/frogbot-defaults.yml
+  params:
+    git:
+      repoName: my-repo
+    scan:
+      minSeverity: Low
+`, server.URL)
+	config := resolveAndUnmarshal(t, NewConfigExtendsResolver(nil, nil), configContent)
+	assertExtendedConfig(t, config[:1])
+	assertExtendedConfig(t, config[1:])
+	// The shared configuration is downloaded once
+	assert.Equal(t, 1, requests)
+
+	_, _, err := NewConfigExtendsResolver(nil, nil).ResolveConfigExtends([]byte(fmt.Sprintf("- extends: %s/missing.yml\n", server.URL)))
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestResolveConfigExtendsRepository(t *testing.T) {
+	client := &fileVcsClient{files: map[string]string{
+		"jfrog/security-config/main/.frogbot/frogbot-config.yml": `- extends:
+    repository: base-config
+    path: base.yml
+  params:
+    git:
+      repoName: security-config
+      branches:
+        - main
+    scan:
+      minSeverity: High
+      failOnSecurityIssues: false
+`,
+		"jfrog/base-config//base.yml": `params:
+  git:
+    commitMessageTemplate: "Upgrade ${IMPACTED_PACKAGE}"
+  scan:
+    minSeverity: Critical
+    fixableOnly: true
+    projects:
+      - workingDirs:
+          - shared
+`,
+	}}
+	resolver := NewConfigExtendsResolver(client, &ClientInfo{RepoOwner: "jfrog"})
+	config := resolveAndUnmarshal(t, resolver, `- extends:
+    repository: security-config
+    branch: main
+  params:
+    git:
+      repoName: my-repo
+    scan:
+      minSeverity: Low
+`)
+	assertExtendedConfig(t, config)
+
+	// Without the Git provider details, shared configurations can't be downloaded from repositories
+	_, _, err := NewConfigExtendsResolver(nil, nil).ResolveConfigExtends([]byte("- extends:\n    repository: security-config\n"))
+	assert.ErrorContains(t, err, "Git provider details")
+}
+
+func TestResolveConfigExtendsErrors(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{ConfigAllowedExtendsEnv: "*.yml"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	repositoryRoot := t.TempDir()
+	cyclicConfigPath := "cyclic.yml"
+	assert.NoError(t, os.WriteFile(filepath.Join(repositoryRoot, cyclicConfigPath), []byte(fmt.Sprintf("extends: %s\nparams:\n  scan:\n    minSeverity: High\n", cyclicConfigPath)), 0644))
+	sharedConfigPath := "shared.yml"
+	assert.NoError(t, os.WriteFile(filepath.Join(repositoryRoot, sharedConfigPath), []byte("params:\n  scan:\n    minSevrity: High\n"), 0644))
+
+	tests := []struct {
+		name          string
+		configContent string
+		expectedErr   string
+	}{
+		{
+			name:          "Cyclic extends",
+			configContent: fmt.Sprintf("- extends: %s\n  params:\n    git:\n      repoName: my-repo\n", cyclicConfigPath),
+			expectedErr:   "extends itself",
+		},
+		{
+			name:          "Missing file",
+			configContent: "- extends: not-exists.yml\n",
+			expectedErr:   "failed to load the shared configuration not-exists.yml",
+		},
+		{
+			name:          "Invalid extends",
+			configContent: "- extends: [a, b]\n",
+			expectedErr:   "must be a file path, a URL, or a repository mapping",
+		},
+		{
+			name:          "Invalid merged config",
+			configContent: fmt.Sprintf("- extends: %s\n  params:\n    git:\n      repoName: my-repo\n      branches:\n        - master\n", sharedConfigPath),
+			expectedErr:   "shared.yml, line 3: 0.params.scan: Additional property minSevrity is not allowed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := NewConfigExtendsResolver(nil, nil).SetRepositoryRoot(repositoryRoot).ResolveConfigExtends([]byte(test.configContent))
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestResolveConfigExtendsIssueLines(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{ConfigAllowedExtendsEnv: "shared.yml", MinSeverityEnv: "Low", InstallCommandEnv: "npm i"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	repositoryRoot := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(repositoryRoot, "shared.yml"), []byte(sharedConfig), 0644))
+	// The params of the shared configuration precede the params of the repository in the merged content, which shifts their lines
+	configContent := `- extends: shared.yml
+  params:
+    git:
+      repoName: my-repo
+    scan:
+      projects:
+        - installCommand: npm ci
+`
+	_, envOverrides, err := NewConfigExtendsResolver(nil, nil).SetRepositoryRoot(repositoryRoot).ResolveConfigExtends([]byte(configContent))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []ConfigIssue{
+		{Line: 8, File: "shared.yml", Path: "0.params.scan.minSeverity", Message: "the JF_MIN_SEVERITY environment variable is ignored, since the value is set in the frogbot-config.yml file"},
+		{Line: 7, Path: "0.params.scan.projects.0.installCommand", Message: "the JF_INSTALL_DEPS_CMD environment variable is ignored, since the value is set in the frogbot-config.yml file"},
+	}, envOverrides)
+
+	_, _, err = NewConfigExtendsResolver(nil, nil).SetRepositoryRoot(repositoryRoot).ResolveConfigExtends([]byte(configContent + "      minSevrity: Low\n"))
+	var invalidConfigErr *ErrInvalidConfig
+	assert.ErrorAs(t, err, &invalidConfigErr)
+	assert.Equal(t, []ConfigIssue{{Line: 8, Path: "0.params.scan", Message: "Additional property minSevrity is not allowed"}}, invalidConfigErr.Issues)
+}
+
+func TestResolveConfigExtendsNoExtends(t *testing.T) {
+	configContent, err := os.ReadFile(configParamsTestFile)
+	assert.NoError(t, err)
+	resolved, _, err := NewConfigExtendsResolver(nil, nil).ResolveConfigExtends(configContent)
+	assert.NoError(t, err)
+	assert.Equal(t, configContent, resolved)
+}
@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"fmt"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const fileReferencePrefix = "file:"
+
+var (
+	// Matches ${ENV_VAR} and ${file:/path} references, and the $${ escape sequence
+	configReferenceRegex = regexp.MustCompile(`\$\$\{|\$\{([^{}]*)\}`)
+	// The params in which references are interpolated, in the repository params and in each of its branch policies. "*" stands for every item of a list.
+	// Other params, such as the templates, are kept as is, since they include placeholders of their own, and their values are published in pull requests.
+	interpolatedParams = [][]string{
+		{"git", "repoName"},
+		{"git", "branches", "*"},
+		{"git", "emailAuthor"},
+		{"git", "authorName"},
+		{"git", "committerName"},
+		{"git", "committerEmail"},
+		{"jfrogPlatform", "jfrogProjectKey"},
+		{"jfrogPlatform", "watches", "*"},
+		{"scan", "minSeverity"},
+		{"scan", "projects", "*", "installCommand"},
+		{"scan", "projects", "*", "pipRequirementsFile"},
+		{"scan", "projects", "*", "workingDirs", "*"},
+		{"scan", "projects", "*", "repository"},
+		{"scan", "projects", "*", "jfrogProjectKey"},
+		{"scan", "projects", "*", "watches", "*"},
+		{"scan", "projects", "*", "verifyCommand"},
+		{"scan", "projects", "*", "minSeverity"},
+	}
+	// The Frogbot credentials can never be referenced, even if allowed by a pattern
+	deniedConfigEnvVars = []string{JFrogTokenEnv, JFrogPasswordEnv, GitTokenEnv, GitSshKeyEnv, GitSshKeyPassphraseEnv, GitHubAppPrivateKeyEnv, GitClientKeyEnv, GitSigningKeyEnv, GitSigningKeyPassphraseEnv}
+)
+
+// configInterpolator resolves references to the environment variables and files allowed by the JF_CONFIG_ALLOWED_ENV_VARS
+// and JF_CONFIG_ALLOWED_FILES environment variables. Both hold comma-separated glob patterns, such as NPM_* or /run/secrets/**.
+type configInterpolator struct {
+	allowedEnvVars []string
+	allowedFiles   []string
+}
+
+// interpolateConfig replaces the ${ENV_VAR} and ${file:/path} references in the params of the repositories in the frogbot-config.yml document
+// with the value of the environment variable or the trimmed content of the file. $${ is replaced with a literal ${.
+func interpolateConfig(document *yaml.Node) error {
+	repositories := getYamlNode(document, nil)
+	if repositories == nil || repositories.Kind != yaml.SequenceNode {
+		return nil
+	}
+	ci := &configInterpolator{allowedEnvVars: getListEnv(ConfigAllowedEnvVarsEnv), allowedFiles: getListEnv(ConfigAllowedFilesEnv)}
+	for _, repository := range repositories.Content {
+		params := getYamlNode(repository, []string{paramsKey})
+		if err := ci.interpolateParams(params); err != nil {
+			return err
+		}
+		// The values of the branch policies mapping are the policies, which hold the same sections as the params
+		if branchPolicies := getYamlNode(params, []string{"branchPolicies"}); branchPolicies != nil && branchPolicies.Kind == yaml.MappingNode {
+			for i := 1; i < len(branchPolicies.Content); i += 2 {
+				if err := ci.interpolateParams(branchPolicies.Content[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (ci *configInterpolator) interpolateParams(params *yaml.Node) error {
+	for _, paramPath := range interpolatedParams {
+		for _, node := range findYamlNodes(params, paramPath) {
+			if node.Kind != yaml.ScalarNode {
+				continue
+			}
+			value, err := ci.interpolate(node.Value)
+			if err != nil {
+				return fmt.Errorf("failed to interpolate the %s file, line %d: %s", FrogbotConfigFile, node.Line, err.Error())
+			}
+			node.Value = value
+		}
+	}
+	return nil
+}
+
+// findYamlNodes returns the value nodes at the end of the path, where "*" matches every item of a sequence.
+func findYamlNodes(node *yaml.Node, path []string) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if len(path) == 0 {
+		return []*yaml.Node{node}
+	}
+	if path[0] != "*" {
+		return findYamlNodes(getYamlNode(node, path[:1]), path[1:])
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var nodes []*yaml.Node
+	for _, item := range node.Content {
+		nodes = append(nodes, findYamlNodes(item, path[1:])...)
+	}
+	return nodes
+}
+
+func (ci *configInterpolator) interpolate(value string) (string, error) {
+	var err error
+	interpolated := configReferenceRegex.ReplaceAllStringFunc(value, func(match string) string {
+		if err != nil {
+			return match
+		}
+		if match == "$${" {
+			return "${"
+		}
+		var resolved string
+		resolved, err = ci.resolve(strings.TrimSpace(match[2 : len(match)-1]))
+		return resolved
+	})
+	return interpolated, err
+}
+
+func (ci *configInterpolator) resolve(reference string) (string, error) {
+	if strings.HasPrefix(reference, fileReferencePrefix) {
+		return ci.readFile(strings.TrimSpace(strings.TrimPrefix(reference, fileReferencePrefix)))
+	}
+	return ci.getEnv(reference)
+}
+
+func (ci *configInterpolator) getEnv(envVar string) (string, error) {
+	if slices.Contains(deniedConfigEnvVars, envVar) || !matchAnyPattern(ci.allowedEnvVars, envVar) {
+		return "", fmt.Errorf("the %s environment variable isn't allowed to be referenced. Add it to the %s environment variable to allow it", envVar, ConfigAllowedEnvVarsEnv)
+	}
+	value, exists := os.LookupEnv(envVar)
+	if !exists {
+		return "", fmt.Errorf("the referenced %s environment variable isn't set", envVar)
+	}
+	return value, nil
+}
+
+func (ci *configInterpolator) readFile(filePath string) (string, error) {
+	absolutePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", err
+	}
+	if !ci.isFileAllowed(absolutePath) {
+		return "", fmt.Errorf("the %s file isn't allowed to be referenced. Add it to the %s environment variable to allow it", filePath, ConfigAllowedFilesEnv)
+	}
+	content, err := os.ReadFile(absolutePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the referenced file: %s", err.Error())
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func (ci *configInterpolator) isFileAllowed(absolutePath string) bool {
+	pathSegments := strings.Split(filepath.ToSlash(absolutePath), "/")
+	for _, pattern := range ci.allowedFiles {
+		absolutePattern, err := filepath.Abs(pattern)
+		if err != nil {
+			continue
+		}
+		if matchPathSegments(strings.Split(filepath.ToSlash(absolutePattern), "/"), pathSegments) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
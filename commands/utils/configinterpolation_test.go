@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInterpolateConfig(t *testing.T) {
+	secretsDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(secretsDir, "npm-token"), []byte("s3cr3t\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(secretsDir, "other"), []byte("other"), 0600))
+	SetEnvAndAssert(t, map[string]string{
+		ConfigAllowedEnvVarsEnv:  "NPM_*, FROGBOT_TEST_REPO_NAME",
+		ConfigAllowedFilesEnv:    filepath.Join(secretsDir, "npm-*"),
+		"NPM_REGISTRY":           "https://registry.example.com",
+		"FROGBOT_TEST_REPO_NAME": "frogbot",
+		"OTHER_ENV":              "other",
+	})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+		assert.NoError(t, os.Unsetenv("NPM_REGISTRY"))
+		assert.NoError(t, os.Unsetenv("FROGBOT_TEST_REPO_NAME"))
+		assert.NoError(t, os.Unsetenv("OTHER_ENV"))
+	}()
+	tokenFile := filepath.ToSlash(filepath.Join(secretsDir, "npm-token"))
+
+	tests := []struct {
+		name           string
+		installCommand string
+		expected       string
+		expectedErr    string
+	}{
+		{name: "No references", installCommand: "npm ci", expected: "npm ci"},
+		{name: "Environment variable", installCommand: "npm ci --registry ${NPM_REGISTRY}", expected: "npm ci --registry https://registry.example.com"},
+		{name: "File", installCommand: "npm ci --token ${file:" + tokenFile + "}", expected: "npm ci --token s3cr3t"},
+		{name: "Escaped reference", installCommand: "npm ci $${NPM_REGISTRY}", expected: "npm ci ${NPM_REGISTRY}"},
+		{name: "Environment variable not allowed", installCommand: "npm ci ${OTHER_ENV}", expectedErr: "the OTHER_ENV environment variable isn't allowed to be referenced"},
+		{name: "Frogbot credentials", installCommand: "npm ci ${JF_GIT_TOKEN}", expectedErr: "the JF_GIT_TOKEN environment variable isn't allowed to be referenced"},
+		{name: "Missing environment variable", installCommand: "npm ci ${NPM_MISSING}", expectedErr: "the referenced NPM_MISSING environment variable isn't set"},
+		{name: "File not allowed", installCommand: "npm ci ${file:" + filepath.ToSlash(filepath.Join(secretsDir, "other")) + "}", expectedErr: "isn't allowed to be referenced"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configContent := `- params:
+    git:
+      repoName: ${FROGBOT_TEST_REPO_NAME}
+      branches:
+        - master
+      commitMessageTemplate: "Upgrade ${IMPACTED_PACKAGE}"
+    scan:
+      projects:
+        - installCommand: "` + test.installCommand + `"
+`
+			aggregator, err := BuildRepoAggregator([]byte(configContent), &Git{}, &config.ServerDetails{})
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				assert.ErrorContains(t, err, "line 9")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "frogbot", aggregator[0].RepoName)
+			assert.Equal(t, test.expected, aggregator[0].Projects[0].InstallCommand)
+			// Params which aren't interpolated are kept as is
+			assert.Equal(t, "Upgrade ${IMPACTED_PACKAGE}", aggregator[0].CommitMessageTemplate)
+		})
+	}
+}
+
+func TestInterpolateConfigProjectsAndBranchPolicies(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{
+		ConfigAllowedEnvVarsEnv: "FROGBOT_TEST_*",
+		"FROGBOT_TEST_SEVERITY": "High",
+		"FROGBOT_TEST_VERIFY":   "npm test",
+		"FROGBOT_TEST_EMAIL":    "release@company.info",
+	})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+		assert.NoError(t, os.Unsetenv("FROGBOT_TEST_SEVERITY"))
+		assert.NoError(t, os.Unsetenv("FROGBOT_TEST_VERIFY"))
+		assert.NoError(t, os.Unsetenv("FROGBOT_TEST_EMAIL"))
+	}()
+	configContent := `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - master
+    scan:
+      minSeverity: ${FROGBOT_TEST_SEVERITY}
+      projects:
+        - verifyCommand: ${FROGBOT_TEST_VERIFY}
+          minSeverity: ${FROGBOT_TEST_SEVERITY}
+    branchPolicies:
+      "release/*":
+        scan:
+          minSeverity: ${FROGBOT_TEST_SEVERITY}
+        git:
+          emailAuthor: ${FROGBOT_TEST_EMAIL}
+          pullRequestTitleTemplate: "[release] ${IMPACTED_PACKAGE}"
+`
+	aggregator, err := BuildRepoAggregator([]byte(configContent), &Git{}, &config.ServerDetails{})
+	assert.NoError(t, err)
+	assert.Equal(t, "High", aggregator[0].MinSeverity)
+	assert.Equal(t, "npm test", aggregator[0].Projects[0].VerifyCommand)
+	assert.Equal(t, "High", aggregator[0].Projects[0].MinSeverity)
+
+	repository, err := aggregator[0].WithBranchPolicies("release/1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "High", repository.MinSeverity)
+	assert.Equal(t, "release@company.info", repository.EmailAuthor)
+	assert.Equal(t, "[release] ${IMPACTED_PACKAGE}", repository.PullRequestTitleTemplate)
+
+	// References in the branch policies are checked even if no branch matches the policies
+	_, err = BuildRepoAggregator([]byte(strings.Replace(configContent, "${FROGBOT_TEST_EMAIL}", "${OTHER_ENV}", 1)), &Git{}, &config.ServerDetails{})
+	assert.ErrorContains(t, err, "line 16: the OTHER_ENV environment variable isn't allowed to be referenced")
+}
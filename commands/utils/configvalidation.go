@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/jfrog/frogbot/schema"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+const rootField = "(root)"
+
+// ConfigIssue describes a single problem found in the frogbot-config.yml file.
+type ConfigIssue struct {
+	// The line in the frogbot-config.yml file, or 0 if unknown
+	Line int
+	// The shared configuration the issue is in, or empty if it is in the frogbot-config.yml file
+	File string
+	// The path of the field in the file, such as 0.params.scan.minSeverity
+	Path    string
+	Message string
+}
+
+func (ci ConfigIssue) String() string {
+	if ci.File != "" {
+		return fmt.Sprintf("%s, line %d: %s: %s", ci.File, ci.Line, ci.Path, ci.Message)
+	}
+	if ci.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", ci.Line, ci.Path, ci.Message)
+	}
+	return fmt.Sprintf("%s: %s", ci.Path, ci.Message)
+}
+
+type ErrInvalidConfig struct {
+	Issues []ConfigIssue
+}
+
+func (e *ErrInvalidConfig) Error() string {
+	var issues []string
+	for _, issue := range e.Issues {
+		issues = append(issues, issue.String())
+	}
+	return fmt.Sprintf("the %s file doesn't match the Frogbot configuration schema:\n%s", FrogbotConfigFile, strings.Join(issues, "\n"))
+}
+
+// A config file field that takes precedence over an environment variable.
+// Boolean fields that are only overridden when set to true aren't listed.
+type envOverride struct {
+	env  string
+	path []string
+}
+
+var (
+	repositoryEnvOverrides = []envOverride{
+		{BranchNameTemplateEnv, []string{"git", "branchNameTemplate"}},
+		{CommitMessageTemplateEnv, []string{"git", "commitMessageTemplate"}},
+		{PullRequestTitleTemplateEnv, []string{"git", "pullRequestTitleTemplate"}},
+		{GitEmailAuthorEnv, []string{"git", "emailAuthor"}},
+		{GitAuthorNameEnv, []string{"git", "authorName"}},
+		{GitCommitterNameEnv, []string{"git", "committerName"}},
+		{GitCommitterEmailEnv, []string{"git", "committerEmail"}},
+		{GitMaxOpenPullRequestsEnv, []string{"git", "maxOpenPullRequests"}},
+		{GitPullRequestLabelsEnv, []string{"git", "pullRequestLabels"}},
+		{GitPullRequestReviewersEnv, []string{"git", "pullRequestReviewers"}},
+		{GitPullRequestAssigneesEnv, []string{"git", "pullRequestAssignees"}},
+		{GitCodeOwnersReviewersEnv, []string{"git", "codeOwnersReviewers"}},
+		{GitFixVersionStrategyEnv, []string{"git", "fixVersionStrategy"}},
+		{GitAutoMergeEnv, []string{"git", "autoMerge", "enabled"}},
+		{GitAutoMergeUpdateTypesEnv, []string{"git", "autoMerge", "updateTypes"}},
+		{GitAutoMergeNotApplicableOnlyEnv, []string{"git", "autoMerge", "notApplicableOnly"}},
+		{GitAutoMergeMethodEnv, []string{"git", "autoMerge", "mergeMethod"}},
+		{GitFailedVerificationActionEnv, []string{"git", "failedVerificationAction"}},
+		{FailOnSecurityIssuesEnv, []string{"scan", "failOnSecurityIssues"}},
+		{MinSeverityEnv, []string{"scan", "minSeverity"}},
+		{jfrogWatchesEnv, []string{"jfrogPlatform", "watches"}},
+		{jfrogProjectEnv, []string{"jfrogPlatform", "jfrogProjectKey"}},
+	}
+	projectEnvOverrides = []envOverride{
+		{InstallCommandEnv, []string{"installCommand"}},
+		{RequirementsFileEnv, []string{"pipRequirementsFile"}},
+		{WorkingDirectoryEnv, []string{"workingDirs"}},
+		{UseWrapperEnv, []string{"useWrapper"}},
+		{DepsRepoEnv, []string{"repository"}},
+		{VerifyCommandEnv, []string{"verifyCommand"}},
+		{VerifyCommandTimeoutEnv, []string{"verifyCommandTimeout"}},
+	}
+)
+
+// ValidateConfigSchema validates the frogbot-config.yml content against the Frogbot configuration schema.
+// Returns ErrInvalidConfig with the line and path of each issue if the content is invalid.
+func ValidateConfigSchema(configFileContent []byte) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(configFileContent, &document); err != nil {
+		return fmt.Errorf("failed to parse the %s file:\n%s", FrogbotConfigFile, err.Error())
+	}
+	return validateConfigDocument(&document, nil)
+}
+
+// validateConfigDocument validates the parsed frogbot-config.yml content.
+// sources maps the nodes merged from shared configurations to the files they were loaded from, to locate their issues.
+func validateConfigDocument(document *yaml.Node, sources map[*yaml.Node]string) error {
+	var config interface{}
+	if err := document.Decode(&config); err != nil {
+		return fmt.Errorf("failed to parse the %s file:\n%s", FrogbotConfigFile, err.Error())
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema.FrogbotSchema), gojsonschema.NewGoLoader(config))
+	if err != nil {
+		return err
+	}
+	if result.Valid() {
+		return nil
+	}
+	invalidConfigErr := &ErrInvalidConfig{}
+	for _, resultErr := range result.Errors() {
+		path := splitFieldPath(resultErr.Field())
+		// Point to the exact key when the error refers to a specific property
+		if property, exists := resultErr.Details()["property"]; exists && resultErr.Type() == "additional_property_not_allowed" {
+			path = append(path, fmt.Sprint(property))
+		}
+		invalidConfigErr.Issues = append(invalidConfigErr.Issues, newConfigIssue(document, path, sources, resultErr.Field(), resultErr.Description()))
+	}
+	return invalidConfigErr
+}
+
+// CheckEnvOverrides returns an issue for each Frogbot environment variable that is ignored,
+// since the same parameter is set in the frogbot-config.yml file.
+func CheckEnvOverrides(configFileContent []byte) (issues []ConfigIssue, err error) {
+	var document yaml.Node
+	if err = yaml.Unmarshal(configFileContent, &document); err != nil {
+		return
+	}
+	return checkDocumentEnvOverrides(&document, nil), nil
+}
+
+func checkDocumentEnvOverrides(document *yaml.Node, sources map[*yaml.Node]string) (issues []ConfigIssue) {
+	repositories := getYamlNode(document, nil)
+	if repositories == nil || repositories.Kind != yaml.SequenceNode {
+		return
+	}
+	for repoIndex, repository := range repositories.Content {
+		params := getYamlNode(repository, []string{"params"})
+		for _, override := range repositoryEnvOverrides {
+			issues = appendEnvOverrideIssue(issues, params, override, sources, fmt.Sprintf("%d.params", repoIndex))
+		}
+		projects := getYamlNode(params, []string{"scan", "projects"})
+		if projects == nil {
+			continue
+		}
+		for projectIndex, project := range projects.Content {
+			for _, override := range projectEnvOverrides {
+				issues = appendEnvOverrideIssue(issues, project, override, sources, fmt.Sprintf("%d.params.scan.projects.%d", repoIndex, projectIndex))
+			}
+		}
+	}
+	return
+}
+
+func appendEnvOverrideIssue(issues []ConfigIssue, parent *yaml.Node, override envOverride, sources map[*yaml.Node]string, parentPath string) []ConfigIssue {
+	if getTrimmedEnv(override.env) == "" {
+		return issues
+	}
+	if getYamlNode(parent, override.path) == nil {
+		return issues
+	}
+	return append(issues, newConfigIssue(parent, override.path, sources, parentPath+"."+strings.Join(override.path, "."),
+		fmt.Sprintf("the %s environment variable is ignored, since the value is set in the %s file", override.env, FrogbotConfigFile)))
+}
+
+// newConfigIssue returns an issue located at the deepest node found along the path.
+// Nodes merged from shared configurations are located in the file they were loaded from.
+func newConfigIssue(node *yaml.Node, path []string, sources map[*yaml.Node]string, fieldPath, message string) ConfigIssue {
+	issue := ConfigIssue{Path: fieldPath, Message: message}
+	walkYamlPath(node, path, func(keyOrItem *yaml.Node) {
+		issue.Line = keyOrItem.Line
+		issue.File = sources[keyOrItem]
+	})
+	return issue
+}
+
+func splitFieldPath(field string) []string {
+	if field == rootField || field == "" {
+		return nil
+	}
+	return strings.Split(field, ".")
+}
+
+// getYamlNode returns the value node at the end of the path, or nil if the path doesn't exist.
+func getYamlNode(node *yaml.Node, path []string) *yaml.Node {
+	return walkYamlPath(node, path, nil)
+}
+
+// walkYamlPath follows the path of mapping keys and sequence indexes, starting from node.
+// The visit callback receives the key node (or the item node in sequences) of each path element found.
+func walkYamlPath(node *yaml.Node, path []string, visit func(*yaml.Node)) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node != nil && visit != nil {
+		visit(node)
+	}
+	for _, element := range path {
+		if node == nil {
+			return nil
+		}
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == element {
+					if visit != nil {
+						visit(node.Content[i])
+					}
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(element); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+				if visit != nil {
+					visit(next)
+				}
+			}
+		}
+		node = next
+	}
+	return node
+}
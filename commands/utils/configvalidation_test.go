@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestValidateConfigSchema(t *testing.T) {
+	tests := []struct {
+		name           string
+		configContent  string
+		expectedIssues []ConfigIssue
+	}{
+		{
+			name: "Valid config",
+			configContent: `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - master
+    scan:
+      minSeverity: High
+`,
+		},
+		{
+			name: "Misspelled key",
+			configContent: `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - master
+    scan:
+      fixableOnly: true
+      minSeverty: High
+`,
+			expectedIssues: []ConfigIssue{{Line: 8, Path: "0.params.scan", Message: "Additional property minSeverty is not allowed"}},
+		},
+		{
+			name: "Missing required key and wrong type",
+			configContent: `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - master
+- params:
+    git:
+      branches: master
+`,
+			expectedIssues: []ConfigIssue{
+				{Line: 7, Path: "1.params.git", Message: "repoName is required"},
+				{Line: 8, Path: "1.params.git.branches", Message: "Invalid type. Expected: array, given: string"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateConfigSchema([]byte(test.configContent))
+			if len(test.expectedIssues) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			invalidConfigErr, ok := err.(*ErrInvalidConfig)
+			assert.True(t, ok, err)
+			assert.ElementsMatch(t, test.expectedIssues, invalidConfigErr.Issues)
+		})
+	}
+}
+
+func TestValidateConfigSchemaParseError(t *testing.T) {
+	err := ValidateConfigSchema([]byte("- params:\n  git: [\n"))
+	assert.ErrorContains(t, err, "failed to parse the frogbot-config.yml file")
+}
+
+func TestCheckEnvOverrides(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{
+		MinSeverityEnv:    "Low",
+		InstallCommandEnv: "npm i",
+		jfrogProjectEnv:   "proj",
+	})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	configContent := `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - master
+    scan:
+      minSeverity: High
+      projects:
+        - workingDirs:
+            - a
+        - installCommand: nuget restore
+`
+	issues, err := CheckEnvOverrides([]byte(configContent))
+	assert.NoError(t, err)
+	assert.Equal(t, []ConfigIssue{
+		{Line: 7, Path: "0.params.scan.minSeverity", Message: "the JF_MIN_SEVERITY environment variable is ignored, since the value is set in the frogbot-config.yml file"},
+		{Line: 11, Path: "0.params.scan.projects.1.installCommand", Message: "the JF_INSTALL_DEPS_CMD environment variable is ignored, since the value is set in the frogbot-config.yml file"},
+	}, issues)
+}
+
+func TestValidateConfigFileContent(t *testing.T) {
+	configContent, err := os.ReadFile(configParamsTestFile)
+	assert.NoError(t, err)
+	assert.NoError(t, validateConfigFileContent(configContent))
+	assert.Error(t, validateConfigFileContent([]byte("- params:\n    gitt:\n")))
+}
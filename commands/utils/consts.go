@@ -33,7 +33,10 @@ const (
 	GitHub          vcsProvider = "github"
 	GitLab          vcsProvider = "gitlab"
 	BitbucketServer vcsProvider = "bitbucketServer"
+	BitbucketCloud  vcsProvider = "bitbucketCloud"
 	AzureRepos      vcsProvider = "azureRepos"
+	Gitea           vcsProvider = "gitea"
+	Forgejo         vcsProvider = "forgejo"
 
 	// Frogbot comments
 	RescanRequestComment = "rescan"
@@ -63,6 +66,7 @@ const (
 	InstallCommandEnv            = "JF_INSTALL_DEPS_CMD"
 	RequirementsFileEnv          = "JF_REQUIREMENTS_FILE"
 	WorkingDirectoryEnv          = "JF_WORKING_DIR"
+	AutoDiscoverEnv              = "JF_AUTO_DISCOVER"
 	jfrogWatchesEnv              = "JF_WATCHES"
 	jfrogProjectEnv              = "JF_PROJECT"
 	IncludeAllVulnerabilitiesEnv = "JF_INCLUDE_ALL_VULNERABILITIES"
@@ -80,6 +84,63 @@ const (
 	GitApiEndpointEnv    = "JF_GIT_API_ENDPOINT"
 	GitAggregateFixesEnv = "JF_GIT_AGGREGATE_FIXES"
 	GitEmailAuthorEnv    = "JF_GIT_EMAIL_AUTHOR"
+	GitMaxRetriesEnv     = "JF_GIT_MAX_RETRIES"
+	GitAuthorNameEnv     = "JF_GIT_AUTHOR_NAME"
+	GitCommitterNameEnv  = "JF_GIT_COMMITTER_NAME"
+	GitCommitterEmailEnv = "JF_GIT_COMMITTER_EMAIL"
+	GitBackendEnv        = "JF_GIT_BACKEND"
+	GitCloneFilterEnv    = "JF_GIT_CLONE_FILTER"
+	GitCloneDepthEnv     = "JF_GIT_CLONE_DEPTH"
+	GitSparseCheckoutEnv = "JF_GIT_SPARSE_CHECKOUT"
+
+	GitMaxOpenPullRequestsEnv = "JF_GIT_MAX_OPEN_PULL_REQUESTS"
+
+	// Fix pull requests assignments environment variables
+	GitPullRequestLabelsEnv    = "JF_GIT_PULL_REQUEST_LABELS"
+	GitPullRequestReviewersEnv = "JF_GIT_PULL_REQUEST_REVIEWERS"
+	GitPullRequestAssigneesEnv = "JF_GIT_PULL_REQUEST_ASSIGNEES"
+	GitCodeOwnersReviewersEnv  = "JF_GIT_CODEOWNERS_REVIEWERS"
+
+	// Fix pull requests auto-merge environment variables
+	GitAutoMergeEnv                  = "JF_GIT_AUTO_MERGE"
+	GitAutoMergeUpdateTypesEnv       = "JF_GIT_AUTO_MERGE_UPDATE_TYPES"
+	GitAutoMergeNotApplicableOnlyEnv = "JF_GIT_AUTO_MERGE_NOT_APPLICABLE_ONLY"
+	GitAutoMergeMethodEnv            = "JF_GIT_AUTO_MERGE_METHOD"
+
+	// The strategy of selecting the fix versions environment variable
+	GitFixVersionStrategyEnv = "JF_GIT_FIX_VERSION_STRATEGY"
+
+	// Fix verification environment variables
+	GitVerifyFixesEnv              = "JF_GIT_VERIFY_FIXES"
+	GitFailedVerificationActionEnv = "JF_GIT_FAILED_VERIFICATION_ACTION"
+	VerifyCommandEnv               = "JF_VERIFY_CMD"
+	VerifyCommandTimeoutEnv        = "JF_VERIFY_CMD_TIMEOUT"
+
+	// Commit signing environment variables
+	GitSigningKeyEnv           = "JF_GIT_SIGNING_KEY"
+	GitSigningKeyPassphraseEnv = "JF_GIT_SIGNING_KEY_PASSPHRASE"
+
+	// Git provider connection environment variables
+	GitProxyEnv      = "JF_GIT_PROXY"
+	GitCaCertEnv     = "JF_GIT_CA_CERT"
+	GitClientCertEnv = "JF_GIT_CLIENT_CERT"
+	GitClientKeyEnv  = "JF_GIT_CLIENT_KEY"
+
+	// Git SSH authentication environment variables
+	GitSshKeyEnv           = "JF_GIT_SSH_KEY"
+	GitSshKeyPassphraseEnv = "JF_GIT_SSH_KEY_PASSPHRASE"
+	GitSshKnownHostsEnv    = "JF_GIT_SSH_KNOWN_HOSTS"
+
+	// GitHub App authentication environment variables
+	GitHubAppIdEnv             = "JF_GITHUB_APP_ID"
+	GitHubAppInstallationIdEnv = "JF_GITHUB_APP_INSTALLATION_ID"
+	GitHubAppPrivateKeyEnv     = "JF_GITHUB_APP_PRIVATE_KEY"
+
+	// frogbot-config.yml interpolation environment variables
+	ConfigAllowedEnvVarsEnv = "JF_CONFIG_ALLOWED_ENV_VARS"
+	ConfigAllowedFilesEnv   = "JF_CONFIG_ALLOWED_FILES"
+	// The shared configuration files and URLs the frogbot-config.yml file is allowed to extend
+	ConfigAllowedExtendsEnv = "JF_CONFIG_ALLOWED_EXTENDS"
 
 	// Comment
 	vulnerabilitiesTableHeader        = "\n| SEVERITY                | DIRECT DEPENDENCIES                  | IMPACTED DEPENDENCY                   | FIXED VERSIONS                       |\n| :---------------------: | :----------------------------------: | :-----------------------------------: | :---------------------------------: |"
@@ -97,6 +158,7 @@ const (
 	PackagePlaceHolder    = "${IMPACTED_PACKAGE}"
 	FixVersionPlaceHolder = "${FIX_VERSION}"
 	BranchHashPlaceHolder = "${BRANCH_NAME_HASH}"
+	SeverityPlaceHolder   = "${SEVERITY}"
 
 	// Default naming templates
 	BranchNameTemplate            = "frogbot-" + PackagePlaceHolder + "-" + BranchHashPlaceHolder
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The strategies of selecting the versions that fix the vulnerable dependencies
+const (
+	// The lowest fix version, which may be of a newer major version
+	MinimalFixVersion = "minimal"
+	// The lowest fix version of the major version of the dependency
+	SameMajorFixVersion = "sameMajor"
+	// The lowest fix version of the minor version of the dependency
+	SameMinorFixVersion = "sameMinor"
+	// The highest fix version
+	LatestFixVersion = "latest"
+)
+
+var fixVersionStrategies = []string{MinimalFixVersion, SameMajorFixVersion, SameMinorFixVersion, LatestFixVersion}
+
+// getFixVersionStrategy returns the strategy, as it's spelled in the constants, or an error if the strategy is invalid.
+func getFixVersionStrategy(strategy string) (string, error) {
+	if strategy == "" {
+		return MinimalFixVersion, nil
+	}
+	for _, fixVersionStrategy := range fixVersionStrategies {
+		if strings.EqualFold(strategy, fixVersionStrategy) {
+			return fixVersionStrategy, nil
+		}
+	}
+	return "", fmt.Errorf("the fix version strategy '%s' is invalid. The following values are accepted: %s", strategy, strings.Join(fixVersionStrategies, ", "))
+}
+
+// IsFixVersionCompliant returns true if updating the dependency from the current version to the fix version complies with the strategy.
+func IsFixVersionCompliant(strategy, currentVersion, fixVersion string) bool {
+	switch strategy {
+	case SameMajorFixVersion:
+		return GetVersionUpdateType(currentVersion, fixVersion) != MajorUpdate
+	case SameMinorFixVersion:
+		return GetVersionUpdateType(currentVersion, fixVersion) == PatchUpdate
+	default:
+		return true
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetFixVersionStrategy(t *testing.T) {
+	for strategy, expected := range map[string]string{"": MinimalFixVersion, "samemajor": SameMajorFixVersion, "SameMinor": SameMinorFixVersion, "latest": LatestFixVersion} {
+		actual, err := getFixVersionStrategy(strategy)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+	_, err := getFixVersionStrategy("highest")
+	assert.Error(t, err)
+}
+
+func TestIsFixVersionCompliant(t *testing.T) {
+	assert.True(t, IsFixVersionCompliant(SameMajorFixVersion, "1.2.3", "1.4.0"))
+	assert.False(t, IsFixVersionCompliant(SameMajorFixVersion, "1.2.3", "2.0.0"))
+	assert.True(t, IsFixVersionCompliant(SameMinorFixVersion, "1.2.3", "1.2.9"))
+	assert.False(t, IsFixVersionCompliant(SameMinorFixVersion, "1.2.3", "1.3.0"))
+	assert.True(t, IsFixVersionCompliant(MinimalFixVersion, "1.2.3", "3.0.0"))
+	assert.True(t, IsFixVersionCompliant(LatestFixVersion, "1.2.3", "3.0.0"))
+}
@@ -12,15 +12,20 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
@@ -35,7 +40,9 @@ const (
 	githubHttpsFormat          = "%s/%s/%s.git"
 	gitLabHttpsFormat          = "%s/%s/%s.git"
 	bitbucketServerHttpsFormat = "%s/scm/%s/%s.git"
+	bitbucketCloudHttpsFormat  = "https://bitbucket.org/%s/%s.git"
 	azureDevopsHttpsFormat     = "https://%s@%s%s/_git/%s"
+	giteaHttpsFormat           = "%s/%s/%s.git"
 )
 
 type GitManager struct {
@@ -43,8 +50,12 @@ type GitManager struct {
 	repository *git.Repository
 	// remoteName is name of the Git remote server
 	remoteName string
-	// The authentication struct consisting a username/password
-	auth *githttp.BasicAuth
+	// The authentication of the remote operations, either a username/password or an SSH key
+	auth transport.AuthMethod
+	// The SSH URL of the repository, used when the remote URL of the local repository isn't an SSH URL
+	sshCloneUrl string
+	// The directories to check out, when the sparse checkout is enabled
+	sparseCheckoutDirs []string
 	// dryRun is used for testing purposes, mocking part of the git commands that requires networking
 	dryRun bool
 	// When dryRun is enabled, dryRunRepoPath specifies the repository local path to clone
@@ -55,6 +66,12 @@ type GitManager struct {
 	customTemplates CustomTemplates
 	// Git details
 	git *Git
+	// Retries remote git operations that failed due to transient errors
+	retryExecutor *retryExecutor
+	// Signs the commits, if a signing key is set
+	signer *commitSigner
+	// Runs the git operations with the git executable, if the git CLI backend is selected. Otherwise, go-git runs them.
+	cli *gitCli
 }
 
 type CustomTemplates struct {
@@ -66,18 +83,43 @@ type CustomTemplates struct {
 	pullRequestTitleTemplate string
 }
 
+// NewGitManager returns a GitManager of the local repository in projectPath.
+// If projectPath is empty, there's no local repository, and the repository is cloned from its HTTPS URL, or from the URL set with SetSshCloneUrl.
 func NewGitManager(dryRun bool, clonedRepoPath, projectPath, remoteName, token, username string, g *Git) (*GitManager, error) {
-	setGoGitCustomClient()
-	repository, err := git.PlainOpen(projectPath)
+	setGoGitCustomClient(g.getHttpTransport())
+	var repository *git.Repository
+	var err error
+	if projectPath != "" {
+		if repository, err = git.PlainOpen(projectPath); err != nil {
+			return nil, fmt.Errorf(".git folder was not found in the following path: %s. git error:\n%s", projectPath, err.Error())
+		}
+	}
+	auth, err := toGitAuth(token, username, g.SshAuth, g.githubApp)
 	if err != nil {
-		return nil, fmt.Errorf(".git folder was not found in the following path: %s. git error:\n%s", projectPath, err.Error())
+		return nil, err
 	}
-	basicAuth := toBasicAuth(token, username)
 	templates, err := loadCustomTemplates(g.CommitMessageTemplate, g.BranchNameTemplate, g.PullRequestTitleTemplate)
 	if err != nil {
 		return nil, err
 	}
-	return &GitManager{repository: repository, dryRunRepoPath: clonedRepoPath, remoteName: remoteName, auth: basicAuth, dryRun: dryRun, customTemplates: templates, git: g}, nil
+	signer, err := newCommitSigner(g.CommitSigningKey)
+	if err != nil {
+		return nil, err
+	}
+	var cli *gitCli
+	if g.GitBackend == GitCliBackend {
+		if cli, err = newGitCli(projectPath, auth, g); err != nil {
+			return nil, err
+		}
+	}
+	gm := &GitManager{repository: repository, dryRunRepoPath: clonedRepoPath, remoteName: remoteName, auth: auth, dryRun: dryRun, customTemplates: templates, git: g, retryExecutor: newRetryExecutor(g.MaxRetries), signer: signer, cli: cli}
+	if cli != nil && repository != nil {
+		// Cloned repositories take the remote URL from the clone
+		if cli.remoteUrl, err = gm.getRemoteUrl(); err != nil {
+			log.Debug("The git commands won't be authenticated, since the remote URL of the repository is unknown:", err.Error())
+		}
+	}
+	return gm, nil
 }
 
 func (gm *GitManager) CheckoutLocalBranch(branchName string) error {
@@ -91,6 +133,9 @@ func (gm *GitManager) CheckoutLocalBranch(branchName string) error {
 
 func (gm *GitManager) Clone(destinationPath, branchName string) error {
 	if gm.dryRun {
+		if gm.cli != nil {
+			gm.cli.repoPath = destinationPath
+		}
 		// "Clone" the repository from the testdata folder
 		return gm.dryRunClone(destinationPath)
 	}
@@ -106,13 +151,28 @@ func (gm *GitManager) Clone(destinationPath, branchName string) error {
 		branchName = "master"
 	}
 	log.Debug(fmt.Sprintf("Cloning repository with these details:\nClone url: %s remote name: %s, branch: %s", gitRemoteUrl, gm.remoteName, getFullBranchName(branchName)))
+	if gm.cli != nil {
+		if err = gm.retryRemoteOperation("git clone", func() error {
+			return gm.cli.clone(gitRemoteUrl, gm.remoteName, plumbing.ReferenceName(branchName).Short(), destinationPath, gm.sparseCheckoutDirs)
+		}); err != nil {
+			return fmt.Errorf("'git clone %s from %s' failed with error: %s", branchName, gitRemoteUrl, err.Error())
+		}
+		log.Debug(fmt.Sprintf("Project cloned from %s to %s", gitRemoteUrl, destinationPath))
+		return nil
+	}
 	cloneOptions := &git.CloneOptions{
 		URL:           gitRemoteUrl,
 		Auth:          gm.auth,
 		RemoteName:    gm.remoteName,
 		ReferenceName: getFullBranchName(branchName),
+		Depth:         gm.git.CloneDepth,
+		SingleBranch:  gm.git.CloneDepth > 0,
 	}
-	repo, err := git.PlainClone(destinationPath, false, cloneOptions)
+	var repo *git.Repository
+	err = gm.retryRemoteOperation("git clone", func() (e error) {
+		repo, e = git.PlainClone(destinationPath, false, cloneOptions)
+		return
+	})
 	if err != nil {
 		return fmt.Errorf("'git clone %s from %s' failed with error: %s", branchName, gitRemoteUrl, err.Error())
 	}
@@ -122,6 +182,13 @@ func (gm *GitManager) Clone(destinationPath, branchName string) error {
 }
 
 func (gm *GitManager) getRemoteUrl() (string, error) {
+	if gm.repository == nil {
+		// There's no local repository to take the remote URL from
+		if gm.git.SshAuth.IsSet() {
+			return gm.getSshRemoteUrl("")
+		}
+		return gm.generateHTTPSCloneUrl()
+	}
 	// Gets the remote repo url from the current .git dir
 	gitRemote, err := gm.repository.Remote(gm.remoteName)
 	if err != nil {
@@ -131,6 +198,9 @@ func (gm *GitManager) getRemoteUrl() (string, error) {
 		return "", errors.New("failed to find git remote URL")
 	}
 	remoteUrl := gitRemote.Config().URLs[0]
+	if gm.git.SshAuth.IsSet() {
+		return gm.getSshRemoteUrl(remoteUrl)
+	}
 	if strings.HasPrefix(remoteUrl, "https://") {
 		return remoteUrl, nil
 	}
@@ -138,6 +208,67 @@ func (gm *GitManager) getRemoteUrl() (string, error) {
 	return gm.generateHTTPSCloneUrl()
 }
 
+func (gm *GitManager) getSshRemoteUrl(remoteUrl string) (string, error) {
+	if isSshUrl(remoteUrl) {
+		return remoteUrl, nil
+	}
+	if gm.sshCloneUrl == "" {
+		if remoteUrl == "" {
+			return "", errors.New("the SSH URL of the repository is unknown")
+		}
+		return "", fmt.Errorf("the remote URL %s isn't an SSH URL, and the SSH URL of the repository is unknown", remoteUrl)
+	}
+	return gm.sshCloneUrl, nil
+}
+
+// SetSshCloneUrl sets the SSH URL to clone the repository from, when the SSH key is set and the local repository has an HTTPS remote.
+func (gm *GitManager) SetSshCloneUrl(sshCloneUrl string) *GitManager {
+	gm.sshCloneUrl = sshCloneUrl
+	return gm
+}
+
+// SetSparseCheckoutDirs sets the directories to check out, when the sparse checkout is enabled.
+// A glob pattern, such as "services/*", is checked out by the directory preceding its first wildcard.
+// If any of the directories is the root directory of the repository, or a pattern starts with a wildcard, the entire repository is checked out.
+// The directories of the CODEOWNERS file are always checked out, as the files in the root directory are.
+func (gm *GitManager) SetSparseCheckoutDirs(dirs []string) *GitManager {
+	gm.sparseCheckoutDirs = nil
+	if !gm.git.SparseCheckout || len(dirs) == 0 {
+		return gm
+	}
+	var sparseCheckoutDirs []string
+	for _, dir := range dirs {
+		dir = getSparseCheckoutDir(dir)
+		if dir == "" {
+			return gm
+		}
+		sparseCheckoutDirs = appendIfMissing(sparseCheckoutDirs, dir)
+	}
+	for _, location := range codeOwnersLocations {
+		if dir := path.Dir(location); dir != "." {
+			sparseCheckoutDirs = appendIfMissing(sparseCheckoutDirs, dir)
+		}
+	}
+	gm.sparseCheckoutDirs = sparseCheckoutDirs
+	return gm
+}
+
+// getSparseCheckoutDir returns the directory to check out for a working directory, which may be a glob pattern.
+// Returns an empty string if the entire repository should be checked out.
+func getSparseCheckoutDir(workingDir string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(filepath.ToSlash(filepath.Clean(workingDir)), "/"), "/") {
+		if isGlobPattern(segment) {
+			break
+		}
+		segments = append(segments, segment)
+	}
+	if dir := strings.Join(segments, "/"); dir != "." {
+		return dir
+	}
+	return ""
+}
+
 func (gm *GitManager) CreateBranchAndCheckout(branchName string) error {
 	log.Debug("Creating branch", branchName, "...")
 	err := gm.createBranchAndCheckout(branchName, true)
@@ -152,6 +283,9 @@ func (gm *GitManager) CreateBranchAndCheckout(branchName string) error {
 }
 
 func (gm *GitManager) createBranchAndCheckout(branchName string, create bool) error {
+	if gm.cli != nil {
+		return gm.cli.checkout(plumbing.ReferenceName(branchName).Short(), create)
+	}
 	checkoutConfig := &git.CheckoutOptions{
 		Create: create,
 		Branch: getFullBranchName(branchName),
@@ -174,6 +308,9 @@ func (gm *GitManager) AddAllAndCommit(commitMessage string) error {
 }
 
 func (gm *GitManager) addAll() error {
+	if gm.cli != nil {
+		return gm.cli.addAll()
+	}
 	worktree, err := gm.repository.Worktree()
 	if err != nil {
 		return err
@@ -206,28 +343,87 @@ func (gm *GitManager) addAll() error {
 	return nil
 }
 
+// gitIdentity is the name and email of a commit author or committer.
+type gitIdentity struct {
+	name  string
+	email string
+}
+
+// commitIdentities returns the author and committer of the commits. The committer is the author, unless set otherwise.
+func (gm *GitManager) commitIdentities() (author, committer gitIdentity) {
+	author = gitIdentity{name: gm.git.AuthorName, email: gm.git.EmailAuthor}
+	committer = gitIdentity{name: gm.git.CommitterName, email: gm.git.CommitterEmail}
+	if committer.name == "" {
+		committer.name = author.name
+	}
+	if committer.email == "" {
+		committer.email = author.email
+	}
+	return
+}
+
 func (gm *GitManager) commit(commitMessage string) error {
+	author, committer := gm.commitIdentities()
+	if gm.cli != nil {
+		if err := gm.cli.commit(commitMessage, author, committer, gm.signer); err != nil {
+			return fmt.Errorf("git commit failed with error: %s", err.Error())
+		}
+		return nil
+	}
 	worktree, err := gm.repository.Worktree()
 	if err != nil {
 		return err
 	}
-	_, err = worktree.Commit(commitMessage, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  frogbotAuthorName,
-			Email: gm.git.EmailAuthor,
-			When:  time.Now(),
-		},
-	})
+	now := time.Now()
+	commitOptions := &git.CommitOptions{
+		Author:    &object.Signature{Name: author.name, Email: author.email, When: now},
+		Committer: &object.Signature{Name: committer.name, Email: committer.email, When: now},
+	}
+	if gm.signer != nil {
+		commitOptions.SignKey = gm.signer.openPgpKey
+	}
+	hash, err := worktree.Commit(commitMessage, commitOptions)
 	if err != nil {
-		err = fmt.Errorf("git commit failed with error: %s", err.Error())
+		return fmt.Errorf("git commit failed with error: %s", err.Error())
+	}
+	if gm.signer == nil || gm.signer.sshKey == nil {
+		return nil
+	}
+	if err = gm.signCommitWithSsh(hash); err != nil {
+		err = fmt.Errorf("failed to sign the commit with the SSH key: %s", err.Error())
 	}
 	return err
 }
 
+// signCommitWithSsh replaces the HEAD commit with a commit signed by the SSH key.
+func (gm *GitManager) signCommitWithSsh(hash plumbing.Hash) error {
+	commit, err := gm.repository.CommitObject(hash)
+	if err != nil {
+		return err
+	}
+	signedHash, err := gm.signer.signCommit(gm.repository.Storer, commit)
+	if err != nil {
+		return err
+	}
+	head, err := gm.repository.Head()
+	if err != nil {
+		return err
+	}
+	return gm.repository.Storer.SetReference(plumbing.NewHashReference(head.Name(), signedHash))
+}
+
 func (gm *GitManager) BranchExistsInRemote(branchName string) (bool, error) {
 	if gm.dryRun {
 		return false, nil
 	}
+	if gm.cli != nil {
+		var exists bool
+		err := gm.retryRemoteOperation("git ls-remote", func() (e error) {
+			exists, e = gm.cli.branchExistsInRemote(gm.remoteName, branchName)
+			return
+		})
+		return exists, err
+	}
 	remote, err := gm.repository.Remote(gm.remoteName)
 	if err != nil {
 		return false, errorutils.CheckError(err)
@@ -252,19 +448,135 @@ func (gm *GitManager) Push(force bool, branchName string) error {
 		return nil
 	}
 	// Pushing to remote
-	if err := gm.repository.Push(&git.PushOptions{
-		RemoteName: gm.remoteName,
-		Auth:       gm.auth,
-		Force:      force,
-		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf(refFormat, branchName))},
+	if err := gm.retryRemoteOperation("git push", func() error {
+		if gm.cli != nil {
+			return gm.cli.push(gm.remoteName, branchName, force)
+		}
+		return gm.repository.Push(&git.PushOptions{
+			RemoteName: gm.remoteName,
+			Auth:       gm.auth,
+			Force:      force,
+			RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf(refFormat, branchName))},
+		})
 	}); err != nil {
 		return fmt.Errorf("git push failed with error: %s", err.Error())
 	}
 	return nil
 }
 
+// FixBranchStatus compares a remote fix branch with the base branch it was created from.
+type FixBranchStatus struct {
+	// True if the common commit of the branches can't be found, such as when the history of a shallow clone is incomplete
+	Unknown bool
+	// True if the base branch has commits that the fix branch doesn't include.
+	// An out-of-date fix branch may also conflict with the base branch.
+	OutOfDate bool
+	// The hashes of the commits of the fix branch that weren't authored by Frogbot
+	ForeignCommits []string
+}
+
+// GetFixBranchStatus fetches the remote fix branch, and compares it with the local base branch.
+// The history of the fix branch is fetched in full, so that its common commit with the base branch is found in shallow clones too.
+func (gm *GitManager) GetFixBranchStatus(baseBranch, fixBranch string) (*FixBranchStatus, error) {
+	if gm.dryRun {
+		return &FixBranchStatus{}, nil
+	}
+	log.Debug("Comparing branch", fixBranch, "with", baseBranch, "...")
+	remoteRefName := plumbing.NewRemoteReferenceName(gm.remoteName, fixBranch)
+	refSpec := config.RefSpec(fmt.Sprintf("+%s:%s", getFullBranchName(fixBranch), remoteRefName))
+	if err := gm.retryRemoteOperation("git fetch", func() error {
+		if gm.cli != nil {
+			return gm.cli.fetch(gm.remoteName, refSpec.String())
+		}
+		err := gm.repository.Fetch(&git.FetchOptions{RemoteName: gm.remoteName, Auth: gm.auth, RefSpecs: []config.RefSpec{refSpec}})
+		if errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return nil
+		}
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("'git fetch %s' failed with error: %s", fixBranch, err.Error())
+	}
+	if gm.cli != nil {
+		return gm.cli.fixBranchStatus(getFullBranchName(baseBranch).String(), remoteRefName.String(), gm.git.EmailAuthor)
+	}
+	return gm.fixBranchStatus(getFullBranchName(baseBranch), remoteRefName)
+}
+
+func (gm *GitManager) fixBranchStatus(baseRefName, fixRefName plumbing.ReferenceName) (*FixBranchStatus, error) {
+	baseCommit, err := gm.referenceCommit(baseRefName)
+	if err != nil {
+		return nil, err
+	}
+	fixCommit, err := gm.referenceCommit(fixRefName)
+	if err != nil {
+		return nil, err
+	}
+	mergeBases, err := baseCommit.MergeBase(fixCommit)
+	if err != nil || len(mergeBases) == 0 {
+		log.Debug("The common commit of", baseRefName.Short(), "and", fixRefName.Short(), "wasn't found")
+		return &FixBranchStatus{Unknown: true}, nil
+	}
+	status := &FixBranchStatus{OutOfDate: mergeBases[0].Hash != baseCommit.Hash}
+	// The commits of the fix branch are the first parents of its head, up to the common commit
+	for commit := fixCommit; commit.Hash != mergeBases[0].Hash; {
+		if commit.Author.Email != gm.git.EmailAuthor {
+			status.ForeignCommits = append(status.ForeignCommits, commit.Hash.String())
+		}
+		if commit, err = commit.Parent(0); err != nil {
+			return &FixBranchStatus{Unknown: true}, nil
+		}
+	}
+	return status, nil
+}
+
+func (gm *GitManager) referenceCommit(refName plumbing.ReferenceName) (*object.Commit, error) {
+	ref, err := gm.repository.Reference(refName, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %s", refName.Short(), err.Error())
+	}
+	return gm.repository.CommitObject(ref.Hash())
+}
+
+// DeleteRemoteBranch deletes the branch from the remote repository.
+func (gm *GitManager) DeleteRemoteBranch(branchName string) error {
+	log.Debug("Deleting remote branch:", branchName, "...")
+	if gm.dryRun {
+		return nil
+	}
+	refSpec := config.RefSpec(":" + getFullBranchName(branchName).String())
+	err := gm.retryRemoteOperation("git push --delete", func() error {
+		if gm.cli != nil && gm.cli.isRepository() {
+			return gm.cli.deleteRemoteBranch(gm.remoteName, branchName)
+		}
+		if gm.repository != nil {
+			return gm.repository.Push(&git.PushOptions{RemoteName: gm.remoteName, Auth: gm.auth, RefSpecs: []config.RefSpec{refSpec}})
+		}
+		// Without a local repository, the branch is deleted through a remote of an empty in-memory repository
+		remoteUrl, err := gm.getRemoteUrl()
+		if err != nil {
+			return err
+		}
+		remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: gm.remoteName, URLs: []string{remoteUrl}})
+		return remote.Push(&git.PushOptions{RemoteName: gm.remoteName, Auth: gm.auth, RefSpecs: []config.RefSpec{refSpec}})
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("git push --delete %s failed with error: %s", branchName, err.Error())
+	}
+	return nil
+}
+
+func (gm *GitManager) retryRemoteOperation(operationName string, operation func() error) error {
+	if gm.retryExecutor == nil {
+		return operation()
+	}
+	return gm.retryExecutor.execute(operationName, isTransientGitError, operation)
+}
+
 // IsClean returns true if all the files are in Unmodified status.
 func (gm *GitManager) IsClean() (bool, error) {
+	if gm.cli != nil {
+		return gm.cli.isClean()
+	}
 	worktree, err := gm.repository.Worktree()
 	if err != nil {
 		return false, err
@@ -277,6 +589,49 @@ func (gm *GitManager) IsClean() (bool, error) {
 	return status.IsClean(), nil
 }
 
+// ResetWorktree discards the uncommitted changes, and removes the untracked and ignored files, such as the outputs of builds and dependency installations.
+func (gm *GitManager) ResetWorktree() error {
+	log.Debug("Resetting the worktree to the last commit...")
+	if gm.cli != nil {
+		return gm.cli.resetWorktree()
+	}
+	worktree, err := gm.repository.Worktree()
+	if err != nil {
+		return err
+	}
+	if err = worktree.Reset(&git.ResetOptions{Mode: git.HardReset}); err != nil {
+		return fmt.Errorf("git reset failed with error: %s", err.Error())
+	}
+	// The ignore patterns aren't read, so the ignored files are removed too
+	if err = worktree.Clean(&git.CleanOptions{Dir: true}); err != nil {
+		return fmt.Errorf("git clean failed with error: %s", err.Error())
+	}
+	return nil
+}
+
+// GetChangedFiles returns the paths of the uncommitted changed files, relative to the root of the repository.
+func (gm *GitManager) GetChangedFiles() ([]string, error) {
+	if gm.cli != nil {
+		return gm.cli.changedFiles()
+	}
+	worktree, err := gm.repository.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	var changedFiles []string
+	for path, fileStatus := range status {
+		if fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified {
+			changedFiles = append(changedFiles, path)
+		}
+	}
+	sort.Strings(changedFiles)
+	return changedFiles, nil
+}
+
 func (gm *GitManager) GenerateCommitMessage(impactedPackage string, fixVersion string) string {
 	template := gm.customTemplates.commitMessageTemplate
 	if template == "" {
@@ -344,6 +699,34 @@ func (gm *GitManager) GenerateAggregatedFixBranchName(tech coreutils.Technology)
 	return formatStringWithPlaceHolders(branchFormat, "", "", tech.ToString(), false), nil
 }
 
+// IsFrogbotBranch returns true if the branch name matches the template of the fix branches, or of the aggregated fix branches.
+// A template whose literal text has no letters or digits, such as ${BRANCH_NAME_HASH}, would match the branches of any name,
+// so branches aren't identified by such a template.
+func (gm *GitManager) IsFrogbotBranch(branchName string) bool {
+	templates := []string{BranchNameTemplate, AggregatedBranchNameTemplate}
+	if gm.customTemplates.branchNameTemplate != "" {
+		templates = []string{gm.customTemplates.branchNameTemplate}
+	}
+	placeHolders := []string{PackagePlaceHolder, FixVersionPlaceHolder, BranchHashPlaceHolder}
+	for _, template := range templates {
+		literalText := template
+		for _, placeHolder := range placeHolders {
+			literalText = strings.ReplaceAll(literalText, placeHolder, "")
+		}
+		if strings.IndexFunc(literalText, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		pattern := regexp.QuoteMeta(strings.ReplaceAll(template, " ", "_"))
+		for _, placeHolder := range placeHolders {
+			pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta(placeHolder), ".*")
+		}
+		if regexp.MustCompile("^" + pattern + "$").MatchString(branchName) {
+			return true
+		}
+	}
+	return false
+}
+
 // dryRunClone clones an existing repository from our testdata folder into the destination folder for testing purposes.
 // We should call this function when the current working directory is the repository we want to clone.
 func (gm *GitManager) dryRunClone(destination string) error {
@@ -380,15 +763,27 @@ func (gm *GitManager) generateHTTPSCloneUrl() (url string, err error) {
 		return fmt.Sprintf(gitLabHttpsFormat, gm.git.APIEndpoint, gm.git.RepoOwner, gm.git.RepoName), nil
 	case vcsutils.BitbucketServer:
 		return fmt.Sprintf(bitbucketServerHttpsFormat, gm.git.APIEndpoint, gm.git.RepoOwner, gm.git.RepoName), nil
+	case vcsutils.BitbucketCloud:
+		return fmt.Sprintf(bitbucketCloudHttpsFormat, gm.git.RepoOwner, gm.git.RepoName), nil
 	case vcsutils.AzureRepos:
 		azureEndpointWithoutHttps := strings.Join(strings.Split(gm.git.APIEndpoint, "https://")[1:], "")
 		return fmt.Sprintf(azureDevopsHttpsFormat, gm.git.RepoOwner, azureEndpointWithoutHttps, gm.git.Project, gm.git.RepoName), nil
 	default:
-		return "", fmt.Errorf("unsupported version control provider: %s", gm.git.GitProvider.String())
+		if plugin := getVcsProviderPlugin(gm.git.GitProvider); plugin != nil {
+			return plugin.HttpsCloneUrl(gm.git), nil
+		}
+		return "", fmt.Errorf("unsupported version control provider: %s", GetVcsProviderName(gm.git.GitProvider))
 	}
 }
 
 func (gm *GitManager) CheckoutRemoteBranch(branchName string) error {
+	if gm.cli != nil {
+		log.Debug("Running git checkout to remote branch:", branchName)
+		if gm.dryRun {
+			return gm.cli.checkout(branchName, false)
+		}
+		return gm.cli.checkoutRemote(gm.remoteName + "/" + branchName)
+	}
 	var checkoutConfig *git.CheckoutOptions
 	if gm.dryRun {
 		// On dry runs we mimic remote as local branches.
@@ -442,10 +837,12 @@ func loadCustomTemplates(commitMessageTemplate, branchNameTemplate, pullRequestT
 	return template, nil
 }
 
-func setGoGitCustomClient() {
+// setGoGitCustomClient sets the timeout of go-git, and the transport of the Git provider, which holds its proxy and TLS settings, if set.
+func setGoGitCustomClient(transport http.RoundTripper) {
 	log.Debug("Setting timeout for go-git to", goGitTimeoutSeconds, "seconds ...")
 	customClient := &http.Client{
-		Timeout: goGitTimeoutSeconds * time.Second,
+		Timeout:   goGitTimeoutSeconds * time.Second,
+		Transport: transport,
 	}
 
 	client.InstallProtocol("http", githttp.NewClient(customClient))
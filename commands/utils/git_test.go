@@ -145,6 +145,30 @@ func TestGitManager_GenerateAggregatedFixBranchName(t *testing.T) {
 	}
 }
 
+func TestGitManager_IsFrogbotBranch(t *testing.T) {
+	defaultTemplates, customTemplates := GitManager{}, GitManager{customTemplates: CustomTemplates{branchNameTemplate: "[feature]-${BRANCH_NAME_HASH}"}}
+	fixBranch, err := defaultTemplates.GenerateFixBranchName("main", "org.apache.logging.log4j:log4j-core", "2.17.1")
+	assert.NoError(t, err)
+	assert.True(t, defaultTemplates.IsFrogbotBranch(fixBranch))
+	assert.True(t, defaultTemplates.IsFrogbotBranch("frogbot-update-go-dependencies"))
+	assert.False(t, defaultTemplates.IsFrogbotBranch("feature/frogbot-update"))
+	assert.False(t, defaultTemplates.IsFrogbotBranch("main"))
+
+	fixBranch, err = customTemplates.GenerateFixBranchName("main", "lodash", "4.17.21")
+	assert.NoError(t, err)
+	assert.True(t, customTemplates.IsFrogbotBranch(fixBranch))
+	// The brackets of the template are matched literally
+	assert.False(t, customTemplates.IsFrogbotBranch("f-go"))
+	assert.False(t, customTemplates.IsFrogbotBranch("frogbot-update-go-dependencies"))
+
+	// A template without literal text would match any branch, so branches aren't identified by it
+	hashTemplate := GitManager{customTemplates: CustomTemplates{branchNameTemplate: "${BRANCH_NAME_HASH}"}}
+	fixBranch, err = hashTemplate.GenerateFixBranchName("main", "lodash", "4.17.21")
+	assert.NoError(t, err)
+	assert.False(t, hashTemplate.IsFrogbotBranch(fixBranch))
+	assert.False(t, hashTemplate.IsFrogbotBranch("main"))
+}
+
 func TestGitManager_GenerateAggregatedCommitMessage(t *testing.T) {
 	tests := []struct {
 		gitManager GitManager
@@ -224,22 +248,25 @@ func TestConvertSSHtoHTTPS(t *testing.T) {
 			expected:    "https://git.company.info/scm/bitbucketServerOwner/npmExample.git",
 			vcsProvider: vcsutils.BitbucketServer,
 		}, {
-			repoName:    "notSupported",
-			repoOwner:   "cloudOwner",
-			expected:    "",
+			repoName:    "npmExample",
+			repoOwner:   "bitbucketCloudWorkspace",
+			apiEndpoint: "https://api.bitbucket.org/2.0",
+			expected:    "https://bitbucket.org/bitbucketCloudWorkspace/npmExample.git",
 			vcsProvider: vcsutils.BitbucketCloud,
+		}, {
+			repoName:    "npmExample",
+			repoOwner:   "giteaOwner",
+			apiEndpoint: "https://gitea.company.info/api/v1/",
+			expected:    "https://gitea.company.info/giteaOwner/npmExample.git",
+			vcsProvider: GiteaVcsProvider,
 		},
 	}
 	for _, test := range testsCases {
-		t.Run(test.vcsProvider.String(), func(t *testing.T) {
+		t.Run(GetVcsProviderName(test.vcsProvider), func(t *testing.T) {
 			gm := GitManager{git: &Git{ClientInfo: ClientInfo{GitProvider: test.vcsProvider, RepoName: test.repoName, RepoOwner: test.repoOwner, VcsInfo: vcsclient.VcsInfo{Project: test.projectName, APIEndpoint: test.apiEndpoint}}}}
 			remoteUrl, err := gm.generateHTTPSCloneUrl()
-			if remoteUrl == "" {
-				assert.Equal(t, err.Error(), "unsupported version control provider: Bitbucket Cloud")
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, test.expected, remoteUrl)
-			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, remoteUrl)
 		})
 	}
 }
@@ -0,0 +1,481 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	GoGitBackend  = "go-git"
+	GitCliBackend = "cli"
+)
+
+var (
+	// Parts of the git error messages of failures that retrying can't fix
+	gitCliPermanentErrors = []struct {
+		message string
+		err     error
+	}{
+		{"Authentication failed", transport.ErrAuthenticationRequired},
+		{"could not read Username", transport.ErrAuthenticationRequired},
+		{"Permission denied", transport.ErrAuthorizationFailed},
+		{"Host key verification failed", transport.ErrAuthorizationFailed},
+		{"Repository not found", transport.ErrRepositoryNotFound},
+		{"does not appear to be a git repository", transport.ErrRepositoryNotFound},
+		{"not found in upstream", transport.ErrRepositoryNotFound},
+		{"already exists and is not an empty directory", git.ErrRepositoryAlreadyExists},
+	}
+	// Parts of the git error messages of network failures, which may be resolved by running the same command again
+	gitCliTransientErrors = []string{
+		"Could not resolve host",
+		"Failed to connect",
+		"Connection timed out",
+		"Connection refused",
+		"Connection reset",
+		"Operation timed out",
+		"the remote end hung up unexpectedly",
+		"early EOF",
+		"RPC failed",
+	}
+	// The HTTP status of the git error messages of failed requests, such as "The requested URL returned error: 502"
+	gitCliHttpStatusRegex = regexp.MustCompile(`The requested URL returned error: (\d{3})`)
+)
+
+// readGitBackendFromEnv returns the backend of the git operations, and the filter of partial clones.
+func readGitBackendFromEnv() (backend, cloneFilter string, err error) {
+	backend = strings.ToLower(getTrimmedEnv(GitBackendEnv))
+	if backend == "" {
+		backend = GoGitBackend
+	}
+	if backend != GoGitBackend && backend != GitCliBackend {
+		return "", "", fmt.Errorf("%s should be either '%s' or '%s'", GitBackendEnv, GoGitBackend, GitCliBackend)
+	}
+	if cloneFilter = getTrimmedEnv(GitCloneFilterEnv); cloneFilter != "" && backend != GitCliBackend {
+		return "", "", fmt.Errorf("%s is supported only when %s is '%s'", GitCloneFilterEnv, GitBackendEnv, GitCliBackend)
+	}
+	return
+}
+
+// readCloneSettingsFromEnv reads the depth of the clones, and whether to check out only the working directories of the projects.
+func readCloneSettingsFromEnv(clientInfo *ClientInfo) (err error) {
+	if clientInfo.CloneDepth, err = getIntEnv(GitCloneDepthEnv, 0); err != nil {
+		return
+	}
+	if clientInfo.SparseCheckout, err = getBoolEnv(GitSparseCheckoutEnv, false); err != nil {
+		return
+	}
+	if clientInfo.SparseCheckout && clientInfo.GitBackend != GitCliBackend {
+		return fmt.Errorf("%s is supported only when %s is '%s'", GitSparseCheckoutEnv, GitBackendEnv, GitCliBackend)
+	}
+	return
+}
+
+// gitCli runs the git operations of GitManager with the git executable rather than with go-git.
+// Unlike go-git, the git executable supports partial clones, LFS and all the server capabilities.
+type gitCli struct {
+	executable string
+	// The local repository, in which the commands run
+	repoPath string
+	// The URL of the remote repository. The authentication header is sent only to its host.
+	remoteUrl string
+	auth      transport.AuthMethod
+	git       *Git
+}
+
+func newGitCli(repoPath string, auth transport.AuthMethod, g *Git) (*gitCli, error) {
+	executable, err := exec.LookPath("git")
+	if err != nil {
+		return nil, fmt.Errorf("%s is set to '%s', but the git executable wasn't found: %s", GitBackendEnv, GitCliBackend, err.Error())
+	}
+	if repoPath, err = filepath.Abs(repoPath); err != nil {
+		return nil, err
+	}
+	return &gitCli{executable: executable, repoPath: repoPath, auth: auth, git: g}, nil
+}
+
+// run runs the git command in the local repository, and returns its trimmed output.
+func (cli *gitCli) run(args ...string) (string, error) {
+	output, err := cli.runCommand(nil, nil, args...)
+	return strings.TrimSpace(output), err
+}
+
+// runCommand runs the git command in the local repository with the additional environment variables and the input, and returns its output.
+func (cli *gitCli) runCommand(extraEnv []string, input []byte, args ...string) (output string, err error) {
+	// The credentials are written to a temporary directory, which is removed once the command is done
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, fileutils.RemoveTempDir(tempDir))
+	}()
+	env, err := cli.commandEnv(tempDir)
+	if err != nil {
+		return
+	}
+	command := exec.Command(cli.executable, args...)
+	command.Dir = cli.repoPath
+	command.Env = append(env, extraEnv...)
+	if input != nil {
+		command.Stdin = bytes.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	log.Debug("Running git", args[0], "...")
+	if err = command.Run(); err != nil {
+		return "", toGitCliError(args[0], stderr.String(), err)
+	}
+	return stdout.String(), nil
+}
+
+// toGitCliError returns the error of a failed git command.
+// Errors of failures that retrying can't fix wrap the matching go-git transport errors, so they aren't retried.
+func toGitCliError(command, stderr string, err error) error {
+	message := strings.TrimSpace(stderr)
+	if message == "" {
+		message = err.Error()
+	}
+	for _, permanentError := range gitCliPermanentErrors {
+		if strings.Contains(message, permanentError.message) {
+			return fmt.Errorf("git %s failed: %w: %s", command, permanentError.err, message)
+		}
+	}
+	if isTransientGitCliMessage(message) {
+		return &transientGitCliError{message: fmt.Sprintf("git %s failed: %s", command, message)}
+	}
+	return fmt.Errorf("git %s failed: %s", command, message)
+}
+
+// transientGitCliError is the error of a git command that failed due to a network or a server side error, and may succeed if it runs again.
+type transientGitCliError struct {
+	message string
+}
+
+func (e *transientGitCliError) Error() string {
+	return e.message
+}
+
+func isTransientGitCliMessage(message string) bool {
+	if match := gitCliHttpStatusRegex.FindStringSubmatch(message); match != nil {
+		statusCode, err := strconv.Atoi(match[1])
+		return err == nil && isTransientStatusCode(statusCode)
+	}
+	for _, transientMessage := range gitCliTransientErrors {
+		if strings.Contains(message, transientMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandEnv returns the environment of the git commands, which holds the authentication and the connection settings of the Git provider.
+// The settings are passed as GIT_CONFIG_* environment variables, rather than as arguments, to keep the credentials out of the process list.
+func (cli *gitCli) commandEnv(tempDir string) ([]string, error) {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	var configs [][2]string
+	// The header is scoped to the host of the remote repository, so it isn't sent to other hosts, such as the hosts of submodules
+	if extraHeaderKey := cli.extraHeaderConfigKey(); extraHeaderKey != "" {
+		switch auth := cli.auth.(type) {
+		case *githttp.BasicAuth:
+			configs = append(configs, [2]string{extraHeaderKey, basicAuthHeader(auth.Username, auth.Password)})
+		case *githubAppGitAuth:
+			token, err := auth.tokens.Token()
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, [2]string{extraHeaderKey, basicAuthHeader(githubAppGitUsername, token)})
+		}
+	}
+	if cli.git.SshAuth.IsSet() {
+		sshEnv, err := cli.sshEnv(tempDir)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, sshEnv...)
+	}
+	httpConfigs, err := cli.httpTransportConfigs(tempDir)
+	if err != nil {
+		return nil, err
+	}
+	configs = append(configs, httpConfigs...)
+	// Frogbot signs the commits itself, with the signing key set in its environment
+	configs = append(configs, [2]string{"commit.gpgSign", "false"})
+	env = append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(configs)))
+	for i, config := range configs {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, config[0]), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, config[1]))
+	}
+	return env, nil
+}
+
+// extraHeaderConfigKey returns the http.<url>.extraHeader config key of the host of the remote repository,
+// or an empty string if the remote repository isn't accessed over HTTP(S).
+func (cli *gitCli) extraHeaderConfigKey() string {
+	parsedUrl, err := url.Parse(cli.remoteUrl)
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+		return ""
+	}
+	return fmt.Sprintf("http.%s://%s/.extraHeader", parsedUrl.Scheme, parsedUrl.Host)
+}
+
+func basicAuthHeader(username, password string) string {
+	return "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// sshEnv returns the environment variables that make git connect with the SSH key, and verify the host key against the known hosts.
+func (cli *gitCli) sshEnv(tempDir string) ([]string, error) {
+	sshAuth := cli.git.SshAuth
+	keyFile := filepath.Join(tempDir, "id_frogbot")
+	// ssh rejects keys that don't end with a new line
+	if err := os.WriteFile(keyFile, []byte(strings.TrimSpace(sshAuth.PrivateKey)+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	sshCommand := fmt.Sprintf("ssh -i %s -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes", shellQuote(keyFile))
+	if sshAuth.KnownHosts != "" {
+		knownHostsFile := filepath.Join(tempDir, "known_hosts")
+		if err := os.WriteFile(knownHostsFile, []byte(sshAuth.KnownHosts+"\n"), 0600); err != nil {
+			return nil, err
+		}
+		sshCommand += " -o UserKnownHostsFile=" + shellQuote(knownHostsFile)
+	}
+	env := []string{"GIT_SSH_COMMAND=" + sshCommand}
+	if sshAuth.Passphrase != "" {
+		// ssh reads the passphrase from the output of the askpass program
+		askPassFile := filepath.Join(tempDir, "askpass.sh")
+		if err := os.WriteFile(askPassFile, []byte(fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' \"$%s\"\n", GitSshKeyPassphraseEnv)), 0700); err != nil {
+			return nil, err
+		}
+		env = append(env, "SSH_ASKPASS="+askPassFile, "SSH_ASKPASS_REQUIRE=force", GitSshKeyPassphraseEnv+"="+sshAuth.Passphrase)
+	}
+	return env, nil
+}
+
+// httpTransportConfigs returns the git configs of the proxy and the certificates.
+// Note that git trusts only the CAs in JF_GIT_CA_CERT if set, rather than adding them to the system CAs.
+func (cli *gitCli) httpTransportConfigs(tempDir string) (configs [][2]string, err error) {
+	settings := cli.git.HttpTransport
+	if settings.Proxy != "" {
+		configs = append(configs, [2]string{"http.proxy", settings.Proxy})
+	}
+	for _, pemFile := range []struct {
+		config  string
+		name    string
+		content string
+	}{
+		{"http.sslCAInfo", "ca.pem", settings.CaCerts},
+		{"http.sslCert", "client.pem", settings.ClientCert},
+		{"http.sslKey", "client.key", settings.ClientKey},
+	} {
+		if pemFile.content == "" {
+			continue
+		}
+		path := filepath.Join(tempDir, pemFile.name)
+		if err = os.WriteFile(path, []byte(pemFile.content), 0600); err != nil {
+			return
+		}
+		configs = append(configs, [2]string{pemFile.config, path})
+	}
+	return
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// clone clones the branch. If sparse checkout directories are set, only they and the files in the root directory are checked out.
+func (cli *gitCli) clone(remoteUrl, remoteName, branchName, destinationPath string, sparseCheckoutDirs []string) (err error) {
+	if destinationPath, err = filepath.Abs(destinationPath); err != nil {
+		return
+	}
+	args := []string{"clone", "--origin", remoteName, "--branch", branchName}
+	if cli.git.CloneFilter != "" {
+		args = append(args, "--filter="+cli.git.CloneFilter)
+	}
+	if cli.git.CloneDepth > 0 {
+		args = append(args, "--depth", strconv.Itoa(cli.git.CloneDepth))
+	}
+	if len(sparseCheckoutDirs) > 0 {
+		args = append(args, "--sparse")
+	}
+	cli.remoteUrl = remoteUrl
+	if _, err = cli.run(append(args, "--", remoteUrl, destinationPath)...); err != nil {
+		return
+	}
+	cli.repoPath = destinationPath
+	if len(sparseCheckoutDirs) > 0 {
+		_, err = cli.run(append([]string{"sparse-checkout", "set", "--cone", "--"}, sparseCheckoutDirs...)...)
+	}
+	return
+}
+
+func (cli *gitCli) checkout(branchName string, create bool) error {
+	args := []string{"checkout", "--force"}
+	if create {
+		args = append(args, "-b")
+	}
+	_, err := cli.run(append(args, branchName)...)
+	return err
+}
+
+// checkoutRemote checks out the remote branch, such as origin/main, as a detached HEAD, as go-git does.
+func (cli *gitCli) checkoutRemote(remoteBranch string) error {
+	_, err := cli.run("checkout", "--force", "--detach", remoteBranch)
+	return err
+}
+
+func (cli *gitCli) addAll() error {
+	_, err := cli.run("add", "--all")
+	return err
+}
+
+// commit commits the staged changes, and signs the commit if a signer is set.
+func (cli *gitCli) commit(commitMessage string, author, committer gitIdentity, signer *commitSigner) error {
+	// The author and committer set in the environment take precedence over the git config
+	identityEnv := []string{
+		"GIT_AUTHOR_NAME=" + author.name,
+		"GIT_AUTHOR_EMAIL=" + author.email,
+		"GIT_COMMITTER_NAME=" + committer.name,
+		"GIT_COMMITTER_EMAIL=" + committer.email,
+	}
+	if _, err := cli.runCommand(identityEnv, nil, "commit", "--no-verify", "--message", commitMessage); err != nil {
+		return err
+	}
+	if signer == nil {
+		return nil
+	}
+	content, err := cli.runCommand(nil, nil, "cat-file", "commit", "HEAD")
+	if err != nil {
+		return err
+	}
+	signature, err := signer.sign([]byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to sign the commit: %s", err.Error())
+	}
+	signedHash, err := cli.runCommand(nil, []byte(addGpgSigHeader(content, signature)), "hash-object", "-t", "commit", "-w", "--stdin")
+	if err != nil {
+		return err
+	}
+	_, err = cli.run("update-ref", "-m", "Frogbot: sign commit", "HEAD", strings.TrimSpace(signedHash))
+	return err
+}
+
+// addGpgSigHeader adds the signature to the headers of the commit, as 'git commit -S' does.
+func addGpgSigHeader(commitContent, signature string) string {
+	headersEnd := strings.Index(commitContent, "\n\n")
+	if headersEnd < 0 {
+		headersEnd = len(commitContent)
+	}
+	signatureLines := strings.Split(strings.TrimSuffix(signature, "\n"), "\n")
+	return commitContent[:headersEnd] + "\ngpgsig " + strings.Join(signatureLines, "\n ") + commitContent[headersEnd:]
+}
+
+func (cli *gitCli) branchExistsInRemote(remoteName, branchName string) (bool, error) {
+	output, err := cli.run("ls-remote", "--heads", remoteName, "refs/heads/"+branchName)
+	if err != nil {
+		return false, err
+	}
+	return output != "", nil
+}
+
+func (cli *gitCli) push(remoteName, branchName string, force bool) error {
+	args := []string{"push"}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := cli.run(append(args, remoteName, fmt.Sprintf(refFormat, branchName))...)
+	return err
+}
+
+func (cli *gitCli) fetch(remoteName, refSpec string) error {
+	_, err := cli.run("fetch", remoteName, refSpec)
+	return err
+}
+
+// fixBranchStatus compares the fetched fix branch with the base branch.
+func (cli *gitCli) fixBranchStatus(baseRef, fixRef, frogbotEmail string) (*FixBranchStatus, error) {
+	mergeBase, err := cli.run("merge-base", baseRef, fixRef)
+	if err != nil {
+		log.Debug("The common commit of", baseRef, "and", fixRef, "wasn't found:", err.Error())
+		return &FixBranchStatus{Unknown: true}, nil
+	}
+	baseHash, err := cli.run("rev-parse", baseRef)
+	if err != nil {
+		return nil, err
+	}
+	status := &FixBranchStatus{OutOfDate: mergeBase != baseHash}
+	output, err := cli.run("log", "--first-parent", "--format=%H %ae", mergeBase+".."+fixRef)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if hash, email, found := strings.Cut(line, " "); found && email != frogbotEmail {
+			status.ForeignCommits = append(status.ForeignCommits, hash)
+		}
+	}
+	return status, nil
+}
+
+// isRepository returns true if the working directory of the git executable is inside a repository.
+func (cli *gitCli) isRepository() bool {
+	_, err := cli.run("rev-parse", "--git-dir")
+	return err == nil
+}
+
+func (cli *gitCli) deleteRemoteBranch(remoteName, branchName string) error {
+	_, err := cli.run("push", "--delete", remoteName, branchName)
+	return err
+}
+
+func (cli *gitCli) changedFiles() ([]string, error) {
+	// The output isn't trimmed, as the status of the first entry may start with a space
+	output, err := cli.runCommand(nil, nil, "status", "--porcelain", "--untracked-files=all", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var changedFiles []string
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		// Each entry is the two status letters, a space and the path
+		if len(entries[i]) < 4 {
+			continue
+		}
+		changedFiles = append(changedFiles, entries[i][3:])
+		// Renamed and copied files are followed by their original paths
+		if entries[i][0] == 'R' || entries[i][0] == 'C' {
+			i++
+		}
+	}
+	sort.Strings(changedFiles)
+	return changedFiles, nil
+}
+
+func (cli *gitCli) resetWorktree() error {
+	if _, err := cli.run("reset", "--hard", "HEAD"); err != nil {
+		return err
+	}
+	_, err := cli.run("clean", "-fdx")
+	return err
+}
+
+func (cli *gitCli) isClean() (bool, error) {
+	output, err := cli.run("status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return output == "", nil
+}
@@ -0,0 +1,425 @@
+package utils
+
+import (
+	"bytes"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newGitCliTestRemote creates a bare repository with a single commit on the main branch, and returns its path.
+func newGitCliTestRemote(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("The git executable wasn't found")
+	}
+	workPath, remotePath := t.TempDir(), t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--initial-branch", "main", workPath},
+		{"-C", workPath, "-c", "user.name=Frogbot", "-c", "user.email=" + frogbotAuthorEmail, "commit", "--allow-empty", "--message", "Initial commit"},
+		{"clone", "--bare", workPath, remotePath},
+	} {
+		output, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	return remotePath
+}
+
+func TestGitCliBackend(t *testing.T) {
+	remotePath := newGitCliTestRemote(t)
+	gitParams := &Git{AuthorName: frogbotAuthorName, EmailAuthor: frogbotAuthorEmail, ClientInfo: ClientInfo{GitBackend: GitCliBackend}}
+	cli, err := newGitCli(".", toBasicAuth("token", "user"), gitParams)
+	require.NoError(t, err)
+	gm := &GitManager{remoteName: "origin", git: gitParams, cli: cli}
+
+	clonePath := filepath.Join(t.TempDir(), "frogbot")
+	require.NoError(t, gm.cli.clone("file://"+remotePath, gm.remoteName, "main", clonePath, nil))
+	assert.Equal(t, clonePath, gm.cli.repoPath)
+	require.NoError(t, gm.CreateBranchAndCheckout("frogbot-fix"))
+	isClean, err := gm.IsClean()
+	assert.NoError(t, err)
+	assert.True(t, isClean)
+
+	require.NoError(t, os.WriteFile(filepath.Join(clonePath, "go.mod"), []byte("module frogbot"), 0600))
+	isClean, err = gm.IsClean()
+	assert.NoError(t, err)
+	assert.False(t, isClean)
+	require.NoError(t, gm.AddAllAndCommit("Upgrade dependencies"))
+	isClean, err = gm.IsClean()
+	assert.NoError(t, err)
+	assert.True(t, isClean)
+
+	exists, err := gm.BranchExistsInRemote("frogbot-fix")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	require.NoError(t, gm.Push(false, "frogbot-fix"))
+	exists, err = gm.BranchExistsInRemote("frogbot-fix")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// The pushed commit is authored by Frogbot
+	remote, err := git.PlainOpen(remotePath)
+	require.NoError(t, err)
+	reference, err := remote.Reference(plumbing.NewBranchReferenceName("frogbot-fix"), false)
+	require.NoError(t, err)
+	commit, err := remote.CommitObject(reference.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "Upgrade dependencies", strings.TrimSpace(commit.Message))
+	assert.Equal(t, frogbotAuthorName, commit.Author.Name)
+	assert.Equal(t, frogbotAuthorEmail, commit.Committer.Email)
+
+	require.NoError(t, gm.CheckoutRemoteBranch("main"))
+	_, err = os.Stat(filepath.Join(clonePath, "go.mod"))
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, gm.CheckoutLocalBranch("frogbot-fix"))
+	assert.FileExists(t, filepath.Join(clonePath, "go.mod"))
+
+	require.NoError(t, gm.CheckoutLocalBranch("main"))
+	require.NoError(t, gm.DeleteRemoteBranch("frogbot-fix"))
+	exists, err = gm.BranchExistsInRemote("frogbot-fix")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestDeleteRemoteBranch(t *testing.T) {
+	remotePath := newGitCliTestRemote(t)
+	output, err := exec.Command("git", "-C", remotePath, "branch", "frogbot-fix", "main").CombinedOutput()
+	require.NoError(t, err, string(output))
+	repository, err := git.PlainClone(t.TempDir(), false, &git.CloneOptions{URL: "file://" + remotePath})
+	require.NoError(t, err)
+	gm := &GitManager{remoteName: "origin", git: &Git{}, repository: repository}
+
+	require.NoError(t, gm.DeleteRemoteBranch("frogbot-fix"))
+	remote, err := git.PlainOpen(remotePath)
+	require.NoError(t, err)
+	_, err = remote.Reference(plumbing.NewBranchReferenceName("frogbot-fix"), false)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	// Deleting a branch that doesn't exist doesn't fail
+	assert.NoError(t, gm.DeleteRemoteBranch("frogbot-fix"))
+}
+
+func TestGitCliShallowSparseClone(t *testing.T) {
+	remotePath := newGitCliTestRemote(t)
+	// Add a second commit with the directories of two projects
+	workPath := filepath.Join(t.TempDir(), "work")
+	output, err := exec.Command("git", "clone", remotePath, workPath).CombinedOutput()
+	require.NoError(t, err, string(output))
+	for _, dir := range []string{"frontend", "backend"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(workPath, dir), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(workPath, dir, "package.json"), []byte("{}"), 0600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(workPath, "README.md"), []byte("# Frogbot"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(workPath, ".github"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(workPath, ".github", "CODEOWNERS"), []byte("* @jfrog/frogbot"), 0600))
+	for _, args := range [][]string{
+		{"-C", workPath, "add", "--all"},
+		{"-C", workPath, "-c", "user.name=Frogbot", "-c", "user.email=" + frogbotAuthorEmail, "commit", "--message", "Add projects"},
+		{"-C", workPath, "push", "origin", "main"},
+	} {
+		output, err = exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	gitParams := &Git{AuthorName: frogbotAuthorName, EmailAuthor: frogbotAuthorEmail, ClientInfo: ClientInfo{GitBackend: GitCliBackend, CloneDepth: 1, SparseCheckout: true}}
+	cli, err := newGitCli(".", toBasicAuth("token", "user"), gitParams)
+	require.NoError(t, err)
+	gm := (&GitManager{remoteName: "origin", git: gitParams, cli: cli}).SetSparseCheckoutDirs([]string{"./frontend/"})
+	assert.Equal(t, []string{"frontend", ".github", "docs", ".gitlab"}, gm.sparseCheckoutDirs)
+
+	clonePath := filepath.Join(t.TempDir(), "frogbot")
+	require.NoError(t, gm.cli.clone("file://"+remotePath, gm.remoteName, "main", clonePath, gm.sparseCheckoutDirs))
+	assert.FileExists(t, filepath.Join(clonePath, "frontend", "package.json"))
+	assert.FileExists(t, filepath.Join(clonePath, "README.md"))
+	assert.FileExists(t, filepath.Join(clonePath, ".github", "CODEOWNERS"))
+	assert.NoDirExists(t, filepath.Join(clonePath, "backend"))
+	commits, err := gm.cli.run("rev-list", "--count", "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", commits)
+
+	// The directories that aren't checked out aren't considered as deleted
+	require.NoError(t, gm.CreateBranchAndCheckout("frogbot-fix"))
+	require.NoError(t, os.WriteFile(filepath.Join(clonePath, "frontend", "package.json"), []byte(`{"name": "frontend"}`), 0600))
+	require.NoError(t, gm.AddAllAndCommit("Upgrade dependencies"))
+	require.NoError(t, gm.Push(false, "frogbot-fix"))
+	changedFiles, err := gm.cli.run("diff", "--name-only", "HEAD~1", "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, "frontend/package.json", changedFiles)
+}
+
+func TestSetSparseCheckoutDirs(t *testing.T) {
+	gm := &GitManager{git: &Git{}}
+	assert.Empty(t, gm.SetSparseCheckoutDirs([]string{"frontend"}).sparseCheckoutDirs)
+
+	gm.git.SparseCheckout = true
+	assert.Equal(t, []string{"frontend", "services/backend", ".github", "docs", ".gitlab"}, gm.SetSparseCheckoutDirs([]string{"frontend/", "./services/backend"}).sparseCheckoutDirs)
+	// Glob patterns are checked out by the directory preceding their first wildcard
+	assert.Equal(t, []string{"services", "frontend", ".github", "docs", ".gitlab"}, gm.SetSparseCheckoutDirs([]string{"services/*", "frontend/**/package.json", "services/*/api"}).sparseCheckoutDirs)
+	// The root directory requires the entire repository
+	assert.Empty(t, gm.SetSparseCheckoutDirs([]string{"frontend", "."}).sparseCheckoutDirs)
+	assert.Empty(t, gm.SetSparseCheckoutDirs([]string{"frontend", "**"}).sparseCheckoutDirs)
+	assert.Empty(t, gm.SetSparseCheckoutDirs([]string{"*/package.json"}).sparseCheckoutDirs)
+	assert.Empty(t, gm.SetSparseCheckoutDirs(nil).sparseCheckoutDirs)
+}
+
+func TestGitCliSignedCommit(t *testing.T) {
+	remotePath := newGitCliTestRemote(t)
+	entity, err := openpgp.NewEntity("Frogbot", "", frogbotAuthorEmail, nil)
+	require.NoError(t, err)
+	var publicKey bytes.Buffer
+	writer, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(writer))
+	require.NoError(t, writer.Close())
+	gitParams := &Git{AuthorName: frogbotAuthorName, EmailAuthor: frogbotAuthorEmail, CommitterName: "CI", CommitterEmail: "ci@company.info"}
+	cli, err := newGitCli(".", toBasicAuth("token", "user"), gitParams)
+	require.NoError(t, err)
+	gm := &GitManager{remoteName: "origin", git: gitParams, cli: cli, signer: &commitSigner{openPgpKey: entity}}
+
+	clonePath := filepath.Join(t.TempDir(), "frogbot")
+	require.NoError(t, gm.cli.clone("file://"+remotePath, gm.remoteName, "main", clonePath, nil))
+	require.NoError(t, os.WriteFile(filepath.Join(clonePath, "go.mod"), []byte("module frogbot"), 0600))
+	require.NoError(t, gm.AddAllAndCommit("Upgrade dependencies"))
+
+	repository, err := git.PlainOpen(clonePath)
+	require.NoError(t, err)
+	head, err := repository.Head()
+	require.NoError(t, err)
+	assert.Equal(t, plumbing.NewBranchReferenceName("main"), head.Name())
+	commit, err := repository.CommitObject(head.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "CI", commit.Committer.Name)
+	_, err = commit.Verify(publicKey.String())
+	assert.NoError(t, err)
+	// The signed commit replaced the unsigned one
+	assert.Len(t, commit.ParentHashes, 1)
+	parent, err := commit.Parent(0)
+	require.NoError(t, err)
+	assert.Equal(t, "Initial commit", strings.TrimSpace(parent.Message))
+}
+
+func TestGitCliCommandEnv(t *testing.T) {
+	_, clientCertPem, clientKeyPem := generateClientCert(t)
+	privateKey, _ := generateSshKey(t)
+	gitParams := &Git{ClientInfo: ClientInfo{
+		HttpTransport: HttpTransportSettings{Proxy: "http://proxy.company.info:8080", ClientCert: clientCertPem, ClientKey: clientKeyPem},
+		SshAuth:       SshAuth{PrivateKey: privateKey, KnownHosts: "git.company.info ssh-rsa AAAA"},
+	}}
+	cli := &gitCli{remoteUrl: "https://user@git.company.info:8443/jfrog/frogbot.git", auth: toBasicAuth("token", "user"), git: gitParams}
+	tempDir := t.TempDir()
+	env, err := cli.commandEnv(tempDir)
+	require.NoError(t, err)
+	assert.Contains(t, env, "GIT_TERMINAL_PROMPT=0")
+	assert.Contains(t, env, "GIT_CONFIG_COUNT=5")
+	// The header is sent only to the host of the remote repository
+	assert.Contains(t, env, "GIT_CONFIG_KEY_0=http.https://git.company.info:8443/.extraHeader")
+	// The base64 encoding of user:token
+	assert.Contains(t, env, "GIT_CONFIG_VALUE_0=Authorization: Basic dXNlcjp0b2tlbg==")
+	assert.Contains(t, env, "GIT_CONFIG_VALUE_1=http://proxy.company.info:8080")
+	assert.Contains(t, env, "GIT_CONFIG_KEY_2=http.sslCert")
+	assert.Contains(t, env, "GIT_CONFIG_VALUE_2="+filepath.Join(tempDir, "client.pem"))
+	assert.Contains(t, env, "GIT_CONFIG_KEY_4=commit.gpgSign")
+	assert.Contains(t, env, "GIT_SSH_COMMAND=ssh -i '"+filepath.Join(tempDir, "id_frogbot")+"' -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o UserKnownHostsFile='"+filepath.Join(tempDir, "known_hosts")+"'")
+	content, err := os.ReadFile(filepath.Join(tempDir, "client.key"))
+	assert.NoError(t, err)
+	assert.Equal(t, clientKeyPem, string(content))
+
+	// Remote repositories that aren't accessed over HTTP(S) get no header
+	cli.remoteUrl = "file:///tmp/frogbot.git"
+	env, err = cli.commandEnv(tempDir)
+	require.NoError(t, err)
+	assert.Contains(t, env, "GIT_CONFIG_COUNT=4")
+	assert.NotContains(t, strings.Join(env, "\n"), "Authorization")
+}
+
+func TestToGitCliError(t *testing.T) {
+	err := toGitCliError("push", "remote: Repository not found.\nfatal: repository 'https://github.com/jfrog/missing.git/' not found\n", nil)
+	assert.ErrorIs(t, err, transport.ErrRepositoryNotFound)
+	assert.EqualError(t, err, "git push failed: repository not found: remote: Repository not found.\nfatal: repository 'https://github.com/jfrog/missing.git/' not found")
+	_, transient := isTransientGitError(err)
+	assert.False(t, transient)
+
+	err = toGitCliError("clone", "fatal: unable to access 'https://github.com/jfrog/frogbot.git/': The requested URL returned error: 502", nil)
+	assert.EqualError(t, err, "git clone failed: fatal: unable to access 'https://github.com/jfrog/frogbot.git/': The requested URL returned error: 502")
+	_, transient = isTransientGitError(err)
+	assert.True(t, transient)
+}
+
+func TestReadGitBackendFromEnv(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	backend, cloneFilter, err := readGitBackendFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, GoGitBackend, backend)
+	assert.Empty(t, cloneFilter)
+
+	SetEnvAndAssert(t, map[string]string{GitBackendEnv: "CLI", GitCloneFilterEnv: "blob:none"})
+	backend, cloneFilter, err = readGitBackendFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, GitCliBackend, backend)
+	assert.Equal(t, "blob:none", cloneFilter)
+
+	SetEnvAndAssert(t, map[string]string{GitBackendEnv: GoGitBackend})
+	_, _, err = readGitBackendFromEnv()
+	assert.EqualError(t, err, "JF_GIT_CLONE_FILTER is supported only when JF_GIT_BACKEND is 'cli'")
+
+	SetEnvAndAssert(t, map[string]string{GitBackendEnv: "libgit2"})
+	_, _, err = readGitBackendFromEnv()
+	assert.EqualError(t, err, "JF_GIT_BACKEND should be either 'go-git' or 'cli'")
+}
+
+func TestReadCloneSettingsFromEnv(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	clientInfo := &ClientInfo{}
+	assert.NoError(t, readCloneSettingsFromEnv(clientInfo))
+	assert.False(t, clientInfo.IsShallowOrSparseClone())
+
+	SetEnvAndAssert(t, map[string]string{GitCloneDepthEnv: "1"})
+	assert.NoError(t, readCloneSettingsFromEnv(clientInfo))
+	assert.Equal(t, 1, clientInfo.CloneDepth)
+	assert.True(t, clientInfo.IsShallowOrSparseClone())
+
+	SetEnvAndAssert(t, map[string]string{GitCloneDepthEnv: "-1"})
+	assert.ErrorContains(t, readCloneSettingsFromEnv(clientInfo), "JF_GIT_CLONE_DEPTH environment is expected to be a non-negative number")
+
+	SetEnvAndAssert(t, map[string]string{GitCloneDepthEnv: "", GitSparseCheckoutEnv: "true"})
+	assert.EqualError(t, readCloneSettingsFromEnv(clientInfo), "JF_GIT_SPARSE_CHECKOUT is supported only when JF_GIT_BACKEND is 'cli'")
+	clientInfo = &ClientInfo{GitBackend: GitCliBackend}
+	assert.NoError(t, readCloneSettingsFromEnv(clientInfo))
+	assert.True(t, clientInfo.SparseCheckout)
+	assert.True(t, clientInfo.IsShallowOrSparseClone())
+}
+
+func TestGetFixBranchStatus(t *testing.T) {
+	remotePath := newGitCliTestRemote(t)
+	workPath := t.TempDir()
+	runGit := func(email string, args ...string) {
+		output, err := exec.Command("git", append([]string{"-C", workPath, "-c", "user.name=Tester", "-c", "user.email=" + email}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	runGit(frogbotAuthorEmail, "clone", remotePath, ".")
+	// A fix branch by Frogbot, a fix branch with a commit of a developer, and a fix branch that is created after the base branch moved
+	runGit(frogbotAuthorEmail, "checkout", "-b", "frogbot-fix")
+	runGit(frogbotAuthorEmail, "commit", "--allow-empty", "--message", "Upgrade lodash")
+	runGit(frogbotAuthorEmail, "checkout", "-b", "frogbot-fix-edited")
+	runGit("developer@company.info", "commit", "--allow-empty", "--message", "Fix the tests")
+	runGit(frogbotAuthorEmail, "checkout", "main")
+	runGit("developer@company.info", "commit", "--allow-empty", "--message", "New feature")
+	runGit(frogbotAuthorEmail, "checkout", "-b", "frogbot-fix-latest")
+	runGit(frogbotAuthorEmail, "commit", "--allow-empty", "--message", "Upgrade minimist")
+	runGit(frogbotAuthorEmail, "push", "origin", "main", "frogbot-fix", "frogbot-fix-edited", "frogbot-fix-latest")
+
+	for _, backend := range []string{"", GitCliBackend} {
+		t.Run("backend="+backend, func(t *testing.T) {
+			gitParams := &Git{EmailAuthor: frogbotAuthorEmail, ClientInfo: ClientInfo{GitBackend: backend}}
+			clonePath := filepath.Join(t.TempDir(), "frogbot")
+			gm := &GitManager{remoteName: "origin", git: gitParams}
+			if backend == GitCliBackend {
+				cli, err := newGitCli(".", nil, gitParams)
+				require.NoError(t, err)
+				gm.cli = cli
+				require.NoError(t, gm.cli.clone("file://"+remotePath, gm.remoteName, "main", clonePath, nil))
+			} else {
+				repository, err := git.PlainClone(clonePath, false, &git.CloneOptions{URL: "file://" + remotePath, ReferenceName: plumbing.NewBranchReferenceName("main")})
+				require.NoError(t, err)
+				gm.repository = repository
+			}
+
+			status, err := gm.GetFixBranchStatus("main", "frogbot-fix")
+			require.NoError(t, err)
+			assert.Equal(t, FixBranchStatus{OutOfDate: true}, *status)
+
+			status, err = gm.GetFixBranchStatus("main", "frogbot-fix-edited")
+			require.NoError(t, err)
+			assert.True(t, status.OutOfDate)
+			assert.Len(t, status.ForeignCommits, 1)
+
+			status, err = gm.GetFixBranchStatus("main", "frogbot-fix-latest")
+			require.NoError(t, err)
+			assert.Equal(t, FixBranchStatus{}, *status)
+		})
+	}
+}
+
+func TestGetChangedFiles(t *testing.T) {
+	remotePath := newGitCliTestRemote(t)
+	for _, backend := range []string{"", GitCliBackend} {
+		t.Run("backend="+backend, func(t *testing.T) {
+			gitParams := &Git{ClientInfo: ClientInfo{GitBackend: backend}}
+			clonePath := filepath.Join(t.TempDir(), "frogbot")
+			gm := &GitManager{remoteName: "origin", git: gitParams}
+			if backend == GitCliBackend {
+				cli, err := newGitCli(".", nil, gitParams)
+				require.NoError(t, err)
+				gm.cli = cli
+				require.NoError(t, gm.cli.clone("file://"+remotePath, gm.remoteName, "main", clonePath, nil))
+			} else {
+				repository, err := git.PlainClone(clonePath, false, &git.CloneOptions{URL: "file://" + remotePath})
+				require.NoError(t, err)
+				gm.repository = repository
+			}
+			changedFiles, err := gm.GetChangedFiles()
+			assert.NoError(t, err)
+			assert.Empty(t, changedFiles)
+
+			require.NoError(t, os.MkdirAll(filepath.Join(clonePath, "frontend"), 0700))
+			require.NoError(t, os.WriteFile(filepath.Join(clonePath, "frontend", "package.json"), []byte("{}"), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(clonePath, "go.mod"), []byte("module frogbot"), 0600))
+			changedFiles, err = gm.GetChangedFiles()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"frontend/package.json", "go.mod"}, changedFiles)
+		})
+	}
+}
+
+func TestResetWorktree(t *testing.T) {
+	remotePath := newGitCliTestRemote(t)
+	for _, backend := range []string{"", GitCliBackend} {
+		t.Run("backend="+backend, func(t *testing.T) {
+			gitParams := &Git{ClientInfo: ClientInfo{GitBackend: backend}, AuthorName: frogbotAuthorName, EmailAuthor: frogbotAuthorEmail}
+			clonePath := filepath.Join(t.TempDir(), "frogbot")
+			gm := &GitManager{remoteName: "origin", git: gitParams}
+			if backend == GitCliBackend {
+				cli, err := newGitCli(".", nil, gitParams)
+				require.NoError(t, err)
+				gm.cli = cli
+				require.NoError(t, gm.cli.clone("file://"+remotePath, gm.remoteName, "main", clonePath, nil))
+			} else {
+				repository, err := git.PlainClone(clonePath, false, &git.CloneOptions{URL: "file://" + remotePath})
+				require.NoError(t, err)
+				gm.repository = repository
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(clonePath, ".gitignore"), []byte("node_modules/\n"), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(clonePath, "package-lock.json"), []byte("{}"), 0600))
+			require.NoError(t, gm.AddAllAndCommit("Upgrade dependencies"))
+
+			// The files a verification creates, such as a rewritten lockfile, build outputs and installed dependencies
+			require.NoError(t, os.WriteFile(filepath.Join(clonePath, "package-lock.json"), []byte(`{"lockfileVersion": 3}`), 0600))
+			for _, dir := range []string{"coverage", "node_modules"} {
+				require.NoError(t, os.MkdirAll(filepath.Join(clonePath, dir, "nested"), 0700))
+				require.NoError(t, os.WriteFile(filepath.Join(clonePath, dir, "nested", "file"), []byte("content"), 0600))
+			}
+			require.NoError(t, gm.ResetWorktree())
+
+			content, err := os.ReadFile(filepath.Join(clonePath, "package-lock.json"))
+			require.NoError(t, err)
+			assert.Equal(t, "{}", string(content))
+			for _, dir := range []string{"coverage", "node_modules"} {
+				assert.NoDirExists(t, filepath.Join(clonePath, dir))
+			}
+			isClean, err := gm.IsClean()
+			require.NoError(t, err)
+			assert.True(t, isClean)
+		})
+	}
+}
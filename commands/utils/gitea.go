@@ -0,0 +1,596 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	giteaApiPath = "/api/v1"
+	// The maximal page size of the Gitea list requests
+	giteaPageLimit = 50
+	// The title prefix that marks pull requests as work in progress, by the default settings of Gitea
+	giteaDraftTitlePrefix = "WIP:"
+)
+
+// GiteaVcsProvider is the provider of Gitea and of its Forgejo fork, selected by JF_GIT_PROVIDER=gitea or JF_GIT_PROVIDER=forgejo.
+var GiteaVcsProvider = RegisterVcsProvider(VcsProviderPlugin{
+	Name:    string(Gitea),
+	Aliases: []string{string(Forgejo)},
+	NewClient: func(vcsInfo vcsclient.VcsInfo, transport http.RoundTripper) (vcsclient.VcsClient, error) {
+		return newGiteaClient(vcsInfo, transport)
+	},
+	HttpsCloneUrl: func(git *Git) string {
+		return fmt.Sprintf(giteaHttpsFormat, getGiteaServerUrl(git.APIEndpoint), git.RepoOwner, git.RepoName)
+	},
+})
+
+var errUnsupportedOnGitea = errors.New("this operation is not supported on Gitea")
+
+// giteaClient is a VcsClient of the Gitea REST API.
+type giteaClient struct {
+	// The URL of the Gitea REST API, such as https://gitea.example.com/api/v1
+	apiEndpoint string
+	token       string
+	httpClient  *http.Client
+}
+
+func newGiteaClient(vcsInfo vcsclient.VcsInfo, transport http.RoundTripper) (*giteaClient, error) {
+	if vcsInfo.APIEndpoint == "" {
+		return nil, fmt.Errorf("the %s environment variable must be set to the URL of the Gitea server", GitApiEndpointEnv)
+	}
+	return &giteaClient{apiEndpoint: getGiteaServerUrl(vcsInfo.APIEndpoint) + giteaApiPath, token: vcsInfo.Token, httpClient: &http.Client{Timeout: gitProviderRequestTimeout, Transport: transport}}, nil
+}
+
+// getGiteaServerUrl returns the URL of the Gitea server, which may be configured with or without the REST API path.
+func getGiteaServerUrl(apiEndpoint string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(apiEndpoint, "/"), giteaApiPath)
+}
+
+type giteaUser struct {
+	Login string `json:"login"`
+}
+
+type giteaRepository struct {
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Owner         giteaUser `json:"owner"`
+	Private       bool      `json:"private"`
+	Internal      bool      `json:"internal"`
+	CloneUrl      string    `json:"clone_url"`
+	SshUrl        string    `json:"ssh_url"`
+	DefaultBranch string    `json:"default_branch"`
+}
+
+type giteaBranch struct {
+	Name string `json:"name"`
+}
+
+type giteaPullRequestBranch struct {
+	Ref  string          `json:"ref"`
+	Repo giteaRepository `json:"repo"`
+}
+
+type giteaPullRequest struct {
+	Number int64                  `json:"number"`
+	Title  string                 `json:"title"`
+	Body   string                 `json:"body"`
+	Head   giteaPullRequestBranch `json:"head"`
+	Base   giteaPullRequestBranch `json:"base"`
+}
+
+type giteaComment struct {
+	ID      int64     `json:"id"`
+	Body    string    `json:"body"`
+	Created time.Time `json:"created_at"`
+}
+
+type giteaCommit struct {
+	Sha     string `json:"sha"`
+	HtmlUrl string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+		Committer struct {
+			Name string `json:"name"`
+		} `json:"committer"`
+	} `json:"commit"`
+	Parents []struct {
+		Sha string `json:"sha"`
+	} `json:"parents"`
+}
+
+type giteaCommitStatus struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	TargetUrl   string    `json:"target_url"`
+	Context     string    `json:"context"`
+	Creator     giteaUser `json:"creator"`
+	Created     time.Time `json:"created_at"`
+	Updated     time.Time `json:"updated_at"`
+}
+
+type giteaLabel struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type giteaWebhook struct {
+	ID int64 `json:"id"`
+}
+
+func (gc *giteaClient) TestConnection(ctx context.Context) error {
+	_, err := gc.sendRequest(ctx, http.MethodGet, "user", nil, nil)
+	return err
+}
+
+func (gc *giteaClient) ListRepositories(ctx context.Context) (map[string][]string, error) {
+	repositories, err := listGiteaPages[giteaRepository](ctx, gc, "user/repos")
+	if err != nil {
+		return nil, err
+	}
+	results := map[string][]string{}
+	for _, repository := range repositories {
+		results[repository.Owner.Login] = append(results[repository.Owner.Login], repository.Name)
+	}
+	return results, nil
+}
+
+func (gc *giteaClient) ListBranches(ctx context.Context, owner, repository string) ([]string, error) {
+	branches, err := listGiteaPages[giteaBranch](ctx, gc, repositoryApiPath(owner, repository, "branches"))
+	if err != nil {
+		return nil, err
+	}
+	var results []string
+	for _, branch := range branches {
+		results = append(results, branch.Name)
+	}
+	return results, nil
+}
+
+func (gc *giteaClient) CreateWebhook(ctx context.Context, owner, repository, branch, payloadURL string, webhookEvents ...vcsutils.WebhookEvent) (string, string, error) {
+	token := vcsutils.CreateToken()
+	webhook := &giteaWebhook{}
+	_, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "hooks"), giteaWebhookBody(branch, payloadURL, token, webhookEvents), webhook)
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.FormatInt(webhook.ID, 10), token, nil
+}
+
+func (gc *giteaClient) UpdateWebhook(ctx context.Context, owner, repository, branch, payloadURL, token, webhookID string, webhookEvents ...vcsutils.WebhookEvent) error {
+	_, err := gc.sendRequest(ctx, http.MethodPatch, repositoryApiPath(owner, repository, "hooks", webhookID), giteaWebhookBody(branch, payloadURL, token, webhookEvents), nil)
+	return err
+}
+
+func (gc *giteaClient) DeleteWebhook(ctx context.Context, owner, repository, webhookID string) error {
+	_, err := gc.sendRequest(ctx, http.MethodDelete, repositoryApiPath(owner, repository, "hooks", webhookID), nil, nil)
+	return err
+}
+
+func giteaWebhookBody(branch, payloadURL, token string, webhookEvents []vcsutils.WebhookEvent) map[string]interface{} {
+	var events []string
+	for _, event := range webhookEvents {
+		switch event {
+		case vcsutils.PrOpened, vcsutils.PrEdited, vcsutils.PrMerged, vcsutils.PrRejected:
+			events = appendIfMissing(events, "pull_request")
+		case vcsutils.Push:
+			events = appendIfMissing(events, "push")
+		case vcsutils.TagPushed:
+			events = appendIfMissing(events, "create")
+		case vcsutils.TagRemoved:
+			events = appendIfMissing(events, "delete")
+		}
+	}
+	return map[string]interface{}{
+		"type":          "gitea",
+		"active":        true,
+		"events":        events,
+		"branch_filter": branch,
+		"config":        map[string]string{"url": payloadURL, "content_type": "json", "secret": token},
+	}
+}
+
+func (gc *giteaClient) SetCommitStatus(ctx context.Context, commitStatus vcsclient.CommitStatus, owner, repository, ref, title, description, detailsURL string) error {
+	body := map[string]string{"state": giteaCommitStates[commitStatus], "context": title, "description": description, "target_url": detailsURL}
+	_, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "statuses", ref), body, nil)
+	return err
+}
+
+var giteaCommitStates = map[vcsclient.CommitStatus]string{
+	vcsclient.Pass:       "success",
+	vcsclient.Fail:       "failure",
+	vcsclient.Error:      "error",
+	vcsclient.InProgress: "pending",
+}
+
+func (gc *giteaClient) GetCommitStatuses(ctx context.Context, owner, repository, ref string) ([]vcsclient.CommitStatusInfo, error) {
+	statuses, err := listGiteaPages[giteaCommitStatus](ctx, gc, repositoryApiPath(owner, repository, "commits", ref, "statuses"))
+	if err != nil {
+		return nil, err
+	}
+	var results []vcsclient.CommitStatusInfo
+	for _, status := range statuses {
+		state := vcsclient.Error
+		for commitStatus, giteaState := range giteaCommitStates {
+			if giteaState == status.Status {
+				state = commitStatus
+			}
+		}
+		results = append(results, vcsclient.CommitStatusInfo{
+			State:         state,
+			Description:   status.Description,
+			DetailsUrl:    status.TargetUrl,
+			Creator:       status.Creator.Login,
+			CreatedAt:     status.Created,
+			LastUpdatedAt: status.Updated,
+		})
+	}
+	return results, nil
+}
+
+// DownloadRepository downloads the archive of the branch, and extracts it into a git repository with the Gitea server as its remote.
+func (gc *giteaClient) DownloadRepository(ctx context.Context, owner, repository, branch, localPath string) (err error) {
+	resp, err := gc.doRequest(ctx, http.MethodGet, repositoryApiPath(owner, repository, "archive", branch+".tar.gz"), nil)
+	if resp == nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if err != nil {
+		return
+	}
+	if err = vcsutils.Untar(localPath, resp.Body, true); err != nil {
+		return
+	}
+	log.Info("Extracted repository successfully")
+	repositoryInfo, err := gc.GetRepositoryInfo(ctx, owner, repository)
+	if err != nil {
+		return
+	}
+	return vcsutils.CreateDotGitFolderWithRemote(localPath, vcsutils.RemoteName, repositoryInfo.CloneInfo.HTTP)
+}
+
+func (gc *giteaClient) CreatePullRequest(ctx context.Context, owner, repository, sourceBranch, targetBranch, title, description string) error {
+	body := map[string]string{"head": sourceBranch, "base": targetBranch, "title": title, "body": description}
+	_, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "pulls"), body, nil)
+	return err
+}
+
+func (gc *giteaClient) UpdatePullRequest(ctx context.Context, owner, repository, title, body, targetBranchName string, prId int, state vcsutils.PullRequestState) error {
+	requestBody := map[string]string{"title": title, "body": body}
+	if targetBranchName != "" {
+		requestBody["base"] = targetBranchName
+	}
+	if state != "" {
+		requestBody["state"] = string(state)
+	}
+	_, err := gc.sendRequest(ctx, http.MethodPatch, repositoryApiPath(owner, repository, "pulls", strconv.Itoa(prId)), requestBody, nil)
+	return err
+}
+
+func (gc *giteaClient) AddPullRequestComment(ctx context.Context, owner, repository, content string, pullRequestID int) error {
+	_, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "issues", strconv.Itoa(pullRequestID), "comments"), map[string]string{"body": content}, nil)
+	return err
+}
+
+// AssignPullRequest adds the labels and reviewers to the pull request, and sets its assignees. Teams are requested to review by their names.
+func (gc *giteaClient) AssignPullRequest(ctx context.Context, owner, repository string, pullRequestID int, assignments *PullRequestAssignments) error {
+	index := strconv.Itoa(pullRequestID)
+	if len(assignments.Labels) > 0 {
+		// Gitea accepts both the IDs and the names of the labels
+		if _, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "issues", index, "labels"), map[string][]string{"labels": assignments.Labels}, nil); err != nil {
+			return err
+		}
+	}
+	if len(assignments.Reviewers) > 0 {
+		reviewers := map[string][]string{"reviewers": {}, "team_reviewers": {}}
+		for _, reviewer := range assignments.Reviewers {
+			if _, team, isTeam := strings.Cut(reviewer, "/"); isTeam {
+				reviewers["team_reviewers"] = append(reviewers["team_reviewers"], team)
+			} else {
+				reviewers["reviewers"] = append(reviewers["reviewers"], reviewer)
+			}
+		}
+		if _, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "pulls", index, "requested_reviewers"), reviewers, nil); err != nil {
+			return err
+		}
+	}
+	if len(assignments.Assignees) > 0 {
+		if _, err := gc.sendRequest(ctx, http.MethodPatch, repositoryApiPath(owner, repository, "issues", index), map[string][]string{"assignees": assignments.Assignees}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableAutoMerge schedules the pull request to be merged when its checks succeed.
+func (gc *giteaClient) EnableAutoMerge(ctx context.Context, owner, repository string, pullRequestID int, mergeMethod string) error {
+	body := map[string]interface{}{"Do": mergeMethod, "merge_when_checks_succeed": true}
+	_, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "pulls", strconv.Itoa(pullRequestID), "merge"), body, nil)
+	return err
+}
+
+// ConvertPullRequestToDraft marks the pull request as a work in progress, by prefixing its title with the default prefix of Gitea.
+func (gc *giteaClient) ConvertPullRequestToDraft(ctx context.Context, owner, repository string, pullRequestID int) error {
+	apiPath := repositoryApiPath(owner, repository, "pulls", strconv.Itoa(pullRequestID))
+	pullRequest := &giteaPullRequest{}
+	if _, err := gc.sendRequest(ctx, http.MethodGet, apiPath, nil, pullRequest); err != nil {
+		return err
+	}
+	if strings.HasPrefix(pullRequest.Title, giteaDraftTitlePrefix) {
+		return nil
+	}
+	_, err := gc.sendRequest(ctx, http.MethodPatch, apiPath, map[string]string{"title": giteaDraftTitlePrefix + " " + pullRequest.Title}, nil)
+	return err
+}
+
+func (gc *giteaClient) ListPullRequestComments(ctx context.Context, owner, repository string, pullRequestID int) ([]vcsclient.CommentInfo, error) {
+	comments, err := listGiteaPages[giteaComment](ctx, gc, repositoryApiPath(owner, repository, "issues", strconv.Itoa(pullRequestID), "comments"))
+	if err != nil {
+		return nil, err
+	}
+	var results []vcsclient.CommentInfo
+	for _, comment := range comments {
+		results = append(results, vcsclient.CommentInfo{ID: comment.ID, Content: comment.Body, Created: comment.Created})
+	}
+	return results, nil
+}
+
+func (gc *giteaClient) ListOpenPullRequestsWithBody(ctx context.Context, owner, repository string) ([]vcsclient.PullRequestInfo, error) {
+	return gc.listOpenPullRequests(ctx, owner, repository, true)
+}
+
+func (gc *giteaClient) ListOpenPullRequests(ctx context.Context, owner, repository string) ([]vcsclient.PullRequestInfo, error) {
+	return gc.listOpenPullRequests(ctx, owner, repository, false)
+}
+
+func (gc *giteaClient) listOpenPullRequests(ctx context.Context, owner, repository string, withBody bool) ([]vcsclient.PullRequestInfo, error) {
+	pullRequests, err := listGiteaPages[giteaPullRequest](ctx, gc, repositoryApiPath(owner, repository, "pulls")+"?state=open")
+	if err != nil {
+		return nil, err
+	}
+	var results []vcsclient.PullRequestInfo
+	for _, pullRequest := range pullRequests {
+		pullRequestInfo := vcsclient.PullRequestInfo{
+			ID:     pullRequest.Number,
+			Source: vcsclient.BranchInfo{Name: pullRequest.Head.Ref, Repository: pullRequest.Head.Repo.Name},
+			Target: vcsclient.BranchInfo{Name: pullRequest.Base.Ref, Repository: pullRequest.Base.Repo.Name},
+		}
+		if withBody {
+			pullRequestInfo.Body = pullRequest.Body
+		}
+		results = append(results, pullRequestInfo)
+	}
+	return results, nil
+}
+
+func (gc *giteaClient) GetLatestCommit(ctx context.Context, owner, repository, branch string) (vcsclient.CommitInfo, error) {
+	commits := []giteaCommit{}
+	_, err := gc.sendRequest(ctx, http.MethodGet, repositoryApiPath(owner, repository, "commits")+"?limit=1&sha="+url.QueryEscape(branch), nil, &commits)
+	if err != nil || len(commits) == 0 {
+		return vcsclient.CommitInfo{}, err
+	}
+	return commits[0].toCommitInfo(), nil
+}
+
+func (gc *giteaClient) GetCommitBySha(ctx context.Context, owner, repository, sha string) (vcsclient.CommitInfo, error) {
+	commit := &giteaCommit{}
+	if _, err := gc.sendRequest(ctx, http.MethodGet, repositoryApiPath(owner, repository, "git", "commits", sha), nil, commit); err != nil {
+		return vcsclient.CommitInfo{}, err
+	}
+	return commit.toCommitInfo(), nil
+}
+
+func (commit *giteaCommit) toCommitInfo() vcsclient.CommitInfo {
+	var parentHashes []string
+	for _, parent := range commit.Parents {
+		parentHashes = append(parentHashes, parent.Sha)
+	}
+	return vcsclient.CommitInfo{
+		Hash:          commit.Sha,
+		AuthorName:    commit.Commit.Author.Name,
+		CommitterName: commit.Commit.Committer.Name,
+		Url:           commit.HtmlUrl,
+		Timestamp:     commit.Commit.Author.Date.Unix(),
+		Message:       commit.Commit.Message,
+		ParentHashes:  parentHashes,
+	}
+}
+
+func (gc *giteaClient) AddSshKeyToRepository(ctx context.Context, owner, repository, keyName, publicKey string, permission vcsclient.Permission) error {
+	body := map[string]interface{}{"title": keyName, "key": publicKey, "read_only": permission != vcsclient.ReadWrite}
+	_, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "keys"), body, nil)
+	return err
+}
+
+func (gc *giteaClient) GetRepositoryInfo(ctx context.Context, owner, repository string) (vcsclient.RepositoryInfo, error) {
+	repositoryDetails := &giteaRepository{}
+	if _, err := gc.sendRequest(ctx, http.MethodGet, repositoryApiPath(owner, repository), nil, repositoryDetails); err != nil {
+		return vcsclient.RepositoryInfo{}, err
+	}
+	visibility := vcsclient.Public
+	switch {
+	case repositoryDetails.Private:
+		visibility = vcsclient.Private
+	case repositoryDetails.Internal:
+		visibility = vcsclient.Internal
+	}
+	return vcsclient.RepositoryInfo{
+		CloneInfo:            vcsclient.CloneInfo{HTTP: repositoryDetails.CloneUrl, SSH: repositoryDetails.SshUrl},
+		RepositoryVisibility: visibility,
+	}, nil
+}
+
+func (gc *giteaClient) CreateLabel(ctx context.Context, owner, repository string, labelInfo vcsclient.LabelInfo) error {
+	body := map[string]string{"name": labelInfo.Name, "description": labelInfo.Description, "color": "#" + strings.TrimPrefix(labelInfo.Color, "#")}
+	_, err := gc.sendRequest(ctx, http.MethodPost, repositoryApiPath(owner, repository, "labels"), body, nil)
+	return err
+}
+
+func (gc *giteaClient) GetLabel(ctx context.Context, owner, repository, name string) (*vcsclient.LabelInfo, error) {
+	label, err := gc.findLabel(ctx, owner, repository, name)
+	if err != nil || label == nil {
+		return nil, err
+	}
+	return &vcsclient.LabelInfo{Name: label.Name, Description: label.Description, Color: strings.TrimPrefix(label.Color, "#")}, nil
+}
+
+func (gc *giteaClient) findLabel(ctx context.Context, owner, repository, name string) (*giteaLabel, error) {
+	labels, err := listGiteaPages[giteaLabel](ctx, gc, repositoryApiPath(owner, repository, "labels"))
+	if err != nil {
+		return nil, err
+	}
+	for i := range labels {
+		if labels[i].Name == name {
+			return &labels[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (gc *giteaClient) ListPullRequestLabels(ctx context.Context, owner, repository string, pullRequestID int) ([]string, error) {
+	labels := []giteaLabel{}
+	if _, err := gc.sendRequest(ctx, http.MethodGet, repositoryApiPath(owner, repository, "issues", strconv.Itoa(pullRequestID), "labels"), nil, &labels); err != nil {
+		return nil, err
+	}
+	var results []string
+	for _, label := range labels {
+		results = append(results, label.Name)
+	}
+	return results, nil
+}
+
+func (gc *giteaClient) UnlabelPullRequest(ctx context.Context, owner, repository, name string, pullRequestID int) error {
+	label, err := gc.findLabel(ctx, owner, repository, name)
+	if err != nil || label == nil {
+		return err
+	}
+	_, err = gc.sendRequest(ctx, http.MethodDelete, repositoryApiPath(owner, repository, "issues", strconv.Itoa(pullRequestID), "labels", strconv.FormatInt(label.ID, 10)), nil, nil)
+	return err
+}
+
+func (gc *giteaClient) UploadCodeScanning(context.Context, string, string, string, string) (string, error) {
+	return "", errUnsupportedOnGitea
+}
+
+// DownloadFileFromRepo downloads a file from the branch of the repository, or from its default branch if the branch is empty.
+func (gc *giteaClient) DownloadFileFromRepo(ctx context.Context, owner, repository, branch, path string) (content []byte, statusCode int, err error) {
+	apiPath := repositoryApiPath(owner, repository, "raw") + "/" + strings.TrimPrefix(path, "/")
+	if branch != "" {
+		apiPath += "?ref=" + url.QueryEscape(branch)
+	}
+	resp, err := gc.doRequest(ctx, http.MethodGet, apiPath, nil)
+	if resp == nil {
+		return
+	}
+	statusCode = resp.StatusCode
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if err != nil {
+		return
+	}
+	content, err = io.ReadAll(resp.Body)
+	return
+}
+
+func (gc *giteaClient) GetRepositoryEnvironmentInfo(context.Context, string, string, string) (vcsclient.RepositoryEnvironmentInfo, error) {
+	return vcsclient.RepositoryEnvironmentInfo{}, errUnsupportedOnGitea
+}
+
+func (gc *giteaClient) GetModifiedFiles(context.Context, string, string, string, string) ([]string, error) {
+	return nil, errUnsupportedOnGitea
+}
+
+// repositoryApiPath returns the REST API path of the repository, followed by the escaped elements.
+func repositoryApiPath(owner, repository string, elements ...string) string {
+	apiPath := "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repository)
+	for _, element := range elements {
+		apiPath += "/" + url.PathEscape(element)
+	}
+	return apiPath
+}
+
+// listGiteaPages sends GET requests to the list API path, page by page, and returns the items of all the pages.
+func listGiteaPages[T any](ctx context.Context, gc *giteaClient, apiPath string) (items []T, err error) {
+	separator := "?"
+	if strings.Contains(apiPath, "?") {
+		separator = "&"
+	}
+	for page := 1; ; page++ {
+		var pageItems []T
+		if _, err = gc.sendRequest(ctx, http.MethodGet, fmt.Sprintf("%s%spage=%d&limit=%d", apiPath, separator, page, giteaPageLimit), nil, &pageItems); err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		if len(pageItems) < giteaPageLimit {
+			return
+		}
+	}
+}
+
+// sendRequest sends the body as JSON, and decodes the JSON response into result, unless result is nil.
+func (gc *giteaClient) sendRequest(ctx context.Context, method, apiPath string, body, result interface{}) (statusCode int, err error) {
+	var requestBody io.Reader
+	if body != nil {
+		var content []byte
+		if content, err = json.Marshal(body); err != nil {
+			return
+		}
+		requestBody = bytes.NewReader(content)
+	}
+	resp, err := gc.doRequest(ctx, method, apiPath, requestBody)
+	if resp == nil {
+		return
+	}
+	statusCode = resp.StatusCode
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if err != nil || result == nil {
+		return
+	}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	return
+}
+
+// doRequest sends the request, and returns an error if the response status isn't successful.
+// The response is returned in case of an unsuccessful status too, and its body must be closed by the caller.
+func (gc *giteaClient) doRequest(ctx context.Context, method, apiPath string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, gc.apiEndpoint+"/"+apiPath, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "token "+gc.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := gc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp, newHttpStatusError("Gitea", strings.SplitN(apiPath, "?", 2)[0], resp)
+	}
+	return resp, nil
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const giteaTestToken = "gitea-token"
+
+// newGiteaStandIn serves the Gitea REST API requests Frogbot sends, for the frogbot repository of the jfrog organization.
+// The bodies of the POST requests are recorded by their path.
+func newGiteaStandIn(t *testing.T, posted map[string]map[string]string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token "+giteaTestToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodPost {
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			posted[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		var err error
+		switch r.URL.Path {
+		case "/api/v1/repos/jfrog/frogbot":
+			err = json.NewEncoder(w).Encode(map[string]interface{}{"name": "frogbot", "private": true, "clone_url": server.URL + "/jfrog/frogbot.git", "ssh_url": "git@gitea:jfrog/frogbot.git"})
+		case "/api/v1/repos/jfrog/frogbot/raw/.frogbot/frogbot-config.yml":
+			if r.URL.Query().Get("ref") != "main" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, err = w.Write([]byte(bitbucketCloudTestConfig))
+		case "/api/v1/repos/jfrog/frogbot/pulls":
+			if r.URL.Query().Get("state") != "open" || r.URL.Query().Get("page") != "1" {
+				_, err = w.Write([]byte("[]"))
+				return
+			}
+			_, err = w.Write([]byte(`[{"number": 3, "body": "Fix", "head": {"ref": "feature", "repo": {"name": "frogbot"}}, "base": {"ref": "main", "repo": {"name": "frogbot"}}}]`))
+		case "/api/v1/repos/jfrog/frogbot/issues/3/comments":
+			_, err = w.Write([]byte(`[{"id": 7, "body": "Scanned", "created_at": "2023-06-01T10:00:00Z"}]`))
+		case "/api/v1/repos/jfrog/frogbot/archive/main.tar.gz":
+			writeGiteaArchive(t, w)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		assert.NoError(t, err)
+	}))
+	return server
+}
+
+// writeGiteaArchive writes a repository archive, in which the files are nested in a directory named after the repository.
+func writeGiteaArchive(t *testing.T, w io.Writer) {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	content := []byte(bitbucketCloudTestConfig)
+	assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "frogbot/", Typeflag: tar.TypeDir, Mode: 0755}))
+	assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "frogbot/frogbot-config.yml", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+	_, err := tarWriter.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+}
+
+func TestGiteaPullRequests(t *testing.T) {
+	posted := map[string]map[string]string{}
+	server := newGiteaStandIn(t, posted)
+	defer server.Close()
+	client, err := newGiteaClient(vcsclient.VcsInfo{APIEndpoint: server.URL, Token: giteaTestToken}, http.DefaultTransport)
+	require.NoError(t, err)
+	assert.Equal(t, gitProviderRequestTimeout, client.httpClient.Timeout)
+	ctx := context.Background()
+
+	pullRequests, err := client.ListOpenPullRequestsWithBody(ctx, "jfrog", "frogbot")
+	assert.NoError(t, err)
+	assert.Equal(t, []vcsclient.PullRequestInfo{{
+		ID:     3,
+		Body:   "Fix",
+		Source: vcsclient.BranchInfo{Name: "feature", Repository: "frogbot"},
+		Target: vcsclient.BranchInfo{Name: "main", Repository: "frogbot"},
+	}}, pullRequests)
+
+	comments, err := client.ListPullRequestComments(ctx, "jfrog", "frogbot", 3)
+	assert.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, int64(7), comments[0].ID)
+	assert.Equal(t, "Scanned", comments[0].Content)
+
+	assert.NoError(t, client.AddPullRequestComment(ctx, "jfrog", "frogbot", "New issues", 3))
+	assert.Equal(t, map[string]string{"body": "New issues"}, posted["/api/v1/repos/jfrog/frogbot/issues/3/comments"])
+	assert.NoError(t, client.CreatePullRequest(ctx, "jfrog", "frogbot", "frogbot-fix", "main", "Fix", "Fixes"))
+	assert.Equal(t, map[string]string{"head": "frogbot-fix", "base": "main", "title": "Fix", "body": "Fixes"}, posted["/api/v1/repos/jfrog/frogbot/pulls"])
+
+	// Unauthorized
+	client.token = "wrong-token"
+	_, err = client.ListOpenPullRequests(ctx, "jfrog", "frogbot")
+	assert.EqualError(t, err, "the Gitea request to repos/jfrog/frogbot/pulls returned status 401 Unauthorized")
+}
+
+func TestGiteaDownloadRepository(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	defer server.Close()
+	// The API endpoint may include the REST API path
+	client, err := newGiteaClient(vcsclient.VcsInfo{APIEndpoint: server.URL + "/api/v1/", Token: giteaTestToken}, http.DefaultTransport)
+	require.NoError(t, err)
+	localPath := t.TempDir()
+
+	assert.NoError(t, client.DownloadRepository(context.Background(), "jfrog", "frogbot", "main", localPath))
+	content, err := os.ReadFile(filepath.Join(localPath, "frogbot-config.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, bitbucketCloudTestConfig, string(content))
+	assert.DirExists(t, filepath.Join(localPath, ".git"))
+
+	repositoryInfo, err := client.GetRepositoryInfo(context.Background(), "jfrog", "frogbot")
+	assert.NoError(t, err)
+	assert.Equal(t, vcsclient.Private, repositoryInfo.RepositoryVisibility)
+	assert.Equal(t, "git@gitea:jfrog/frogbot.git", repositoryInfo.CloneInfo.SSH)
+}
+
+func TestGetFrogbotUtilsGitea(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	defer server.Close()
+	SetEnvAndAssert(t, map[string]string{
+		JFrogUrlEnv:       "http://127.0.0.1:8081",
+		JFrogTokenEnv:     "token",
+		GitProvider:       string(Forgejo),
+		GitApiEndpointEnv: server.URL,
+		GitRepoOwnerEnv:   "jfrog",
+		GitRepoEnv:        "frogbot",
+		GitTokenEnv:       giteaTestToken,
+		GitBaseBranchEnv:  "main",
+	})
+	frogbotUtils, err := GetFrogbotUtils()
+	assert.NoError(t, err)
+	require.Len(t, frogbotUtils.Repositories, 1)
+	repository := frogbotUtils.Repositories[0]
+	assert.Equal(t, GiteaVcsProvider, repository.GitProvider)
+	assert.Equal(t, "High", repository.MinSeverity)
+	assert.IsType(t, &StandardOutput{}, repository.OutputWriter)
+
+	// The API endpoint is mandatory
+	SetEnvAndAssert(t, map[string]string{
+		JFrogUrlEnv:      "http://127.0.0.1:8081",
+		JFrogTokenEnv:    "token",
+		GitProvider:      string(Gitea),
+		GitRepoOwnerEnv:  "jfrog",
+		GitRepoEnv:       "frogbot",
+		GitTokenEnv:      giteaTestToken,
+		GitBaseBranchEnv: "main",
+	})
+	_, err = GetFrogbotUtils()
+	assert.EqualError(t, err, "the JF_GIT_API_ENDPOINT environment variable must be set to the URL of the Gitea server")
+}
+
+func TestVcsProviderPlugins(t *testing.T) {
+	assert.Equal(t, "gitea", GetVcsProviderName(GiteaVcsProvider))
+	assert.Equal(t, vcsutils.GitLab.String(), GetVcsProviderName(vcsutils.GitLab))
+	assert.Nil(t, getVcsProviderPlugin(vcsutils.GitHub))
+	provider, exists := findVcsProviderPlugin("forgejo")
+	assert.True(t, exists)
+	assert.Equal(t, GiteaVcsProvider, provider)
+	_, exists = findVcsProviderPlugin("gogs")
+	assert.False(t, exists)
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// githubClient completes the GitHub client of froggit-go with the requests Frogbot relies on and the client doesn't support.
+type githubClient struct {
+	vcsclient.VcsClient
+	client *github.Client
+}
+
+func newGithubClient(client vcsclient.VcsClient, vcsInfo vcsclient.VcsInfo, transport http.RoundTripper) (vcsclient.VcsClient, error) {
+	ghClient := github.NewClient(&http.Client{Transport: &githubTokenTransport{token: vcsInfo.Token, transport: transport}})
+	if vcsInfo.APIEndpoint != "" {
+		baseURL, err := url.Parse(strings.TrimSuffix(vcsInfo.APIEndpoint, "/") + "/")
+		if err != nil {
+			return nil, err
+		}
+		ghClient.BaseURL = baseURL
+	}
+	return &githubClient{VcsClient: client, client: ghClient}, nil
+}
+
+// githubTokenTransport authenticates the requests with the token, and sends them through the transport of the Git provider.
+type githubTokenTransport struct {
+	token     string
+	transport http.RoundTripper
+}
+
+func (gt *githubTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if gt.token != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+gt.token)
+	}
+	return gt.transport.RoundTrip(req)
+}
+
+// AssignPullRequest adds the labels, reviewers and assignees to the pull request. Teams are requested to review by their slugs.
+func (gc *githubClient) AssignPullRequest(ctx context.Context, owner, repository string, pullRequestID int, assignments *PullRequestAssignments) error {
+	if len(assignments.Labels) > 0 {
+		if _, _, err := gc.client.Issues.AddLabelsToIssue(ctx, owner, repository, pullRequestID, assignments.Labels); err != nil {
+			return err
+		}
+	}
+	if len(assignments.Reviewers) > 0 {
+		var reviewers github.ReviewersRequest
+		for _, reviewer := range assignments.Reviewers {
+			if _, team, isTeam := strings.Cut(reviewer, "/"); isTeam {
+				reviewers.TeamReviewers = append(reviewers.TeamReviewers, team)
+			} else {
+				reviewers.Reviewers = append(reviewers.Reviewers, reviewer)
+			}
+		}
+		if _, _, err := gc.client.PullRequests.RequestReviewers(ctx, owner, repository, pullRequestID, reviewers); err != nil {
+			return err
+		}
+	}
+	if len(assignments.Assignees) > 0 {
+		if _, _, err := gc.client.Issues.AddAssignees(ctx, owner, repository, pullRequestID, assignments.Assignees); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	enableAutoMergeMutation = `mutation($pullRequestId: ID!, $mergeMethod: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $pullRequestId, mergeMethod: $mergeMethod}) { clientMutationId }
+}`
+	convertToDraftMutation = `mutation($pullRequestId: ID!) {
+  convertPullRequestToDraft(input: {pullRequestId: $pullRequestId}) { clientMutationId }
+}`
+)
+
+// EnableAutoMerge enables the auto-merge of the pull request, which is available through the GraphQL API only.
+// The repository must allow auto-merge, and the base branch must be protected with required checks.
+func (gc *githubClient) EnableAutoMerge(ctx context.Context, owner, repository string, pullRequestID int, mergeMethod string) error {
+	return gc.mutatePullRequest(ctx, owner, repository, pullRequestID, enableAutoMergeMutation, map[string]string{"mergeMethod": strings.ToUpper(mergeMethod)})
+}
+
+// ConvertPullRequestToDraft converts the pull request to a draft, which is available through the GraphQL API only.
+func (gc *githubClient) ConvertPullRequestToDraft(ctx context.Context, owner, repository string, pullRequestID int) error {
+	return gc.mutatePullRequest(ctx, owner, repository, pullRequestID, convertToDraftMutation, map[string]string{})
+}
+
+// mutatePullRequest runs the GraphQL mutation on the pull request, which is passed to the mutation as the $pullRequestId variable.
+func (gc *githubClient) mutatePullRequest(ctx context.Context, owner, repository string, pullRequestID int, mutation string, variables map[string]string) error {
+	pullRequest, _, err := gc.client.PullRequests.Get(ctx, owner, repository, pullRequestID)
+	if err != nil {
+		return err
+	}
+	variables["pullRequestId"] = pullRequest.GetNodeID()
+	req, err := gc.client.NewRequest(http.MethodPost, gc.graphqlUrl(), map[string]interface{}{"query": mutation, "variables": variables})
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err = gc.client.Do(ctx, req, &result); err != nil {
+		return err
+	}
+	for _, graphqlError := range result.Errors {
+		err = errors.Join(err, fmt.Errorf("the GraphQL mutation of pull request %d failed: %s", pullRequestID, graphqlError.Message))
+	}
+	return err
+}
+
+// graphqlUrl returns the URL of the GraphQL API, such as https://api.github.com/graphql, or https://<host>/api/graphql on GitHub Enterprise.
+func (gc *githubClient) graphqlUrl() string {
+	graphqlUrl := *gc.client.BaseURL
+	graphqlUrl.Path = strings.TrimSuffix(graphqlUrl.Path, "v3/") + "graphql"
+	return graphqlUrl.String()
+}
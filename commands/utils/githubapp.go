@@ -0,0 +1,403 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	githubApiEndpoint = "https://api.github.com"
+	// GitHub rejects app JWTs that expire more than 10 minutes after they're issued
+	githubAppJwtExpiration = 9 * time.Minute
+	// Installation tokens expire after an hour. They're replaced this long before they expire, so that no request uses an expired token.
+	githubAppTokenRefreshMargin = 5 * time.Minute
+	// The username of git operations authenticated with an installation token
+	githubAppGitUsername = "x-access-token"
+)
+
+// githubAppTokenSource mints installation access tokens of a GitHub App, and refreshes them before they expire.
+type githubAppTokenSource struct {
+	apiEndpoint    string
+	appId          string
+	installationId string
+	privateKey     *rsa.PrivateKey
+	httpClient     *http.Client
+	// now is replaceable for testing purposes
+	now       func() time.Time
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func newGithubAppTokenSource(apiEndpoint, appId, installationId string, privateKeyPem []byte, transport http.RoundTripper) (*githubAppTokenSource, error) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyPem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the GitHub App private key set in %s: %s", GitHubAppPrivateKeyEnv, err.Error())
+	}
+	if apiEndpoint == "" {
+		apiEndpoint = githubApiEndpoint
+	}
+	return &githubAppTokenSource{
+		apiEndpoint:    strings.TrimSuffix(apiEndpoint, "/"),
+		appId:          appId,
+		installationId: installationId,
+		privateKey:     privateKey,
+		httpClient:     &http.Client{Timeout: goGitTimeoutSeconds * time.Second, Transport: transport},
+		now:            time.Now,
+	}, nil
+}
+
+// readGithubAppFromEnv returns the token source of the GitHub App set in the environment variables, or nil if no GitHub App is set.
+func readGithubAppFromEnv(clientInfo *ClientInfo) (*githubAppTokenSource, error) {
+	appId := getTrimmedEnv(GitHubAppIdEnv)
+	if appId == "" {
+		return nil, nil
+	}
+	if clientInfo.GitProvider != vcsutils.GitHub {
+		return nil, fmt.Errorf("%s can be set only when %s is '%s'", GitHubAppIdEnv, GitProvider, GitHub)
+	}
+	var installationId string
+	if err := readParamFromEnv(GitHubAppInstallationIdEnv, &installationId); err != nil {
+		return nil, err
+	}
+	privateKey, err := readPrivateKeyEnv(GitHubAppPrivateKeyEnv)
+	if err != nil {
+		return nil, err
+	}
+	if privateKey == "" {
+		return nil, &ErrMissingEnv{VariableName: GitHubAppPrivateKeyEnv}
+	}
+	return newGithubAppTokenSource(clientInfo.APIEndpoint, appId, installationId, []byte(privateKey), clientInfo.getHttpTransport())
+}
+
+// Token returns an installation access token, which is valid for at least githubAppTokenRefreshMargin.
+func (ts *githubAppTokenSource) Token() (string, error) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	if ts.token != "" && ts.now().Add(githubAppTokenRefreshMargin).Before(ts.expiresAt) {
+		return ts.token, nil
+	}
+	log.Debug("Creating an installation access token of GitHub App", ts.appId)
+	token, expiresAt, err := ts.createInstallationToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to create an access token of GitHub App %s: %s", ts.appId, err.Error())
+	}
+	ts.token, ts.expiresAt = token, expiresAt
+	return token, nil
+}
+
+func (ts *githubAppTokenSource) createInstallationToken() (token string, expiresAt time.Time, err error) {
+	// Backdate the JWT, to allow for clock drift between the runner and GitHub
+	issuedAt := ts.now().Add(-time.Minute)
+	appJwt, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    ts.appId,
+		IssuedAt:  jwt.NewNumericDate(issuedAt),
+		ExpiresAt: jwt.NewNumericDate(issuedAt.Add(githubAppJwtExpiration)),
+	}).SignedString(ts.privateKey)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/app/installations/%s/access_tokens", ts.apiEndpoint, url.PathEscape(ts.installationId)), bytes.NewReader(nil))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+appJwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := ts.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusCreated {
+		err = fmt.Errorf("GitHub returned status %s", resp.Status)
+		return
+	}
+	var installationToken struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&installationToken); err != nil {
+		return
+	}
+	return installationToken.Token, installationToken.ExpiresAt, nil
+}
+
+// githubAppGitAuth authenticates git operations over HTTPS with the current installation token of the GitHub App.
+type githubAppGitAuth struct {
+	tokens *githubAppTokenSource
+}
+
+func (ga *githubAppGitAuth) Name() string {
+	return "http-github-app"
+}
+
+func (ga *githubAppGitAuth) String() string {
+	return fmt.Sprintf("%s - GitHub App %s", ga.Name(), ga.tokens.appId)
+}
+
+func (ga *githubAppGitAuth) SetAuth(r *http.Request) {
+	token, err := ga.tokens.Token()
+	if err != nil {
+		// The request fails with an authentication error, which is returned by the git operation
+		log.Warn(err.Error())
+	}
+	r.SetBasicAuth(githubAppGitUsername, token)
+}
+
+// githubAppClient is a GitHub client authenticated as a GitHub App.
+// The wrapped client is rebuilt whenever the installation token is refreshed, so that long runs don't fail once the first token expires.
+type githubAppClient struct {
+	vcsclient.VcsClient
+	tokens *githubAppTokenSource
+	token  string
+	build  func(token string) (vcsclient.VcsClient, error)
+}
+
+func newGithubAppClient(tokens *githubAppTokenSource, build func(token string) (vcsclient.VcsClient, error)) (vcsclient.VcsClient, error) {
+	gc := &githubAppClient{tokens: tokens, build: build}
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc, nil
+}
+
+func (gc *githubAppClient) refresh() error {
+	token, err := gc.tokens.Token()
+	if err != nil {
+		return err
+	}
+	if token == gc.token {
+		return nil
+	}
+	client, err := gc.build(token)
+	if err != nil {
+		return err
+	}
+	gc.VcsClient, gc.token = client, token
+	return nil
+}
+
+func (gc *githubAppClient) TestConnection(ctx context.Context) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.TestConnection(ctx)
+}
+
+func (gc *githubAppClient) ListRepositories(ctx context.Context) (map[string][]string, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.ListRepositories(ctx)
+}
+
+func (gc *githubAppClient) ListBranches(ctx context.Context, owner, repository string) ([]string, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.ListBranches(ctx, owner, repository)
+}
+
+func (gc *githubAppClient) CreateWebhook(ctx context.Context, owner, repository, branch, payloadURL string, webhookEvents ...vcsutils.WebhookEvent) (string, string, error) {
+	if err := gc.refresh(); err != nil {
+		return "", "", err
+	}
+	return gc.VcsClient.CreateWebhook(ctx, owner, repository, branch, payloadURL, webhookEvents...)
+}
+
+func (gc *githubAppClient) UpdateWebhook(ctx context.Context, owner, repository, branch, payloadURL, token, webhookID string, webhookEvents ...vcsutils.WebhookEvent) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.UpdateWebhook(ctx, owner, repository, branch, payloadURL, token, webhookID, webhookEvents...)
+}
+
+func (gc *githubAppClient) DeleteWebhook(ctx context.Context, owner, repository, webhookID string) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.DeleteWebhook(ctx, owner, repository, webhookID)
+}
+
+func (gc *githubAppClient) SetCommitStatus(ctx context.Context, commitStatus vcsclient.CommitStatus, owner, repository, ref, title, description, detailsURL string) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.SetCommitStatus(ctx, commitStatus, owner, repository, ref, title, description, detailsURL)
+}
+
+func (gc *githubAppClient) GetCommitStatuses(ctx context.Context, owner, repository, ref string) ([]vcsclient.CommitStatusInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.GetCommitStatuses(ctx, owner, repository, ref)
+}
+
+func (gc *githubAppClient) DownloadRepository(ctx context.Context, owner, repository, branch, localPath string) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.DownloadRepository(ctx, owner, repository, branch, localPath)
+}
+
+func (gc *githubAppClient) CreatePullRequest(ctx context.Context, owner, repository, sourceBranch, targetBranch, title, description string) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.CreatePullRequest(ctx, owner, repository, sourceBranch, targetBranch, title, description)
+}
+
+func (gc *githubAppClient) UpdatePullRequest(ctx context.Context, owner, repository, title, body, targetBranchName string, prId int, state vcsutils.PullRequestState) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.UpdatePullRequest(ctx, owner, repository, title, body, targetBranchName, prId, state)
+}
+
+func (gc *githubAppClient) AddPullRequestComment(ctx context.Context, owner, repository, content string, pullRequestID int) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.AddPullRequestComment(ctx, owner, repository, content, pullRequestID)
+}
+
+func (gc *githubAppClient) ListPullRequestComments(ctx context.Context, owner, repository string, pullRequestID int) ([]vcsclient.CommentInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.ListPullRequestComments(ctx, owner, repository, pullRequestID)
+}
+
+func (gc *githubAppClient) ListOpenPullRequestsWithBody(ctx context.Context, owner, repository string) ([]vcsclient.PullRequestInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.ListOpenPullRequestsWithBody(ctx, owner, repository)
+}
+
+func (gc *githubAppClient) ListOpenPullRequests(ctx context.Context, owner, repository string) ([]vcsclient.PullRequestInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.ListOpenPullRequests(ctx, owner, repository)
+}
+
+func (gc *githubAppClient) GetLatestCommit(ctx context.Context, owner, repository, branch string) (vcsclient.CommitInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return vcsclient.CommitInfo{}, err
+	}
+	return gc.VcsClient.GetLatestCommit(ctx, owner, repository, branch)
+}
+
+func (gc *githubAppClient) AddSshKeyToRepository(ctx context.Context, owner, repository, keyName, publicKey string, permission vcsclient.Permission) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.AddSshKeyToRepository(ctx, owner, repository, keyName, publicKey, permission)
+}
+
+func (gc *githubAppClient) GetRepositoryInfo(ctx context.Context, owner, repository string) (vcsclient.RepositoryInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return vcsclient.RepositoryInfo{}, err
+	}
+	return gc.VcsClient.GetRepositoryInfo(ctx, owner, repository)
+}
+
+func (gc *githubAppClient) GetCommitBySha(ctx context.Context, owner, repository, sha string) (vcsclient.CommitInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return vcsclient.CommitInfo{}, err
+	}
+	return gc.VcsClient.GetCommitBySha(ctx, owner, repository, sha)
+}
+
+func (gc *githubAppClient) CreateLabel(ctx context.Context, owner, repository string, labelInfo vcsclient.LabelInfo) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.CreateLabel(ctx, owner, repository, labelInfo)
+}
+
+func (gc *githubAppClient) GetLabel(ctx context.Context, owner, repository, name string) (*vcsclient.LabelInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.GetLabel(ctx, owner, repository, name)
+}
+
+func (gc *githubAppClient) ListPullRequestLabels(ctx context.Context, owner, repository string, pullRequestID int) ([]string, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.ListPullRequestLabels(ctx, owner, repository, pullRequestID)
+}
+
+func (gc *githubAppClient) UnlabelPullRequest(ctx context.Context, owner, repository, name string, pullRequestID int) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return gc.VcsClient.UnlabelPullRequest(ctx, owner, repository, name, pullRequestID)
+}
+
+func (gc *githubAppClient) UploadCodeScanning(ctx context.Context, owner, repository, branch, scanResults string) (string, error) {
+	if err := gc.refresh(); err != nil {
+		return "", err
+	}
+	return gc.VcsClient.UploadCodeScanning(ctx, owner, repository, branch, scanResults)
+}
+
+func (gc *githubAppClient) DownloadFileFromRepo(ctx context.Context, owner, repository, branch, path string) ([]byte, int, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, 0, err
+	}
+	return gc.VcsClient.DownloadFileFromRepo(ctx, owner, repository, branch, path)
+}
+
+func (gc *githubAppClient) GetRepositoryEnvironmentInfo(ctx context.Context, owner, repository, name string) (vcsclient.RepositoryEnvironmentInfo, error) {
+	if err := gc.refresh(); err != nil {
+		return vcsclient.RepositoryEnvironmentInfo{}, err
+	}
+	return gc.VcsClient.GetRepositoryEnvironmentInfo(ctx, owner, repository, name)
+}
+
+func (gc *githubAppClient) GetModifiedFiles(ctx context.Context, owner, repository, refBefore, refAfter string) ([]string, error) {
+	if err := gc.refresh(); err != nil {
+		return nil, err
+	}
+	return gc.VcsClient.GetModifiedFiles(ctx, owner, repository, refBefore, refAfter)
+}
+
+func (gc *githubAppClient) AssignPullRequest(ctx context.Context, owner, repository string, pullRequestID int, assignments *PullRequestAssignments) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return AssignPullRequest(ctx, gc.VcsClient, owner, repository, pullRequestID, assignments)
+}
+
+func (gc *githubAppClient) EnableAutoMerge(ctx context.Context, owner, repository string, pullRequestID int, mergeMethod string) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return EnableAutoMerge(ctx, gc.VcsClient, owner, repository, pullRequestID, mergeMethod)
+}
+
+func (gc *githubAppClient) ConvertPullRequestToDraft(ctx context.Context, owner, repository string, pullRequestID int) error {
+	if err := gc.refresh(); err != nil {
+		return err
+	}
+	return ConvertPullRequestToDraft(ctx, gc.VcsClient, owner, repository, pullRequestID)
+}
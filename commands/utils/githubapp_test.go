@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	githubAppTestId             = "123"
+	githubAppTestInstallationId = "456"
+)
+
+// newGithubAppStandIn serves the installation access tokens endpoint of GitHub, and returns a new token on every request.
+// The tokens expire an hour after the time returned by now.
+func newGithubAppStandIn(t *testing.T, privateKeyPem string, now func() time.Time) (*httptest.Server, *int) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPem))
+	require.NoError(t, err)
+	minted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/"+githubAppTestInstallationId+"/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(r.Header.Get("Authorization")[len("Bearer "):], claims, func(*jwt.Token) (interface{}, error) {
+			return &privateKey.PublicKey, nil
+		}, jwt.WithoutClaimsValidation())
+		if err != nil || claims.Issuer != githubAppTestId {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, githubAppJwtExpiration, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+		minted++
+		w.WriteHeader(http.StatusCreated)
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":      fmt.Sprintf("ghs_%d", minted),
+			"expires_at": now().Add(time.Hour).UTC().Format(time.RFC3339),
+		}))
+	}))
+	return server, &minted
+}
+
+func TestGithubAppTokenSource(t *testing.T) {
+	privateKey, _ := generateSshKey(t)
+	now := time.Now()
+	clock := func() time.Time { return now }
+	server, minted := newGithubAppStandIn(t, privateKey, clock)
+	defer server.Close()
+	tokens, err := newGithubAppTokenSource(server.URL, githubAppTestId, githubAppTestInstallationId, []byte(privateKey), http.DefaultTransport)
+	require.NoError(t, err)
+	tokens.now = clock
+
+	token, err := tokens.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_1", token)
+	// The token is reused while it's valid
+	now = now.Add(50 * time.Minute)
+	token, err = tokens.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_1", token)
+	assert.Equal(t, 1, *minted)
+	// The token is replaced shortly before it expires
+	now = now.Add(6 * time.Minute)
+	token, err = tokens.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_2", token)
+
+	// Git operations authenticate with the current token
+	now = now.Add(time.Hour)
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	(&githubAppGitAuth{tokens: tokens}).SetAuth(req)
+	username, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, githubAppGitUsername, username)
+	assert.Equal(t, "ghs_3", password)
+
+	// Unknown installation
+	tokens.installationId = "789"
+	tokens.token = ""
+	_, err = tokens.Token()
+	assert.EqualError(t, err, "failed to create an access token of GitHub App 123: GitHub returned status 404 Not Found")
+
+	_, err = newGithubAppTokenSource(server.URL, githubAppTestId, githubAppTestInstallationId, []byte("invalid"), http.DefaultTransport)
+	assert.ErrorContains(t, err, "failed to parse the GitHub App private key set in JF_GITHUB_APP_PRIVATE_KEY")
+}
+
+func TestGithubAppClient(t *testing.T) {
+	privateKey, _ := generateSshKey(t)
+	now := time.Now()
+	clock := func() time.Time { return now }
+	server, _ := newGithubAppStandIn(t, privateKey, clock)
+	defer server.Close()
+	tokens, err := newGithubAppTokenSource(server.URL, githubAppTestId, githubAppTestInstallationId, []byte(privateKey), http.DefaultTransport)
+	require.NoError(t, err)
+	tokens.now = clock
+
+	var builtWith []string
+	client, err := newGithubAppClient(tokens, func(token string) (vcsclient.VcsClient, error) {
+		builtWith = append(builtWith, token)
+		return vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token(token).Build()
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghs_1"}, builtWith)
+	// The client is rebuilt only once the token is refreshed
+	_, _ = client.ListOpenPullRequests(context.Background(), "jfrog", "frogbot")
+	assert.Equal(t, []string{"ghs_1"}, builtWith)
+	now = now.Add(time.Hour)
+	_, _ = client.ListOpenPullRequests(context.Background(), "jfrog", "frogbot")
+	assert.Equal(t, []string{"ghs_1", "ghs_2"}, builtWith)
+}
+
+func TestExtractClientInfoGithubApp(t *testing.T) {
+	privateKey, _ := generateSshKey(t)
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	require.NoError(t, os.WriteFile(keyFile, []byte(privateKey), 0600))
+	server, _ := newGithubAppStandIn(t, privateKey, time.Now)
+	defer server.Close()
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+
+	// The token isn't required
+	SetEnvAndAssert(t, map[string]string{
+		GitProvider:                string(GitHub),
+		GitApiEndpointEnv:          server.URL,
+		GitRepoOwnerEnv:            "jfrog",
+		GitHubAppIdEnv:             githubAppTestId,
+		GitHubAppInstallationIdEnv: githubAppTestInstallationId,
+		GitHubAppPrivateKeyEnv:     keyFile,
+	})
+	clientInfo, err := extractClientInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_1", clientInfo.Token)
+	assert.NotNil(t, clientInfo.githubApp)
+	auth, err := toGitAuth(clientInfo.Token, clientInfo.Username, clientInfo.SshAuth, clientInfo.githubApp)
+	assert.NoError(t, err)
+	assert.IsType(t, &githubAppGitAuth{}, auth)
+
+	SetEnvAndAssert(t, map[string]string{GitHubAppInstallationIdEnv: ""})
+	_, err = extractClientInfo()
+	assert.ErrorContains(t, err, GitHubAppInstallationIdEnv)
+
+	SetEnvAndAssert(t, map[string]string{GitHubAppInstallationIdEnv: githubAppTestInstallationId, GitProvider: string(GitLab)})
+	_, err = extractClientInfo()
+	assert.EqualError(t, err, "JF_GITHUB_APP_ID can be set only when JF_GIT_PROVIDER is 'github'")
+}
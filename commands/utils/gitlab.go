@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	gitlabDefaultServerUrl = "https://gitlab.com"
+	gitlabApiPath          = "/api/v4"
+)
+
+// gitlabClient completes the GitLab client of froggit-go with the requests Frogbot relies on and the client doesn't support.
+type gitlabClient struct {
+	vcsclient.VcsClient
+	// The URL of the GitLab REST API, such as https://gitlab.com/api/v4
+	apiEndpoint string
+	token       string
+	httpClient  *http.Client
+}
+
+func newGitlabClient(client vcsclient.VcsClient, vcsInfo vcsclient.VcsInfo, transport http.RoundTripper) vcsclient.VcsClient {
+	// The API endpoint may be configured with or without the REST API path, as in froggit-go
+	serverUrl := strings.TrimSuffix(strings.TrimSuffix(vcsInfo.APIEndpoint, "/"), gitlabApiPath)
+	if serverUrl == "" {
+		serverUrl = gitlabDefaultServerUrl
+	}
+	return &gitlabClient{VcsClient: client, apiEndpoint: serverUrl + gitlabApiPath, token: vcsInfo.Token, httpClient: &http.Client{Timeout: gitProviderRequestTimeout, Transport: transport}}
+}
+
+// AssignPullRequest adds the labels to the merge request, and sets its reviewers and assignees.
+// Reviewers and assignees that aren't users, such as groups, are skipped.
+func (gc *gitlabClient) AssignPullRequest(ctx context.Context, owner, repository string, pullRequestID int, assignments *PullRequestAssignments) error {
+	update := map[string]interface{}{}
+	if len(assignments.Labels) > 0 {
+		update["add_labels"] = strings.Join(assignments.Labels, ",")
+	}
+	if len(assignments.Reviewers) > 0 {
+		reviewerIds, err := gc.getUserIds(ctx, assignments.Reviewers)
+		if err != nil {
+			return err
+		}
+		update["reviewer_ids"] = reviewerIds
+	}
+	if len(assignments.Assignees) > 0 {
+		assigneeIds, err := gc.getUserIds(ctx, assignments.Assignees)
+		if err != nil {
+			return err
+		}
+		update["assignee_ids"] = assigneeIds
+	}
+	apiPath := fmt.Sprintf("projects/%s/merge_requests/%d", url.PathEscape(owner+"/"+repository), pullRequestID)
+	_, err := gc.send(ctx, http.MethodPut, apiPath, update)
+	return err
+}
+
+// EnableAutoMerge sets the merge request to be merged when its pipeline succeeds.
+// GitLab merges merge requests without a pipeline immediately, so auto-merge is enabled only if the merge request has a head pipeline.
+// GitLab merges or squashes merge requests according to the project settings, so the rebase method merges them.
+func (gc *gitlabClient) EnableAutoMerge(ctx context.Context, owner, repository string, pullRequestID int, mergeMethod string) error {
+	apiPath := fmt.Sprintf("projects/%s/merge_requests/%d", url.PathEscape(owner+"/"+repository), pullRequestID)
+	content, err := gc.send(ctx, http.MethodGet, apiPath, nil)
+	if err != nil {
+		return err
+	}
+	var mergeRequest struct {
+		HeadPipeline *struct {
+			Id int `json:"id"`
+		} `json:"head_pipeline"`
+	}
+	if err = json.Unmarshal(content, &mergeRequest); err != nil {
+		return err
+	}
+	if mergeRequest.HeadPipeline == nil {
+		log.Warn(fmt.Sprintf("Merge request %d has no pipeline, so it won't be merged automatically", pullRequestID))
+		return nil
+	}
+	_, err = gc.send(ctx, http.MethodPut, apiPath+"/merge", map[string]interface{}{
+		"merge_when_pipeline_succeeds": true,
+		"squash":                       mergeMethod == MergeMethodSquash,
+	})
+	return err
+}
+
+// ConvertPullRequestToDraft marks the merge request as a draft, by prefixing its title.
+func (gc *gitlabClient) ConvertPullRequestToDraft(ctx context.Context, owner, repository string, pullRequestID int) error {
+	apiPath := fmt.Sprintf("projects/%s/merge_requests/%d", url.PathEscape(owner+"/"+repository), pullRequestID)
+	content, err := gc.send(ctx, http.MethodGet, apiPath, nil)
+	if err != nil {
+		return err
+	}
+	var mergeRequest struct {
+		Title string `json:"title"`
+		Draft bool   `json:"draft"`
+	}
+	if err = json.Unmarshal(content, &mergeRequest); err != nil || mergeRequest.Draft {
+		return err
+	}
+	_, err = gc.send(ctx, http.MethodPut, apiPath, map[string]string{"title": "Draft: " + mergeRequest.Title})
+	return err
+}
+
+func (gc *gitlabClient) getUserIds(ctx context.Context, usernames []string) ([]int, error) {
+	var ids []int
+	for _, username := range usernames {
+		content, err := gc.send(ctx, http.MethodGet, "users?username="+url.QueryEscape(username), nil)
+		if err != nil {
+			return nil, err
+		}
+		var users []struct {
+			Id int `json:"id"`
+		}
+		if err = json.Unmarshal(content, &users); err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			log.Debug(fmt.Sprintf("Skipping '%s', which isn't a GitLab user", username))
+			continue
+		}
+		ids = append(ids, users[0].Id)
+	}
+	return ids, nil
+}
+
+func (gc *gitlabClient) send(ctx context.Context, method, apiPath string, body interface{}) (content []byte, err error) {
+	var requestBody io.Reader
+	if body != nil {
+		var payload []byte
+		if payload, err = json.Marshal(body); err != nil {
+			return
+		}
+		requestBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, gc.apiEndpoint+"/"+apiPath, requestBody)
+	if err != nil {
+		return
+	}
+	req.Header.Set("PRIVATE-TOKEN", gc.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := gc.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if content, err = io.ReadAll(resp.Body); err != nil {
+		return
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		err = newHttpStatusError("GitLab", strings.SplitN(apiPath, "?", 2)[0], resp)
+	}
+	return
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The user of the SSH connections to the Git providers
+const sshUser = "git"
+
+// SshAuth holds the credentials of git operations over SSH.
+type SshAuth struct {
+	// The PEM encoded private key, such as a deploy key. If empty, git operations run over HTTPS with the token.
+	PrivateKey string
+	Passphrase string
+	// Entries in the known_hosts format. If empty, the known_hosts files of the user are used.
+	KnownHosts string
+}
+
+func (sa *SshAuth) IsSet() bool {
+	return sa.PrivateKey != ""
+}
+
+// readSshAuthFromEnv reads the SSH credentials. The private key may be set as the key itself, or as the path of the key file.
+func readSshAuthFromEnv() (sshAuth SshAuth, err error) {
+	if sshAuth.PrivateKey, err = readPrivateKeyEnv(GitSshKeyEnv); err != nil {
+		return
+	}
+	sshAuth.Passphrase = os.Getenv(GitSshKeyPassphraseEnv)
+	sshAuth.KnownHosts = getTrimmedEnv(GitSshKnownHostsEnv)
+	return
+}
+
+// readPrivateKeyEnv returns the PEM encoded private key set in the environment variable, either as the key itself or as the path of the key file.
+func readPrivateKeyEnv(envKey string) (string, error) {
+	return readPemEnv(envKey, "private key")
+}
+
+// readPemEnv returns the PEM encoded value set in the environment variable, either as PEM or as the path of a PEM file.
+func readPemEnv(envKey, kind string) (string, error) {
+	value := getTrimmedEnv(envKey)
+	if value == "" || strings.Contains(value, "-----BEGIN ") {
+		return value, nil
+	}
+	content, err := os.ReadFile(value)
+	if err != nil {
+		return "", fmt.Errorf("%s should be a %s or the path of a %s file: %s", envKey, kind, kind, err.Error())
+	}
+	return string(content), nil
+}
+
+// toGitAuth returns the authentication of the git remote operations: the SSH key if set, the installation token of the GitHub App if set, and the token otherwise.
+func toGitAuth(token, username string, sshAuth SshAuth, githubApp *githubAppTokenSource) (transport.AuthMethod, error) {
+	if sshAuth.IsSet() {
+		return toSshAuth(sshAuth)
+	}
+	if githubApp != nil {
+		return &githubAppGitAuth{tokens: githubApp}, nil
+	}
+	return toBasicAuth(token, username), nil
+}
+
+// toSshAuth returns an SSH authentication, which verifies the host key of the Git server against the known hosts.
+func toSshAuth(sshAuth SshAuth) (publicKeys *gitssh.PublicKeys, err error) {
+	publicKeys, err = gitssh.NewPublicKeys(sshUser, []byte(sshAuth.PrivateKey), sshAuth.Passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SSH private key set in %s: %s", GitSshKeyEnv, err.Error())
+	}
+	if sshAuth.KnownHosts == "" {
+		if publicKeys.HostKeyCallback, err = gitssh.NewKnownHostsCallback(); err != nil {
+			return nil, fmt.Errorf("failed to load the known_hosts files. Set the known hosts of the Git server in %s: %s", GitSshKnownHostsEnv, err.Error())
+		}
+		return publicKeys, nil
+	}
+	// The known hosts callback only reads files, so the entries are written to a temporary file, which is read once.
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, fileutils.RemoveTempDir(tempDir))
+	}()
+	knownHostsFile := filepath.Join(tempDir, "known_hosts")
+	if err = os.WriteFile(knownHostsFile, []byte(sshAuth.KnownHosts+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	if publicKeys.HostKeyCallback, err = gitssh.NewKnownHostsCallback(knownHostsFile); err != nil {
+		return nil, fmt.Errorf("failed to parse the known hosts set in %s: %s", GitSshKnownHostsEnv, err.Error())
+	}
+	return publicKeys, nil
+}
+
+// isSshUrl returns true for SSH remote URLs, such as ssh://git@host:7999/project/repo.git and git@host:owner/repo.git.
+func isSshUrl(remoteUrl string) bool {
+	if strings.HasPrefix(remoteUrl, "ssh://") {
+		return true
+	}
+	return !strings.Contains(remoteUrl, "://") && strings.Contains(remoteUrl, "@") && strings.Contains(remoteUrl, ":")
+}
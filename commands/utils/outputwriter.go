@@ -31,9 +31,13 @@ type OutputWriter interface {
 
 func GetCompatibleOutputWriter(provider vcsutils.VcsProvider) OutputWriter {
 	switch provider {
-	case vcsutils.BitbucketServer:
+	// Bitbucket doesn't render HTML in markdown
+	case vcsutils.BitbucketServer, vcsutils.BitbucketCloud:
 		return &SimplifiedOutput{vcsProvider: provider}
 	default:
+		if plugin := getVcsProviderPlugin(provider); plugin != nil && plugin.SimplifiedOutput {
+			return &SimplifiedOutput{vcsProvider: provider}
+		}
 		return &StandardOutput{vcsProvider: provider}
 	}
 }
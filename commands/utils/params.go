@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
@@ -21,14 +22,16 @@ import (
 )
 
 const (
-	frogbotConfigDir  = ".frogbot"
+	FrogbotConfigDir  = ".frogbot"
 	FrogbotConfigFile = "frogbot-config.yml"
+	// The default number of minutes the verify command of a project may run
+	defaultVerifyCommandTimeout = 30
 )
 
 var (
 	errFrogbotConfigNotFound = fmt.Errorf("%s wasn't found in the Frogbot directory and its subdirectories. Assuming all the configuration is stored as environment variables", FrogbotConfigFile)
 	// Possible Config file path's to Frogbot Management repository
-	osFrogbotConfigPath = filepath.Join(frogbotConfigDir, FrogbotConfigFile)
+	osFrogbotConfigPath = filepath.Join(FrogbotConfigDir, FrogbotConfigFile)
 )
 
 type FrogbotUtils struct {
@@ -50,6 +53,9 @@ type Params struct {
 	Scan          `yaml:"scan,omitempty"`
 	Git           `yaml:"git,omitempty"`
 	JFrogPlatform `yaml:"jfrogPlatform,omitempty"`
+	// Scan and Git settings that override the above for the target branches matching the glob keys, such as release/*.
+	// Kept as a raw mapping node, to preserve the order of the policies and to tell which settings each policy sets.
+	BranchPolicies yaml.Node `yaml:"branchPolicies,omitempty"`
 }
 
 func (p *Params) setDefaultsIfNeeded(git *Git) error {
@@ -68,8 +74,42 @@ type Project struct {
 	WorkingDirs         []string `yaml:"workingDirs,omitempty"`
 	UseWrapper          *bool    `yaml:"useWrapper,omitempty"`
 	Repository          string   `yaml:"repository,omitempty"`
-	InstallCommandName  string
-	InstallCommandArgs  []string
+	// Scan every project found under the working directories, instead of the working directories themselves
+	AutoDiscover bool `yaml:"autoDiscover,omitempty"`
+	// The name shown next to the issues of the project, when the repository includes multiple projects
+	Name string `yaml:"name,omitempty"`
+	// The maximum number of open Frogbot pull requests that fix the project, in addition to the limit of the repository
+	MaxOpenPullRequests int `yaml:"maxOpenPullRequests,omitempty"`
+	// A command that builds or tests the project, such as 'mvn -q verify' or 'npm test', run after its dependencies are fixed to verify the fixes.
+	// The command is split by whitespaces and run without a shell, so shell operators, such as && and pipes, and quoted arguments aren't supported.
+	VerifyCommand string `yaml:"verifyCommand,omitempty"`
+	// The number of minutes the verify command may run before it's stopped and the verification fails
+	VerifyCommandTimeout int `yaml:"verifyCommandTimeout,omitempty"`
+	// Scan settings that override the repository settings for this project
+	MinSeverity        string `yaml:"minSeverity,omitempty"`
+	FixableOnly        *bool  `yaml:"fixableOnly,omitempty"`
+	JFrogPlatform      `yaml:",inline"`
+	InstallCommandName string
+	InstallCommandArgs []string
+}
+
+// GetVerifyCommandTimeout returns the time the verify command of the project may run.
+func (p *Project) GetVerifyCommandTimeout() time.Duration {
+	if p.VerifyCommandTimeout <= 0 {
+		return defaultVerifyCommandTimeout * time.Minute
+	}
+	return time.Duration(p.VerifyCommandTimeout) * time.Minute
+}
+
+// Label returns the name that identifies the project in Frogbot comments.
+func (p *Project) Label() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if p.JFrogProjectKey != "" {
+		return p.JFrogProjectKey
+	}
+	return strings.Join(p.WorkingDirs, ", ")
 }
 
 func (p *Project) setDefaultsIfNeeded() error {
@@ -87,19 +127,37 @@ func (p *Project) setDefaultsIfNeeded() error {
 		}
 		p.UseWrapper = &useWrapper
 	}
+	if !p.AutoDiscover {
+		autoDiscover, err := getBoolEnv(AutoDiscoverEnv, false)
+		if err != nil {
+			return err
+		}
+		p.AutoDiscover = autoDiscover
+	}
 	if p.InstallCommand == "" {
 		p.InstallCommand = getTrimmedEnv(InstallCommandEnv)
 	}
 	if p.InstallCommand != "" {
 		setProjectInstallCommand(p.InstallCommand, p)
 	}
+	if p.VerifyCommand == "" {
+		p.VerifyCommand = getTrimmedEnv(VerifyCommandEnv)
+	}
+	if p.VerifyCommandTimeout == 0 {
+		var err error
+		if p.VerifyCommandTimeout, err = getIntEnv(VerifyCommandTimeoutEnv, defaultVerifyCommandTimeout); err != nil {
+			return err
+		}
+	}
 	if p.PipRequirementsFile == "" {
 		p.PipRequirementsFile = getTrimmedEnv(RequirementsFileEnv)
 	}
 	if p.Repository == "" {
 		p.Repository = getTrimmedEnv(DepsRepoEnv)
 	}
-	return nil
+	var err error
+	p.MinSeverity, err = xrutils.GetSeveritiesFormat(p.MinSeverity)
+	return err
 }
 
 type Scan struct {
@@ -183,6 +241,40 @@ type ClientInfo struct {
 	RepoName  string   `yaml:"repoName,omitempty"`
 	Branches  []string `yaml:"branches,omitempty"`
 	RepoOwner string
+	// The number of times to retry VCS REST API requests and git remote operations that failed due to transient errors
+	MaxRetries int
+	// The credentials of git remote operations over SSH, used instead of the token if set
+	SshAuth SshAuth
+	// Either go-git (the default), or cli to run the git operations with the git executable
+	GitBackend string
+	// The filter of partial clones, such as blob:none. Supported by the git CLI backend only.
+	CloneFilter string
+	// The number of commits to clone. If 0, the entire history is cloned.
+	CloneDepth int
+	// Check out only the working directories of the projects. Supported by the git CLI backend only.
+	SparseCheckout bool
+	// The key that signs the commits of Frogbot, if set
+	CommitSigningKey CommitSigningKey
+	// The proxy and TLS settings of the connections to the Git provider
+	HttpTransport HttpTransportSettings
+	// The transport that applies the HttpTransport settings, if set
+	httpTransport http.RoundTripper
+	// The GitHub App whose installation tokens are used instead of the token, if set
+	githubApp *githubAppTokenSource
+}
+
+// getHttpTransport returns the transport of the connections to the Git provider.
+func (ci *ClientInfo) getHttpTransport() http.RoundTripper {
+	if ci.httpTransport != nil {
+		return ci.httpTransport
+	}
+	return defaultHttpTransport
+}
+
+// IsShallowOrSparseClone returns true if the repository is cloned partially.
+// Such repositories are cloned once, and the same checkout is both scanned and fixed.
+func (ci *ClientInfo) IsShallowOrSparseClone() bool {
+	return ci.CloneDepth > 0 || ci.SparseCheckout
 }
 
 type Git struct {
@@ -191,7 +283,26 @@ type Git struct {
 	CommitMessageTemplate    string `yaml:"commitMessageTemplate,omitempty"`
 	PullRequestTitleTemplate string `yaml:"pullRequestTitleTemplate,omitempty"`
 	EmailAuthor              string `yaml:"emailAuthor,omitempty"`
+	AuthorName               string `yaml:"authorName,omitempty"`
+	CommitterName            string `yaml:"committerName,omitempty"`
+	CommitterEmail           string `yaml:"committerEmail,omitempty"`
 	AggregateFixes           bool   `yaml:"aggregateFixes,omitempty"`
+	// The strategy of selecting the fix versions: minimal, sameMajor, sameMinor or latest
+	FixVersionStrategy string `yaml:"fixVersionStrategy,omitempty"`
+	// The maximum number of open Frogbot pull requests in the repository. If 0, the number isn't limited.
+	MaxOpenPullRequests int `yaml:"maxOpenPullRequests,omitempty"`
+	// The labels, reviewers and assignees of the fix pull requests
+	PullRequestLabels    []string `yaml:"pullRequestLabels,omitempty"`
+	PullRequestReviewers []string `yaml:"pullRequestReviewers,omitempty"`
+	PullRequestAssignees []string `yaml:"pullRequestAssignees,omitempty"`
+	// Request reviews from the code owners of the files that the fix pull requests change
+	CodeOwnersReviewers bool `yaml:"codeOwnersReviewers,omitempty"`
+	// The policy of the fix pull requests that are merged automatically
+	AutoMerge AutoMerge `yaml:"autoMerge,omitempty"`
+	// Rescan the projects after fixing them, to verify that the fixes resolve the vulnerabilities without introducing new ones
+	VerifyFixes bool `yaml:"verifyFixes,omitempty"`
+	// The action taken on fixes that fail the verification: draft or skip
+	FailedVerificationAction string `yaml:"failedVerificationAction,omitempty"`
 	PullRequestID            int
 }
 
@@ -199,6 +310,16 @@ func (g *Git) setDefaultsIfNeeded(git *Git) (err error) {
 	g.RepoOwner = git.RepoOwner
 	g.GitProvider = git.GitProvider
 	g.VcsInfo = git.VcsInfo
+	g.MaxRetries = git.MaxRetries
+	g.SshAuth = git.SshAuth
+	g.CommitSigningKey = git.CommitSigningKey
+	g.GitBackend = git.GitBackend
+	g.CloneFilter = git.CloneFilter
+	g.CloneDepth = git.CloneDepth
+	g.SparseCheckout = git.SparseCheckout
+	g.HttpTransport = git.HttpTransport
+	g.httpTransport = git.httpTransport
+	g.githubApp = git.githubApp
 	if g.RepoName == "" {
 		if git.RepoName == "" {
 			return fmt.Errorf("repository name is missing. please set the repository name in your %s file or as the %s environment variable", FrogbotConfigFile, GitRepoEnv)
@@ -221,6 +342,47 @@ func (g *Git) setDefaultsIfNeeded(git *Git) (err error) {
 	if g.PullRequestTitleTemplate == "" {
 		g.PullRequestTitleTemplate = getTrimmedEnv(PullRequestTitleTemplateEnv)
 	}
+	if g.MaxOpenPullRequests == 0 {
+		if g.MaxOpenPullRequests, err = getIntEnv(GitMaxOpenPullRequestsEnv, 0); err != nil {
+			return
+		}
+	}
+	if len(g.PullRequestLabels) == 0 {
+		g.PullRequestLabels = getListEnv(GitPullRequestLabelsEnv)
+	}
+	if len(g.PullRequestReviewers) == 0 {
+		g.PullRequestReviewers = getListEnv(GitPullRequestReviewersEnv)
+	}
+	if len(g.PullRequestAssignees) == 0 {
+		g.PullRequestAssignees = getListEnv(GitPullRequestAssigneesEnv)
+	}
+	if !g.CodeOwnersReviewers {
+		if g.CodeOwnersReviewers, err = getBoolEnv(GitCodeOwnersReviewersEnv, false); err != nil {
+			return
+		}
+	}
+	if err = g.AutoMerge.setDefaultsIfNeeded(); err != nil {
+		return
+	}
+	if !g.VerifyFixes {
+		if g.VerifyFixes, err = getBoolEnv(GitVerifyFixesEnv, false); err != nil {
+			return
+		}
+	}
+	if g.FailedVerificationAction == "" {
+		if g.FailedVerificationAction = strings.ToLower(getTrimmedEnv(GitFailedVerificationActionEnv)); g.FailedVerificationAction == "" {
+			g.FailedVerificationAction = DraftFailedFixes
+		}
+	}
+	if g.FailedVerificationAction != DraftFailedFixes && g.FailedVerificationAction != SkipFailedFixes {
+		return fmt.Errorf("the failed verification action '%s' is invalid. The following values are accepted: %s or %s", g.FailedVerificationAction, DraftFailedFixes, SkipFailedFixes)
+	}
+	if g.FixVersionStrategy == "" {
+		g.FixVersionStrategy = getTrimmedEnv(GitFixVersionStrategyEnv)
+	}
+	if g.FixVersionStrategy, err = getFixVersionStrategy(g.FixVersionStrategy); err != nil {
+		return
+	}
 	g.AggregateFixes = git.AggregateFixes
 	if !g.AggregateFixes {
 		if g.AggregateFixes, err = getBoolEnv(GitAggregateFixesEnv, false); err != nil {
@@ -232,6 +394,18 @@ func (g *Git) setDefaultsIfNeeded(git *Git) (err error) {
 			g.EmailAuthor = frogbotAuthorEmail
 		}
 	}
+	if g.AuthorName == "" {
+		if g.AuthorName = getTrimmedEnv(GitAuthorNameEnv); g.AuthorName == "" {
+			g.AuthorName = frogbotAuthorName
+		}
+	}
+	// If not set, the committer is the author
+	if g.CommitterName == "" {
+		g.CommitterName = getTrimmedEnv(GitCommitterNameEnv)
+	}
+	if g.CommitterEmail == "" {
+		g.CommitterEmail = getTrimmedEnv(GitCommitterEmailEnv)
+	}
 	// Non-mandatory git branch pr id.
 	if pullRequestIDString := getTrimmedEnv(GitPullRequestIDEnv); pullRequestIDString != "" {
 		if g.PullRequestID, err = strconv.Atoi(pullRequestIDString); err != nil {
@@ -259,17 +433,11 @@ func GetFrogbotUtils() (frogbotUtils *FrogbotUtils, err error) {
 	}()
 
 	// Build a version control client for REST API requests
-	client, err := vcsclient.
-		NewClientBuilder(gitParams.GitProvider).
-		ApiEndpoint(gitParams.APIEndpoint).
-		Token(gitParams.Token).
-		Project(gitParams.Project).
-		Logger(log.GetLogger()).
-		Username(gitParams.Username).
-		Build()
+	client, err := newVcsClient(gitParams)
 	if err != nil {
 		return nil, err
 	}
+	client = NewRetryingVcsClient(client, gitParams.MaxRetries)
 
 	configAggregator, err := getConfigAggregator(client, gitParams, server)
 	if err != nil {
@@ -280,27 +448,39 @@ func GetFrogbotUtils() (frogbotUtils *FrogbotUtils, err error) {
 
 // getConfigAggregator returns a RepoAggregator based on frogbot-config.yml and environment variables.
 func getConfigAggregator(client vcsclient.VcsClient, gitParams *Git, server *coreconfig.ServerDetails) (RepoAggregator, error) {
-	configFileContent, err := getConfigFileContent(client, &gitParams.ClientInfo)
+	configFileContent, repositoryRoot, err := getConfigFileContent(client, &gitParams.ClientInfo)
 	// Don't return error in case of a missing frogbot-config.yml file
 	// If an error occurs due to a missing file, attempt to generate an environment variable-based configuration aggregator as an alternative.
 	if _, missingConfigErr := err.(*ErrMissingConfig); !missingConfigErr && len(configFileContent) == 0 {
 		return nil, err
 	}
-	return BuildRepoAggregator(configFileContent, gitParams, server)
+	if len(configFileContent) == 0 {
+		return BuildRepoAggregator(configFileContent, gitParams, server)
+	}
+	// Merge the shared configurations the repositories extend, and validate the merged content
+	configFileContent, envOverrides, err := NewConfigExtendsResolver(client, &gitParams.ClientInfo).SetRepositoryRoot(repositoryRoot).ResolveConfigExtends(configFileContent)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range envOverrides {
+		log.Warn(issue.String())
+	}
+	return buildRepoAggregator(configFileContent, gitParams, server)
 }
 
 // The getConfigFileContent function retrieves the frogbot-config.yml file content.
 // If the JF_GIT_REPO and JF_GIT_OWNER environment variables are set, this function will attempt to retrieve the frogbot-config.yml file from the target repository based on these variables.
 // If these variables aren't set, this function will attempt to retrieve the frogbot-config.yml file from the current working directory.
-func getConfigFileContent(client vcsclient.VcsClient, clientInfo *ClientInfo) (configFileContent []byte, err error) {
+// The returned repository root is empty if the file was downloaded from the target repository.
+func getConfigFileContent(client vcsclient.VcsClient, clientInfo *ClientInfo) (configFileContent []byte, repositoryRoot string, err error) {
 	configFileContent, err = readConfigFromTarget(client, clientInfo)
 	_, missingConfigErr := err.(*ErrMissingConfig)
 	if err != nil && !missingConfigErr {
-		return nil, err
+		return nil, "", err
 	}
 	// Read the config from the current working dir
 	if len(configFileContent) == 0 {
-		configFileContent, err = ReadConfigFromFileSystem(osFrogbotConfigPath)
+		configFileContent, repositoryRoot, err = ReadConfigAndRootFromFileSystem(osFrogbotConfigPath)
 	}
 	return
 }
@@ -308,6 +488,16 @@ func getConfigFileContent(client vcsclient.VcsClient, clientInfo *ClientInfo) (c
 // BuildRepoAggregator receive a frogbot-config.yml file content along with the ClientInfo and ServerDetails parameters.
 // Returns a RepoAggregator instance with all the defaults and necessary fields.
 func BuildRepoAggregator(configFileContent []byte, gitParams *Git, server *coreconfig.ServerDetails) (resultAggregator RepoAggregator, err error) {
+	if len(configFileContent) > 0 {
+		if err = validateConfigFileContent(configFileContent); err != nil {
+			return
+		}
+	}
+	return buildRepoAggregator(configFileContent, gitParams, server)
+}
+
+// buildRepoAggregator is like BuildRepoAggregator, for content which has already been validated.
+func buildRepoAggregator(configFileContent []byte, gitParams *Git, server *coreconfig.ServerDetails) (resultAggregator RepoAggregator, err error) {
 	var cleanAggregator RepoAggregator
 	// Unmarshal the frogbot-config.yml file if exists
 	if cleanAggregator, err = unmarshalFrogbotConfigYaml(configFileContent); err != nil {
@@ -325,13 +515,36 @@ func BuildRepoAggregator(configFileContent []byte, gitParams *Git, server *corec
 	return
 }
 
+// validateConfigFileContent fails on frogbot-config.yml content that doesn't match the schema, such as misspelled keys,
+// and warns about environment variables that are ignored because the config file sets the same parameters.
+func validateConfigFileContent(configFileContent []byte) error {
+	if err := ValidateConfigSchema(configFileContent); err != nil {
+		return err
+	}
+	envOverrides, err := CheckEnvOverrides(configFileContent)
+	if err != nil {
+		return err
+	}
+	for _, issue := range envOverrides {
+		log.Warn(issue.String())
+	}
+	return nil
+}
+
 // unmarshalFrogbotConfigYaml uses the yaml.Unmarshaler interface to parse the yamlContent.
 // If there is no config file, the function returns a RepoAggregator with an empty repository.
 func unmarshalFrogbotConfigYaml(yamlContent []byte) (result RepoAggregator, err error) {
 	if len(yamlContent) == 0 {
 		return RepoAggregator{{Params: Params{Scan: Scan{Projects: []Project{{}}}}}}, nil
 	}
-	err = yaml.Unmarshal(yamlContent, &result)
+	var document yaml.Node
+	if err = yaml.Unmarshal(yamlContent, &document); err != nil {
+		return
+	}
+	if err = interpolateConfig(&document); err != nil {
+		return
+	}
+	err = document.Decode(&result)
 	return
 }
 
@@ -398,14 +611,42 @@ func extractClientInfo() (*ClientInfo, error) {
 	if err = readParamFromEnv(GitRepoOwnerEnv, &clientInfo.RepoOwner); err != nil {
 		return nil, err
 	}
-	// Set the access token to the git provider
-	if err = readParamFromEnv(GitTokenEnv, &clientInfo.Token); err != nil {
+	// Set the backend of the git operations
+	if clientInfo.GitBackend, clientInfo.CloneFilter, err = readGitBackendFromEnv(); err != nil {
+		return nil, err
+	}
+	// Set the shallow and sparse clone settings, for large repositories
+	if err = readCloneSettingsFromEnv(clientInfo); err != nil {
+		return nil, err
+	}
+	// Set the key that signs the commits, for repositories that require signed commits
+	if clientInfo.CommitSigningKey, err = readCommitSigningKeyFromEnv(); err != nil {
+		return nil, err
+	}
+	// Set the proxy, CA certificates and client certificate of the connections to the git provider.
+	// They must be set before the GitHub App tokens are minted, as these requests are sent to the git provider too.
+	if clientInfo.HttpTransport, err = readHttpTransportSettingsFromEnv(); err != nil {
+		return nil, err
+	}
+	if err = configureHttpTransport(clientInfo); err != nil {
+		return nil, err
+	}
+	// Set the access token to the git provider.
+	// GitHub Apps authenticate with short-lived installation tokens, which are minted instead.
+	if clientInfo.githubApp, err = readGithubAppFromEnv(clientInfo); err != nil {
+		return nil, err
+	}
+	if clientInfo.githubApp != nil {
+		if clientInfo.Token, err = clientInfo.githubApp.Token(); err != nil {
+			return nil, err
+		}
+	} else if err = readParamFromEnv(GitTokenEnv, &clientInfo.Token); err != nil {
 		return nil, err
 	}
 
-	// Set Bitbucket Server username
-	// Mandatory only for Bitbucket Server, this authentication detail is required for performing git operations.
-	if err = readParamFromEnv(GitUsernameEnv, &clientInfo.Username); err != nil && !e.IsMissingEnvErr(err) {
+	// Set Bitbucket username
+	// Mandatory for Bitbucket Cloud, which authenticates with app passwords, and for git operations on Bitbucket Server.
+	if err = readParamFromEnv(GitUsernameEnv, &clientInfo.Username); err != nil && (!e.IsMissingEnvErr(err) || clientInfo.GitProvider == vcsutils.BitbucketCloud) {
 		return nil, err
 	}
 	// Set Azure Repos Project name
@@ -413,6 +654,14 @@ func extractClientInfo() (*ClientInfo, error) {
 	if err = readParamFromEnv(GitProjectEnv, &clientInfo.Project); err != nil && clientInfo.GitProvider == vcsutils.AzureRepos {
 		return nil, err
 	}
+	// Set the number of retries for transient VCS and git errors
+	if clientInfo.MaxRetries, err = getIntEnv(GitMaxRetriesEnv, defaultMaxRetries); err != nil {
+		return nil, err
+	}
+	// Set the SSH credentials, for git servers that don't allow git operations over HTTPS
+	if clientInfo.SshAuth, err = readSshAuthFromEnv(); err != nil {
+		return nil, err
+	}
 
 	return clientInfo, nil
 }
@@ -454,11 +703,20 @@ func extractVcsProviderFromEnv() (vcsutils.VcsProvider, error) {
 	// For backward compatibility, we are accepting also "bitbucket server"
 	case string(BitbucketServer), "bitbucket server":
 		return vcsutils.BitbucketServer, nil
+	case string(BitbucketCloud):
+		return vcsutils.BitbucketCloud, nil
 	case string(AzureRepos):
 		return vcsutils.AzureRepos, nil
 	}
+	if provider, exists := findVcsProviderPlugin(vcsProvider); exists {
+		return provider, nil
+	}
 
-	return 0, fmt.Errorf("%s should be one of: '%s', '%s' or '%s'", GitProvider, GitHub, GitLab, BitbucketServer)
+	supportedProviders := []string{string(GitHub), string(GitLab), string(BitbucketServer), string(BitbucketCloud), string(AzureRepos)}
+	for _, plugin := range vcsProviderPlugins {
+		supportedProviders = append(supportedProviders, plugin.Name)
+	}
+	return 0, fmt.Errorf("%s should be one of: '%s' or '%s'", GitProvider, strings.Join(supportedProviders[:len(supportedProviders)-1], "', '"), supportedProviders[len(supportedProviders)-1])
 }
 
 func SanitizeEnv() error {
@@ -490,10 +748,16 @@ func extractClientServerParams() (*coreconfig.ServerDetails, *Git, error) {
 // ReadConfigFromFileSystem looks for .frogbot/frogbot-config.yml from the given path and return its content. The path is relative and starts from the root of the project.
 // If the config file is not found in the relative path, it will search in parent dirs.
 func ReadConfigFromFileSystem(configRelativePath string) (configFileContent []byte, err error) {
+	configFileContent, _, err = ReadConfigAndRootFromFileSystem(configRelativePath)
+	return
+}
+
+// ReadConfigAndRootFromFileSystem is like ReadConfigFromFileSystem, and also returns the root of the repository the config file was found in.
+func ReadConfigAndRootFromFileSystem(configRelativePath string) (configFileContent []byte, repositoryRoot string, err error) {
 	log.Debug("Reading config from file system. Looking for", osFrogbotConfigPath)
 	fullConfigDirPath, err := filepath.Abs(configRelativePath)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Look for the frogbot-config.yml file in fullConfigPath
@@ -502,7 +766,7 @@ func ReadConfigFromFileSystem(configRelativePath string) (configFileContent []by
 		// Look for the frogbot-config.yml in fullConfigPath parents dirs
 		log.Debug(FrogbotConfigFile, "wasn't found in "+fullConfigDirPath+". Searching for it in upstream directories")
 		if fullConfigDirPath, err = utils.FindFileInDirAndParents(fullConfigDirPath, configRelativePath); err != nil {
-			return nil, &ErrMissingConfig{errFrogbotConfigNotFound.Error()}
+			return nil, "", &ErrMissingConfig{errFrogbotConfigNotFound.Error()}
 		}
 		fullConfigDirPath = filepath.Join(fullConfigDirPath, configRelativePath)
 	}
@@ -512,9 +776,23 @@ func ReadConfigFromFileSystem(configRelativePath string) (configFileContent []by
 	if err != nil {
 		err = fmt.Errorf("an error occurd while reading the %s file at: %s\n%s", FrogbotConfigFile, configRelativePath, err.Error())
 	}
+	repositoryRoot = GetConfigRepositoryRoot(fullConfigDirPath)
 	return
 }
 
+// GetConfigRepositoryRoot returns the root of the repository of the config file, which is the parent of the .frogbot directory.
+// If the config file isn't in a .frogbot directory, its directory is returned.
+func GetConfigRepositoryRoot(configPath string) string {
+	configDir := filepath.Dir(configPath)
+	if absoluteConfigDir, err := filepath.Abs(configDir); err == nil {
+		configDir = absoluteConfigDir
+	}
+	if filepath.Base(configDir) == FrogbotConfigDir {
+		return filepath.Dir(configDir)
+	}
+	return configDir
+}
+
 func setProjectInstallCommand(installCommand string, project *Project) {
 	parts := strings.Fields(installCommand)
 	if len(parts) > 1 {
@@ -536,6 +814,29 @@ func getBoolEnv(envKey string, defaultValue bool) (bool, error) {
 	return defaultValue, nil
 }
 
+// getListEnv returns the comma-separated values of the environment variable, without spaces and empty values.
+func getListEnv(envKey string) (values []string) {
+	for _, value := range strings.Split(getTrimmedEnv(envKey), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return
+}
+
+func getIntEnv(envKey string, defaultValue int) (int, error) {
+	envValue := getTrimmedEnv(envKey)
+	if envValue != "" {
+		parsedEnv, err := strconv.Atoi(envValue)
+		if err != nil || parsedEnv < 0 {
+			return 0, fmt.Errorf("the value of the %s environment is expected to be a non-negative number. The value received however is %s", envKey, envValue)
+		}
+		return parsedEnv, nil
+	}
+
+	return defaultValue, nil
+}
+
 // readConfigFromTarget reads the .frogbot/frogbot-config.yml from the target repository
 func readConfigFromTarget(client vcsclient.VcsClient, clientInfo *ClientInfo) (configContent []byte, err error) {
 	if clientInfo.RepoName != "" && clientInfo.RepoOwner != "" {
@@ -548,7 +849,7 @@ func readConfigFromTarget(client vcsclient.VcsClient, clientInfo *ClientInfo) (c
 			log.Debug("the", FrogbotConfigFile, "will be downloaded from the", branch, "branch")
 		}
 
-		gitFrogbotConfigPath := fmt.Sprintf("%s/%s", frogbotConfigDir, FrogbotConfigFile)
+		gitFrogbotConfigPath := fmt.Sprintf("%s/%s", FrogbotConfigDir, FrogbotConfigFile)
 		var statusCode int
 		configContent, statusCode, err = client.DownloadFileFromRepo(context.Background(), clientInfo.RepoOwner, clientInfo.RepoName, branch, gitFrogbotConfigPath)
 		if statusCode == http.StatusNotFound {
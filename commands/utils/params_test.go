@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/stretchr/testify/assert"
@@ -101,6 +102,11 @@ func TestExtractVcsProviderFromEnv(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, vcsutils.BitbucketServer, vcsProvider)
 
+	SetEnvAndAssert(t, map[string]string{GitProvider: string(BitbucketCloud)})
+	vcsProvider, err = extractVcsProviderFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, vcsutils.BitbucketCloud, vcsProvider)
+
 	SetEnvAndAssert(t, map[string]string{GitProvider: string(AzureRepos)})
 	vcsProvider, err = extractVcsProviderFromEnv()
 	assert.NoError(t, err)
@@ -113,7 +119,7 @@ func TestExtractClientInfo(t *testing.T) {
 	}()
 
 	_, err := extractClientInfo()
-	assert.EqualError(t, err, "JF_GIT_PROVIDER should be one of: 'github', 'gitlab' or 'bitbucketServer'")
+	assert.EqualError(t, err, "JF_GIT_PROVIDER should be one of: 'github', 'gitlab', 'bitbucketServer', 'bitbucketCloud', 'azureRepos' or 'gitea'")
 
 	SetEnvAndAssert(t, map[string]string{GitProvider: "github"})
 	_, err = extractClientInfo()
@@ -140,6 +146,15 @@ func TestExtractAndAssertRepoParams(t *testing.T) {
 		GitEmailAuthorEnv:    "myemail@jfrog.com",
 		MinSeverityEnv:       "high",
 		FixableOnlyEnv:       "true",
+
+		GitPullRequestLabelsEnv:    "security, severity:${SEVERITY}",
+		GitPullRequestReviewersEnv: "froggy,jfrog/security",
+		GitCodeOwnersReviewersEnv:  "true",
+
+		GitAutoMergeEnv:            "true",
+		GitAutoMergeUpdateTypesEnv: "patch, Minor",
+		GitFixVersionStrategyEnv:   "samemajor",
+		GitVerifyFixesEnv:          "true",
 	})
 	defer func() {
 		assert.NoError(t, SanitizeEnv())
@@ -166,6 +181,14 @@ func TestExtractAndAssertRepoParams(t *testing.T) {
 		assert.True(t, repo.FixableOnly)
 		assert.Equal(t, true, repo.AggregateFixes)
 		assert.Equal(t, "myemail@jfrog.com", repo.EmailAuthor)
+		assert.Equal(t, []string{"security", "severity:${SEVERITY}"}, repo.PullRequestLabels)
+		assert.Equal(t, []string{"froggy", "jfrog/security"}, repo.PullRequestReviewers)
+		assert.Empty(t, repo.PullRequestAssignees)
+		assert.True(t, repo.CodeOwnersReviewers)
+		assert.Equal(t, SameMajorFixVersion, repo.FixVersionStrategy)
+		assert.True(t, repo.VerifyFixes)
+		assert.Equal(t, DraftFailedFixes, repo.FailedVerificationAction)
+		assert.Equal(t, AutoMerge{Enabled: true, UpdateTypes: []string{PatchUpdate, MinorUpdate}, MergeMethod: MergeMethodMerge}, repo.AutoMerge)
 		assert.ElementsMatch(t, []string{"watch-2", "watch-1"}, repo.Watches)
 		for _, project := range repo.Projects {
 			testExtractAndAssertProjectParams(t, project)
@@ -210,6 +233,40 @@ func TestBuildRepoAggregatorWithEmptyScan(t *testing.T) {
 	assert.True(t, *project.UseWrapper)
 }
 
+func TestBuildRepoAggregatorWithProjectScanSettings(t *testing.T) {
+	configFileContent := `- params:
+    git:
+      repoName: frogbot
+      branches:
+        - master
+    scan:
+      projects:
+        - name: Payments
+          workingDirs:
+            - payments
+          minSeverity: critical
+          fixableOnly: true
+          jfrogProjectKey: pay
+          watches:
+            - pay-watch
+        - workingDirs:
+            - a
+            - b
+`
+	configAggregator, err := BuildRepoAggregator([]byte(configFileContent), &Git{}, &config.ServerDetails{})
+	assert.NoError(t, err)
+	projects := configAggregator[0].Projects
+	assert.Len(t, projects, 2)
+	assert.Equal(t, "Critical", projects[0].MinSeverity)
+	assert.True(t, *projects[0].FixableOnly)
+	assert.Equal(t, "pay", projects[0].JFrogProjectKey)
+	assert.Equal(t, []string{"pay-watch"}, projects[0].Watches)
+	assert.Equal(t, "Payments", projects[0].Label())
+	assert.Empty(t, projects[1].MinSeverity)
+	assert.Nil(t, projects[1].FixableOnly)
+	assert.Equal(t, "a, b", projects[1].Label())
+}
+
 func testExtractAndAssertProjectParams(t *testing.T, project Project) {
 	assert.Equal(t, "nuget", project.InstallCommandName)
 	assert.Equal(t, []string{"restore"}, project.InstallCommandArgs)
@@ -281,6 +338,27 @@ func TestExtractInstallationCommandFromEnv(t *testing.T) {
 	assert.Equal(t, []string{"b", "--flagName=flagValue"}, project.InstallCommandArgs)
 }
 
+func TestExtractVerifyCommandFromEnv(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+
+	project := &Project{}
+	assert.NoError(t, project.setDefaultsIfNeeded())
+	assert.Empty(t, project.VerifyCommand)
+	assert.Equal(t, 30*time.Minute, project.GetVerifyCommandTimeout())
+
+	SetEnvAndAssert(t, map[string]string{VerifyCommandEnv: "mvn -q verify", VerifyCommandTimeoutEnv: "90"})
+	project = &Project{}
+	assert.NoError(t, project.setDefaultsIfNeeded())
+	assert.Equal(t, "mvn -q verify", project.VerifyCommand)
+	assert.Equal(t, 90*time.Minute, project.GetVerifyCommandTimeout())
+
+	project = &Project{VerifyCommand: "npm test"}
+	assert.NoError(t, project.setDefaultsIfNeeded())
+	assert.Equal(t, "npm test", project.VerifyCommand)
+}
+
 func TestGenerateConfigAggregatorFromEnv(t *testing.T) {
 	SetEnvAndAssert(t, map[string]string{
 		JFrogUrlEnv:                  "",
@@ -0,0 +1,370 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const pipRequirementsFileName = "requirements.txt"
+
+// Directories that don't hold projects Frogbot should scan, such as installed dependencies, build outputs and test fixtures.
+// Hidden directories, such as .git and .idea, are always skipped.
+var DefaultExcludedDirs = []string{"node_modules", "vendor", "target", "build", "dist", "__pycache__", "venv", "testdata", "fixtures"}
+
+// DetectedProjectDir is a directory that includes package descriptors of one or more technologies.
+type DetectedProjectDir struct {
+	// Relative path from the repository root, or RootDir for the root itself
+	WorkingDir   string
+	Technologies []coreutils.Technology
+}
+
+// DetectProjectDirs walks baseDir and returns the directories which include package descriptors, using the technology detection of the Xray audit.
+// A subdirectory that a project includes, such as a module of a Maven multi-module project or a package of npm workspaces,
+// is scanned as part of the project, so it isn't returned as a separate project. Other subdirectories are returned as separate projects.
+func DetectProjectDirs(baseDir string, excludedDirs []string) (detectedDirs []DetectedProjectDir, err error) {
+	// Technology -> the directories already detected as its projects, including the projects that other projects include
+	projectDirs := map[coreutils.Technology][]string{}
+	err = filepath.WalkDir(baseDir, func(currentPath string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if currentPath != baseDir && (strings.HasPrefix(entry.Name(), ".") || slices.Contains(excludedDirs, entry.Name())) {
+			return filepath.SkipDir
+		}
+		detected, e := coreutils.DetectTechnologies(currentPath, false, false)
+		if e != nil {
+			return e
+		}
+		relativePath, e := filepath.Rel(baseDir, currentPath)
+		if e != nil {
+			return e
+		}
+		var technologies []coreutils.Technology
+		for tech := range detected {
+			included := isIncludedByAnyProject(baseDir, relativePath, tech, projectDirs)
+			// An included project may include the projects of its own subdirectories, such as the modules of a Maven module
+			projectDirs[tech] = append(projectDirs[tech], relativePath)
+			if !included {
+				technologies = append(technologies, tech)
+			}
+		}
+		if len(technologies) > 0 {
+			sort.Slice(technologies, func(i, j int) bool { return technologies[i] < technologies[j] })
+			detectedDirs = append(detectedDirs, DetectedProjectDir{WorkingDir: filepath.ToSlash(relativePath), Technologies: technologies})
+		}
+		return nil
+	})
+	return
+}
+
+// isIncludedByAnyProject returns true if a project of the technology, in one of the parent directories of dir, includes the project in dir.
+func isIncludedByAnyProject(baseDir, dir string, tech coreutils.Technology, projectDirs map[coreutils.Technology][]string) bool {
+	parentTechnologies := []coreutils.Technology{tech}
+	// The packages of yarn workspaces have no yarn files of their own, so they're detected as npm projects
+	if tech == coreutils.Npm || tech == coreutils.Yarn {
+		parentTechnologies = []coreutils.Technology{coreutils.Npm, coreutils.Yarn}
+	}
+	for _, parentTech := range parentTechnologies {
+		for _, parentDir := range projectDirs[parentTech] {
+			if parentDir == dir || (parentDir != RootDir && !strings.HasPrefix(dir, parentDir+string(os.PathSeparator))) {
+				continue
+			}
+			relativeDir, err := filepath.Rel(parentDir, dir)
+			if err != nil {
+				continue
+			}
+			included, err := includesProject(filepath.Join(baseDir, parentDir), filepath.ToSlash(relativeDir), parentTech)
+			if err != nil {
+				log.Debug(fmt.Sprintf("Couldn't check whether the %s project in '%s' includes '%s': %s", parentTech, parentDir, dir, err.Error()))
+				continue
+			}
+			if included {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// includesProject returns true if the project of the technology in projectDir includes the project in its subdirectory, relative to projectDir:
+// a module of a Maven project, a project of a Gradle multi-project build, a package of npm or yarn workspaces, or a project of a .NET solution.
+func includesProject(projectDir, subDir string, tech coreutils.Technology) (bool, error) {
+	switch tech {
+	case coreutils.Maven:
+		return mavenIncludesModule(projectDir, subDir)
+	case coreutils.Gradle:
+		return gradleIncludesProject(projectDir, subDir)
+	case coreutils.Npm, coreutils.Yarn:
+		return npmIncludesWorkspace(projectDir, subDir)
+	case coreutils.Dotnet, coreutils.Nuget:
+		return solutionIncludesProject(projectDir, subDir)
+	}
+	// Projects of other technologies, such as Go modules, are independent of the projects of their parent directories
+	return false, nil
+}
+
+func mavenIncludesModule(projectDir, subDir string) (bool, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, "pom.xml"))
+	if err != nil {
+		return false, err
+	}
+	var pom struct {
+		Modules []string `xml:"modules>module"`
+	}
+	if err = xml.Unmarshal(content, &pom); err != nil {
+		return false, err
+	}
+	for _, module := range pom.Modules {
+		if path.Clean(filepath.ToSlash(strings.TrimSpace(module))) == subDir {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// gradleIncludesProject returns true if projectDir is the root of a multi-project build, which has a settings file, and subDir isn't the root of another build.
+func gradleIncludesProject(projectDir, subDir string) (bool, error) {
+	hasSettings := func(dir string) bool {
+		for _, settingsFile := range []string{"settings.gradle", "settings.gradle.kts"} {
+			if _, err := os.Stat(filepath.Join(dir, settingsFile)); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	return hasSettings(projectDir) && !hasSettings(filepath.Join(projectDir, subDir)), nil
+}
+
+func npmIncludesWorkspace(projectDir, subDir string) (bool, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, "package.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	// The workspaces are either a list of patterns, or an object with a list of patterns in its packages field
+	var packageJson struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err = json.Unmarshal(content, &packageJson); err != nil || len(packageJson.Workspaces) == 0 {
+		return false, err
+	}
+	var workspaces []string
+	if err = json.Unmarshal(packageJson.Workspaces, &workspaces); err != nil {
+		var workspacesConfig struct {
+			Packages []string `json:"packages"`
+		}
+		if err = json.Unmarshal(packageJson.Workspaces, &workspacesConfig); err != nil {
+			return false, err
+		}
+		workspaces = workspacesConfig.Packages
+	}
+	for _, workspace := range workspaces {
+		if matchPathSegments(strings.Split(strings.Trim(path.Clean(workspace), "/"), "/"), strings.Split(subDir, "/")) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+var solutionProjectRegex = regexp.MustCompile(`(?m)^Project\("[^"]*"\)\s*=\s*"[^"]*",\s*"([^"]+)"`)
+
+func solutionIncludesProject(projectDir, subDir string) (bool, error) {
+	solutionFiles, err := filepath.Glob(filepath.Join(projectDir, "*.sln"))
+	if err != nil {
+		return false, err
+	}
+	for _, solutionFile := range solutionFiles {
+		content, err := os.ReadFile(solutionFile)
+		if err != nil {
+			return false, err
+		}
+		for _, match := range solutionProjectRegex.FindAllStringSubmatch(string(content), -1) {
+			// The project paths of solutions are separated by backslashes
+			if path.Dir(path.Clean(strings.ReplaceAll(match[1], "\\", "/"))) == subDir {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// ProjectsFromDetectedDirs groups the detected directories into projects, so that directories sharing the same install
+// settings are listed as working directories of a single project.
+func ProjectsFromDetectedDirs(baseDir string, detectedDirs []DetectedProjectDir) (projects []Project) {
+	projectIndexes := map[string]int{}
+	for _, detectedDir := range detectedDirs {
+		project := getProjectInstallSettings(baseDir, detectedDir)
+		key := project.InstallCommand + "|" + project.PipRequirementsFile
+		if index, exists := projectIndexes[key]; exists {
+			projects[index].WorkingDirs = append(projects[index].WorkingDirs, detectedDir.WorkingDir)
+			continue
+		}
+		project.WorkingDirs = []string{detectedDir.WorkingDir}
+		projectIndexes[key] = len(projects)
+		projects = append(projects, project)
+	}
+	return
+}
+
+// getProjectInstallSettings returns a project with the install command and requirements file needed by the technologies in the directory.
+// Most technologies are installed by the audit itself, and only yarn 2, NuGet and .NET require an install command.
+func getProjectInstallSettings(baseDir string, detectedDir DetectedProjectDir) (project Project) {
+	switch {
+	// .csproj and .sln files are indicators of both .NET and NuGet, prefer the .NET CLI
+	case slices.Contains(detectedDir.Technologies, coreutils.Dotnet):
+		project.InstallCommand = "dotnet restore"
+	case slices.Contains(detectedDir.Technologies, coreutils.Nuget):
+		project.InstallCommand = "nuget restore"
+	case slices.Contains(detectedDir.Technologies, coreutils.Yarn):
+		project.InstallCommand = "yarn install"
+	case slices.Contains(detectedDir.Technologies, coreutils.Pip):
+		if _, err := os.Stat(filepath.Join(baseDir, detectedDir.WorkingDir, pipRequirementsFileName)); err == nil {
+			project.PipRequirementsFile = pipRequirementsFileName
+		}
+	}
+	return
+}
+
+// ResolveWorkingDirs returns the working directories of the project, relative to baseDir.
+// Working directories may be glob patterns, in which "**" matches any number of directories.
+// A pattern that matches directories, such as "services/*", resolves to these directories, and a pattern that only matches files,
+// such as "**/package.json", resolves to the directories which include them.
+// If the project is auto-discovered, each project found under the working directories is returned instead.
+func ResolveWorkingDirs(project *Project, baseDir string) (workingDirs []string, err error) {
+	for _, workingDir := range project.WorkingDirs {
+		if !isGlobPattern(workingDir) {
+			workingDirs = appendIfMissing(workingDirs, workingDir)
+			continue
+		}
+		var matches []string
+		if matches, err = matchWorkingDirPattern(baseDir, workingDir); err != nil {
+			return
+		}
+		if len(matches) == 0 {
+			log.Warn(fmt.Sprintf("The working directory pattern '%s' doesn't match any path in the repository", workingDir))
+		}
+		for _, match := range matches {
+			workingDirs = appendIfMissing(workingDirs, match)
+		}
+	}
+	if !project.AutoDiscover {
+		return
+	}
+	return discoverProjects(baseDir, workingDirs)
+}
+
+func discoverProjects(baseDir string, workingDirs []string) (discoveredDirs []string, err error) {
+	for _, workingDir := range workingDirs {
+		var detectedDirs []DetectedProjectDir
+		if detectedDirs, err = DetectProjectDirs(filepath.Join(baseDir, workingDir), DefaultExcludedDirs); err != nil {
+			return
+		}
+		for _, detectedDir := range detectedDirs {
+			discoveredDirs = appendIfMissing(discoveredDirs, path.Join(filepath.ToSlash(workingDir), detectedDir.WorkingDir))
+		}
+	}
+	if len(discoveredDirs) == 0 {
+		return nil, fmt.Errorf("no projects were discovered in the working directories: %s", strings.Join(workingDirs, ", "))
+	}
+	log.Info("Discovered projects in the following directories:", strings.Join(discoveredDirs, ", "))
+	return
+}
+
+func isGlobPattern(workingDir string) bool {
+	return strings.ContainsAny(workingDir, "*?[")
+}
+
+// matchWorkingDirPattern walks baseDir and returns the directories matching the pattern, or the parent directories of the matching files.
+// Hidden directories and DefaultExcludedDirs are skipped.
+func matchWorkingDirPattern(baseDir, pattern string) (matches []string, err error) {
+	patternSegments := strings.Split(strings.Trim(filepath.ToSlash(pattern), "/"), "/")
+	var matchedDirs, matchedFilesDirs []string
+	err = filepath.WalkDir(baseDir, func(currentPath string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if currentPath == baseDir {
+			return nil
+		}
+		if entry.IsDir() && (strings.HasPrefix(entry.Name(), ".") || slices.Contains(DefaultExcludedDirs, entry.Name())) {
+			return filepath.SkipDir
+		}
+		relativePath, e := filepath.Rel(baseDir, currentPath)
+		if e != nil {
+			return e
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if !matchPathSegments(patternSegments, strings.Split(relativePath, "/")) {
+			return nil
+		}
+		if entry.IsDir() {
+			matchedDirs = append(matchedDirs, relativePath)
+		} else {
+			matchedFilesDirs = appendIfMissing(matchedFilesDirs, path.Dir(relativePath))
+		}
+		return nil
+	})
+	if len(matchedDirs) > 0 {
+		return matchedDirs, err
+	}
+	return matchedFilesDirs, err
+}
+
+// matchPathSegments matches the path segments against the pattern segments, where "**" matches zero or more segments.
+func matchPathSegments(patternSegments, pathSegments []string) bool {
+	if len(patternSegments) == 0 {
+		return len(pathSegments) == 0
+	}
+	if patternSegments[0] == "**" {
+		for i := 0; i <= len(pathSegments); i++ {
+			if matchPathSegments(patternSegments[1:], pathSegments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(pathSegments) == 0 {
+		return false
+	}
+	if matched, err := path.Match(patternSegments[0], pathSegments[0]); err != nil || !matched {
+		return false
+	}
+	return matchPathSegments(patternSegments[1:], pathSegments[1:])
+}
+
+func appendIfMissing(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+// GetDiscoveredInstallCommand returns the install command needed by the project in dir,
+// or an empty string if the dependencies are installed by the audit itself.
+func GetDiscoveredInstallCommand(dir string) (string, error) {
+	detected, err := coreutils.DetectTechnologies(dir, false, false)
+	if err != nil {
+		return "", err
+	}
+	var technologies []coreutils.Technology
+	for tech := range detected {
+		technologies = append(technologies, tech)
+	}
+	return getProjectInstallSettings(dir, DetectedProjectDir{WorkingDir: RootDir, Technologies: technologies}).InstallCommand, nil
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createFilesInDir(t *testing.T, baseDir string, files ...string) {
+	for _, file := range files {
+		fullPath := filepath.Join(baseDir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		assert.NoError(t, os.WriteFile(fullPath, []byte{}, 0644))
+	}
+}
+
+func writeFileInDir(t *testing.T, baseDir, file, content string) {
+	fullPath := filepath.Join(baseDir, file)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+	assert.NoError(t, os.WriteFile(fullPath, []byte(content), 0644))
+}
+
+func TestDetectProjectDirs(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFileInDir(t, baseDir, "pom.xml", "<project><modules><module>module-a</module></modules></project>")
+	createFilesInDir(t, baseDir,
+		"module-a/pom.xml",
+		"samples/pom.xml",
+		"frontend/package.json",
+		"frontend/node_modules/lodash/package.json",
+		"services/api/go.mod",
+		"services/api/testdata/npm/package.json",
+		"tools/requirements.txt",
+		"yarn-app/package.json",
+		"yarn-app/yarn.lock",
+		"dotnet-app/app.csproj",
+		".idea/package.json",
+	)
+	detectedDirs, err := DetectProjectDirs(baseDir, DefaultExcludedDirs)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []DetectedProjectDir{
+		{WorkingDir: RootDir, Technologies: []coreutils.Technology{coreutils.Maven}},
+		// The root project doesn't include the samples module
+		{WorkingDir: "samples", Technologies: []coreutils.Technology{coreutils.Maven}},
+		{WorkingDir: "frontend", Technologies: []coreutils.Technology{coreutils.Npm}},
+		{WorkingDir: "services/api", Technologies: []coreutils.Technology{coreutils.Go}},
+		{WorkingDir: "tools", Technologies: []coreutils.Technology{coreutils.Pip}},
+		{WorkingDir: "yarn-app", Technologies: []coreutils.Technology{coreutils.Yarn}},
+		{WorkingDir: "dotnet-app", Technologies: []coreutils.Technology{coreutils.Dotnet, coreutils.Nuget}},
+	}, detectedDirs)
+
+	projects := ProjectsFromDetectedDirs(baseDir, detectedDirs)
+	assert.ElementsMatch(t, []Project{
+		{WorkingDirs: []string{RootDir, "frontend", "samples", "services/api"}},
+		{WorkingDirs: []string{"tools"}, PipRequirementsFile: pipRequirementsFileName},
+		{WorkingDirs: []string{"yarn-app"}, InstallCommand: "yarn install"},
+		{WorkingDirs: []string{"dotnet-app"}, InstallCommand: "dotnet restore"},
+	}, projects)
+}
+
+func TestDetectProjectDirsIncludedProjects(t *testing.T) {
+	baseDir := t.TempDir()
+	// npm workspaces, and an npm project that the workspaces don't include
+	writeFileInDir(t, baseDir, "web/package.json", `{"workspaces": ["packages/*"]}`)
+	writeFileInDir(t, baseDir, "web/yarn.lock", "")
+	createFilesInDir(t, baseDir, "web/packages/ui/package.json", "web/e2e/package.json")
+	// A Gradle multi-project build, and a separate build nested in it
+	createFilesInDir(t, baseDir, "jvm/settings.gradle", "jvm/build.gradle", "jvm/core/build.gradle", "jvm/buildSrc/settings.gradle", "jvm/buildSrc/build.gradle")
+	// A .NET solution
+	writeFileInDir(t, baseDir, "dotnet/app.sln", "Project(\"{FAE04EC0}\") = \"Api\", \"src\\Api\\Api.csproj\", \"{1}\"\r\nEndProject\r\n")
+	createFilesInDir(t, baseDir, "dotnet/src/Api/Api.csproj", "dotnet/tools/Tool.csproj")
+
+	detectedDirs, err := DetectProjectDirs(baseDir, DefaultExcludedDirs)
+	assert.NoError(t, err)
+	var workingDirs []string
+	for _, detectedDir := range detectedDirs {
+		workingDirs = append(workingDirs, detectedDir.WorkingDir)
+	}
+	assert.ElementsMatch(t, []string{"web", "web/e2e", "jvm", "jvm/buildSrc", "dotnet", "dotnet/tools"}, workingDirs)
+}
+
+func TestResolveWorkingDirs(t *testing.T) {
+	baseDir := t.TempDir()
+	createFilesInDir(t, baseDir,
+		"services/users/package.json",
+		"services/orders/go.mod",
+		"services/README.md",
+		"libs/common/package.json",
+		"libs/common/node_modules/lodash/package.json",
+		"libs/legacy/sub/app.csproj",
+		"docs/index.md",
+	)
+	tests := []struct {
+		name        string
+		project     Project
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			name:     "Plain directories",
+			project:  Project{WorkingDirs: []string{RootDir, "services/users"}},
+			expected: []string{RootDir, "services/users"},
+		},
+		{
+			name:     "Directories pattern",
+			project:  Project{WorkingDirs: []string{"services/*"}},
+			expected: []string{"services/orders", "services/users"},
+		},
+		{
+			name:     "Files pattern",
+			project:  Project{WorkingDirs: []string{"**/package.json"}},
+			expected: []string{"libs/common", "services/users"},
+		},
+		{
+			name:     "Pattern without matches",
+			project:  Project{WorkingDirs: []string{"apps/*", "libs/common"}},
+			expected: []string{"libs/common"},
+		},
+		{
+			name:     "Auto discover",
+			project:  Project{WorkingDirs: []string{RootDir}, AutoDiscover: true},
+			expected: []string{"libs/common", "libs/legacy/sub", "services/orders", "services/users"},
+		},
+		{
+			name:     "Auto discover in pattern",
+			project:  Project{WorkingDirs: []string{"libs/*"}, AutoDiscover: true},
+			expected: []string{"libs/common", "libs/legacy/sub"},
+		},
+		{
+			name:        "Nothing discovered",
+			project:     Project{WorkingDirs: []string{"docs"}, AutoDiscover: true},
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workingDirs, err := ResolveWorkingDirs(&test.project, baseDir)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, workingDirs)
+		})
+	}
+}
+
+func TestMatchPathSegments(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{pattern: "services/*", path: "services/users", expected: true},
+		{pattern: "services/*", path: "services/users/api", expected: false},
+		{pattern: "**/package.json", path: "package.json", expected: true},
+		{pattern: "**/package.json", path: "a/b/c/package.json", expected: true},
+		{pattern: "a/**/c", path: "a/c", expected: true},
+		{pattern: "a/**/c", path: "a/b/d", expected: false},
+		{pattern: "**/*.csproj", path: "src/app.csproj", expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.path, func(t *testing.T) {
+			assert.Equal(t, test.expected, matchPathSegments(strings.Split(test.pattern, "/"), strings.Split(test.path, "/")))
+		})
+	}
+}
+
+func TestGetDiscoveredInstallCommand(t *testing.T) {
+	baseDir := t.TempDir()
+	createFilesInDir(t, baseDir, "yarn-app/package.json", "yarn-app/yarn.lock", "npm-app/package.json")
+	installCommand, err := GetDiscoveredInstallCommand(filepath.Join(baseDir, "yarn-app"))
+	assert.NoError(t, err)
+	assert.Equal(t, "yarn install", installCommand)
+	installCommand, err = GetDiscoveredInstallCommand(filepath.Join(baseDir, "npm-app"))
+	assert.NoError(t, err)
+	assert.Empty(t, installCommand)
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/jfrog/froggit-go/vcsclient"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The locations of the CODEOWNERS file, in the order GitHub and GitLab look for it
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// PullRequestAssignments are the labels, reviewers and assignees of a fix pull request.
+type PullRequestAssignments struct {
+	Labels []string
+	// Users, and teams as organization/team
+	Reviewers []string
+	Assignees []string
+}
+
+func (pa *PullRequestAssignments) IsEmpty() bool {
+	return len(pa.Labels) == 0 && len(pa.Reviewers) == 0 && len(pa.Assignees) == 0
+}
+
+// pullRequestAssigner is implemented by the clients of the Git providers which support setting the labels, reviewers and assignees of pull requests.
+type pullRequestAssigner interface {
+	AssignPullRequest(ctx context.Context, owner, repository string, pullRequestID int, assignments *PullRequestAssignments) error
+}
+
+// AssignPullRequest adds the labels, reviewers and assignees to the pull request.
+// If the Git provider doesn't support it, a warning is logged.
+func AssignPullRequest(ctx context.Context, client vcsclient.VcsClient, owner, repository string, pullRequestID int, assignments *PullRequestAssignments) error {
+	if assignments == nil || assignments.IsEmpty() {
+		return nil
+	}
+	assigner, ok := client.(pullRequestAssigner)
+	if !ok {
+		log.Warn("Setting the labels, reviewers and assignees of pull requests isn't supported by the Git provider")
+		return nil
+	}
+	log.Debug(fmt.Sprintf("Assigning pull request %d. Labels: %v, reviewers: %v, assignees: %v", pullRequestID, assignments.Labels, assignments.Reviewers, assignments.Assignees))
+	return assigner.AssignPullRequest(ctx, owner, repository, pullRequestID, assignments)
+}
+
+// GetPullRequestAssignments returns the assignments of a fix pull request.
+// severity is the highest severity of the fixed vulnerabilities, and changedFiles are the files the pull request changes, relative to the root of the repository.
+func (g *Git) GetPullRequestAssignments(severity string, codeOwners *CodeOwners, changedFiles []string) *PullRequestAssignments {
+	assignments := &PullRequestAssignments{Assignees: g.PullRequestAssignees}
+	for _, label := range g.PullRequestLabels {
+		assignments.Labels = append(assignments.Labels, strings.ReplaceAll(label, SeverityPlaceHolder, strings.ToLower(severity)))
+	}
+	assignments.Reviewers = append(assignments.Reviewers, g.PullRequestReviewers...)
+	if g.CodeOwnersReviewers && codeOwners != nil {
+		for _, owner := range codeOwners.GetOwners(changedFiles) {
+			if !slices.Contains(assignments.Reviewers, owner) {
+				assignments.Reviewers = append(assignments.Reviewers, owner)
+			}
+		}
+	}
+	return assignments
+}
+
+// GetHighestSeverity returns the highest severity of the vulnerabilities.
+func GetHighestSeverity(vulnerabilities ...*VulnerabilityDetails) (highestSeverity string) {
+	highestValue := 0
+	for _, vulnerability := range vulnerabilities {
+		// The applicability is fixed, so that only the severities are compared
+		severity := xrayutils.GetSeverity(vulnerability.Severity, xrayutils.NotApplicableStringValue)
+		if severity != nil && severity.NumValue() > highestValue {
+			highestSeverity, highestValue = vulnerability.Severity, severity.NumValue()
+		}
+	}
+	return
+}
+
+type codeOwnersRule struct {
+	pattern gitignore.Pattern
+	owners  []string
+}
+
+// CodeOwners holds the rules of a CODEOWNERS file.
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+// ReadCodeOwners reads the CODEOWNERS file of the repository. Returns nil if the repository has no CODEOWNERS file.
+func ReadCodeOwners(repositoryDir string) (*CodeOwners, error) {
+	for _, location := range codeOwnersLocations {
+		content, err := os.ReadFile(filepath.Join(repositoryDir, filepath.FromSlash(location)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		log.Debug("Reading the code owners from", location)
+		return parseCodeOwners(string(content)), nil
+	}
+	return nil, nil
+}
+
+func parseCodeOwners(content string) *CodeOwners {
+	codeOwners := &CodeOwners{}
+	for _, line := range strings.Split(content, "\n") {
+		if commentStart := strings.Index(line, "#"); commentStart >= 0 {
+			line = line[:commentStart]
+		}
+		fields := strings.Fields(line)
+		// Skip empty lines, and the section headers of GitLab
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		rule := codeOwnersRule{pattern: gitignore.ParsePattern(fields[0], nil)}
+		for _, owner := range fields[1:] {
+			if !strings.HasPrefix(owner, "@") {
+				// Owners set by their emails can't be requested to review
+				log.Debug("Skipping the code owner", owner)
+				continue
+			}
+			rule.owners = append(rule.owners, strings.TrimPrefix(owner, "@"))
+		}
+		codeOwners.rules = append(codeOwners.rules, rule)
+	}
+	return codeOwners
+}
+
+// GetOwners returns the owners of the files. The owners of a file are set by the last rule that matches it.
+func (co *CodeOwners) GetOwners(files []string) (owners []string) {
+	for _, file := range files {
+		path := strings.Split(filepath.ToSlash(file), "/")
+		for i := len(co.rules) - 1; i >= 0; i-- {
+			if co.rules[i].pattern.Match(path, false) != gitignore.Exclude {
+				continue
+			}
+			for _, owner := range co.rules[i].owners {
+				if !slices.Contains(owners, owner) {
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const codeOwnersTestContent = `# Default owners
+*                   @jfrog/frogbot-maintainers
+
+[Frontend]
+/frontend/          @frontend-lead @jfrog/frontend docs@jfrog.com
+package-lock.json   @npm-owner # The lock files of all the projects
+/backend/go.mod     @go-owner
+`
+
+func TestCodeOwners(t *testing.T) {
+	codeOwners := parseCodeOwners(codeOwnersTestContent)
+	tests := []struct {
+		files          []string
+		expectedOwners []string
+	}{
+		{files: []string{"README.md"}, expectedOwners: []string{"jfrog/frogbot-maintainers"}},
+		{files: []string{"frontend/package.json"}, expectedOwners: []string{"frontend-lead", "jfrog/frontend"}},
+		// The last matching rule sets the owners
+		{files: []string{"frontend/package-lock.json"}, expectedOwners: []string{"npm-owner"}},
+		{files: []string{"backend/go.mod", "backend/go.sum"}, expectedOwners: []string{"go-owner", "jfrog/frogbot-maintainers"}},
+		{files: nil, expectedOwners: nil},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expectedOwners, codeOwners.GetOwners(test.files), test.files)
+	}
+}
+
+func TestReadCodeOwners(t *testing.T) {
+	repositoryDir := t.TempDir()
+	codeOwners, err := ReadCodeOwners(repositoryDir)
+	assert.NoError(t, err)
+	assert.Nil(t, codeOwners)
+
+	require.NoError(t, os.Mkdir(filepath.Join(repositoryDir, ".github"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(repositoryDir, ".github", "CODEOWNERS"), []byte(codeOwnersTestContent), 0600))
+	codeOwners, err = ReadCodeOwners(repositoryDir)
+	assert.NoError(t, err)
+	if assert.NotNil(t, codeOwners) {
+		assert.Equal(t, []string{"go-owner"}, codeOwners.GetOwners([]string{"backend/go.mod"}))
+	}
+}
+
+func TestGetPullRequestAssignments(t *testing.T) {
+	git := &Git{
+		PullRequestLabels:    []string{"security", "severity:" + SeverityPlaceHolder},
+		PullRequestReviewers: []string{"security-champion", "go-owner"},
+		PullRequestAssignees: []string{"security-champion"},
+	}
+	codeOwners := parseCodeOwners(codeOwnersTestContent)
+	assignments := git.GetPullRequestAssignments("Critical", codeOwners, []string{"backend/go.mod"})
+	assert.Equal(t, &PullRequestAssignments{Labels: []string{"security", "severity:critical"}, Reviewers: []string{"security-champion", "go-owner"}, Assignees: []string{"security-champion"}}, assignments)
+
+	// The code owners review the changed files, in addition to the configured reviewers
+	git.CodeOwnersReviewers = true
+	assignments = git.GetPullRequestAssignments("Low", codeOwners, []string{"backend/go.mod", "frontend/package.json"})
+	assert.Equal(t, []string{"security", "severity:low"}, assignments.Labels)
+	assert.Equal(t, []string{"security-champion", "go-owner", "frontend-lead", "jfrog/frontend"}, assignments.Reviewers)
+
+	assert.True(t, (&Git{CodeOwnersReviewers: true}).GetPullRequestAssignments("High", nil, []string{"go.mod"}).IsEmpty())
+}
+
+func TestGetHighestSeverity(t *testing.T) {
+	newDetails := func(severity string) *VulnerabilityDetails {
+		return NewVulnerabilityDetails(&formats.VulnerabilityOrViolationRow{Severity: severity}, "1.0.0")
+	}
+	assert.Equal(t, "High", GetHighestSeverity(newDetails("Low"), newDetails("High"), newDetails("Medium")))
+	assert.Equal(t, "Critical", GetHighestSeverity(newDetails("Critical"), newDetails("High")))
+	assert.Equal(t, "", GetHighestSeverity())
+}
+
+type recordedRequest struct {
+	method string
+	path   string
+	body   interface{}
+}
+
+// newAssignmentsStandIn records the requests that assign pull requests, and returns the response body of the GET requests by their path and query.
+func newAssignmentsStandIn(t *testing.T, getResponses map[string]string, requests *[]recordedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			response, exists := getResponses[r.URL.RequestURI()]
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, err := w.Write([]byte(response))
+			assert.NoError(t, err)
+			return
+		}
+		content, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		request := recordedRequest{method: r.Method, path: r.URL.Path}
+		assert.NoError(t, json.Unmarshal(content, &request.body))
+		*requests = append(*requests, request)
+		// Labels are returned as an array by GitHub
+		response := "{}"
+		if strings.HasSuffix(r.URL.Path, "/labels") {
+			response = "[]"
+		}
+		_, err = w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+}
+
+var testAssignments = &PullRequestAssignments{Labels: []string{"security"}, Reviewers: []string{"froggy", "jfrog/security"}, Assignees: []string{"froggy"}}
+
+func TestGithubAssignPullRequest(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, nil, &requests)
+	defer server.Close()
+	client, err := newGithubClient(nil, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "github-token"}, http.DefaultTransport)
+	require.NoError(t, err)
+
+	assert.NoError(t, AssignPullRequest(context.Background(), client, "jfrog", "frogbot", 3, testAssignments))
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPost, path: "/repos/jfrog/frogbot/issues/3/labels", body: []interface{}{"security"}},
+		{method: http.MethodPost, path: "/repos/jfrog/frogbot/pulls/3/requested_reviewers", body: map[string]interface{}{"reviewers": []interface{}{"froggy"}, "team_reviewers": []interface{}{"security"}}},
+		{method: http.MethodPost, path: "/repos/jfrog/frogbot/issues/3/assignees", body: map[string]interface{}{"assignees": []interface{}{"froggy"}}},
+	}, requests)
+}
+
+func TestGitlabAssignPullRequest(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, map[string]string{
+		"/api/v4/users?username=froggy":           `[{"id": 7}]`,
+		"/api/v4/users?username=jfrog%2Fsecurity": `[]`,
+	}, &requests)
+	defer server.Close()
+	client := newGitlabClient(nil, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "gitlab-token"}, http.DefaultTransport)
+
+	assert.NoError(t, AssignPullRequest(context.Background(), client, "jfrog", "frogbot", 3, testAssignments))
+	// Groups can't review merge requests, so they are skipped
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPut, path: "/api/v4/projects/jfrog/frogbot/merge_requests/3", body: map[string]interface{}{"add_labels": "security", "reviewer_ids": []interface{}{float64(7)}, "assignee_ids": []interface{}{float64(7)}}},
+	}, requests)
+}
+
+func TestGiteaAssignPullRequest(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, nil, &requests)
+	defer server.Close()
+	client, err := newGiteaClient(vcsclient.VcsInfo{APIEndpoint: server.URL, Token: giteaTestToken}, http.DefaultTransport)
+	require.NoError(t, err)
+
+	assert.NoError(t, AssignPullRequest(context.Background(), client, "jfrog", "frogbot", 3, testAssignments))
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPost, path: "/api/v1/repos/jfrog/frogbot/issues/3/labels", body: map[string]interface{}{"labels": []interface{}{"security"}}},
+		{method: http.MethodPost, path: "/api/v1/repos/jfrog/frogbot/pulls/3/requested_reviewers", body: map[string]interface{}{"reviewers": []interface{}{"froggy"}, "team_reviewers": []interface{}{"security"}}},
+		{method: http.MethodPatch, path: "/api/v1/repos/jfrog/frogbot/issues/3", body: map[string]interface{}{"assignees": []interface{}{"froggy"}}},
+	}, requests)
+}
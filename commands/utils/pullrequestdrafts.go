@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The actions taken on fixes that fail the verification
+const (
+	// Open the fix pull request as a draft, which explains why the verification failed
+	DraftFailedFixes = "draft"
+	// Don't open a pull request for the fix
+	SkipFailedFixes = "skip"
+)
+
+// pullRequestDrafter is implemented by the clients of the Git providers which support draft pull requests.
+type pullRequestDrafter interface {
+	ConvertPullRequestToDraft(ctx context.Context, owner, repository string, pullRequestID int) error
+}
+
+// ConvertPullRequestToDraft marks the pull request as a draft, which isn't ready to be merged.
+// If the Git provider doesn't support it, a warning is logged.
+func ConvertPullRequestToDraft(ctx context.Context, client vcsclient.VcsClient, owner, repository string, pullRequestID int) error {
+	drafter, ok := client.(pullRequestDrafter)
+	if !ok {
+		log.Warn("Draft pull requests aren't supported by the Git provider")
+		return nil
+	}
+	log.Info(fmt.Sprintf("Converting pull request %d to a draft", pullRequestID))
+	return drafter.ConvertPullRequestToDraft(ctx, owner, repository, pullRequestID)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"context"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestGithubConvertPullRequestToDraft(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, map[string]string{"/repos/jfrog/frogbot/pulls/3": `{"number": 3, "node_id": "PR_kwDOA"}`}, &requests)
+	defer server.Close()
+	client, err := newGithubClient(nil, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "github-token"}, http.DefaultTransport)
+	require.NoError(t, err)
+
+	assert.NoError(t, ConvertPullRequestToDraft(context.Background(), client, "jfrog", "frogbot", 3))
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/graphql", requests[0].path)
+	assert.Contains(t, requests[0].body.(map[string]interface{})["query"], "convertPullRequestToDraft")
+	assert.Equal(t, map[string]interface{}{"pullRequestId": "PR_kwDOA"}, requests[0].body.(map[string]interface{})["variables"])
+}
+
+func TestGitlabConvertPullRequestToDraft(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, map[string]string{
+		"/api/v4/projects/jfrog%2Ffrogbot/merge_requests/3": `{"title": "Upgrade minimist to 1.2.6", "draft": false}`,
+		"/api/v4/projects/jfrog%2Ffrogbot/merge_requests/4": `{"title": "Draft: Upgrade json5 to 2.2.2", "draft": true}`,
+	}, &requests)
+	defer server.Close()
+	client := newGitlabClient(nil, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "gitlab-token"}, http.DefaultTransport)
+
+	assert.NoError(t, ConvertPullRequestToDraft(context.Background(), client, "jfrog", "frogbot", 3))
+	// Drafts aren't updated
+	assert.NoError(t, ConvertPullRequestToDraft(context.Background(), client, "jfrog", "frogbot", 4))
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPut, path: "/api/v4/projects/jfrog/frogbot/merge_requests/3", body: map[string]interface{}{"title": "Draft: Upgrade minimist to 1.2.6"}},
+	}, requests)
+}
+
+func TestGiteaConvertPullRequestToDraft(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, map[string]string{
+		"/api/v1/repos/jfrog/frogbot/pulls/3": `{"number": 3, "title": "Upgrade minimist to 1.2.6"}`,
+		"/api/v1/repos/jfrog/frogbot/pulls/4": `{"number": 4, "title": "WIP: Upgrade json5 to 2.2.2"}`,
+	}, &requests)
+	defer server.Close()
+	client, err := newGiteaClient(vcsclient.VcsInfo{APIEndpoint: server.URL, Token: giteaTestToken}, http.DefaultTransport)
+	require.NoError(t, err)
+
+	assert.NoError(t, ConvertPullRequestToDraft(context.Background(), client, "jfrog", "frogbot", 3))
+	assert.NoError(t, ConvertPullRequestToDraft(context.Background(), client, "jfrog", "frogbot", 4))
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPatch, path: "/api/v1/repos/jfrog/frogbot/pulls/3", body: map[string]interface{}{"title": "WIP: Upgrade minimist to 1.2.6"}},
+	}, requests)
+}
@@ -0,0 +1,361 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/xanzy/go-gitlab"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	// The first wait between attempts. Each following wait is doubled, up to retryMaxInterval.
+	retryBaseInterval = 2 * time.Second
+	retryMaxInterval  = 60 * time.Second
+	// Rate limits that reset later than this are not waited for, and the error is returned instead.
+	retryMaxRateLimitWait = 5 * time.Minute
+)
+
+// Matches the HTTP status line at the beginning of the error messages returned by the VCS clients that don't expose a typed error,
+// such as "Status: 503 Service Unavailable" of Bitbucket Server, "503 Service Unavailable" of Bitbucket Cloud, or "server response: 503 Service Unavailable".
+var statusLineRegex = regexp.MustCompile(`^(?i:status: |server response: )?(\d{3}) [A-Za-z]`)
+
+// httpStatusError is returned by the REST API requests Frogbot sends by itself, when the Git provider responds with an error status.
+type httpStatusError struct {
+	provider   string
+	apiPath    string
+	status     string
+	statusCode int
+}
+
+func newHttpStatusError(provider, apiPath string, resp *http.Response) error {
+	return &httpStatusError{provider: provider, apiPath: apiPath, status: resp.Status, statusCode: resp.StatusCode}
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("the %s request to %s returned status %s", e.provider, e.apiPath, e.status)
+}
+
+// retryExecutor runs an operation and retries it with an exponential backoff, as long as the returned error is transient.
+type retryExecutor struct {
+	maxRetries int
+	// sleep is replaceable for testing purposes
+	sleep func(time.Duration)
+}
+
+func newRetryExecutor(maxRetries int) *retryExecutor {
+	return &retryExecutor{maxRetries: maxRetries, sleep: time.Sleep}
+}
+
+// isTransientFunc decides whether an error should be retried.
+// A positive wait overrides the exponential backoff, for example when the server sent a Retry-After header.
+type isTransientFunc func(err error) (wait time.Duration, retry bool)
+
+func (re *retryExecutor) execute(operationName string, isTransient isTransientFunc, operation func() error) (err error) {
+	for attempt := 0; ; attempt++ {
+		if err = operation(); err == nil || attempt >= re.maxRetries {
+			return
+		}
+		wait, retry := isTransient(err)
+		if !retry {
+			return
+		}
+		if wait <= 0 {
+			wait = backoffInterval(attempt)
+		}
+		log.Warn(fmt.Sprintf("%s failed (attempt %d of %d), retrying in %s: %s", operationName, attempt+1, re.maxRetries+1, wait, err.Error()))
+		re.sleep(wait)
+	}
+}
+
+func backoffInterval(attempt int) time.Duration {
+	interval := retryBaseInterval << attempt
+	if interval <= 0 || interval > retryMaxInterval {
+		return retryMaxInterval
+	}
+	return interval
+}
+
+// isTransientVcsError returns true for rate limits and server side errors returned by the VCS provider REST API.
+func isTransientVcsError(err error) (time.Duration, bool) {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		wait := time.Until(rateLimitErr.Rate.Reset.Time)
+		if wait > retryMaxRateLimitWait {
+			log.Debug("The GitHub rate limit resets at", rateLimitErr.Rate.Reset.Time.String(), "which is too far to wait for")
+			return 0, false
+		}
+		return wait, true
+	}
+	// GitHub secondary rate limits
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseRateLimitErr) {
+		if abuseRateLimitErr.RetryAfter != nil {
+			return *abuseRateLimitErr.RetryAfter, *abuseRateLimitErr.RetryAfter <= retryMaxRateLimitWait
+		}
+		return 0, true
+	}
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) {
+		if githubErr.Response == nil {
+			return 0, false
+		}
+		return getRetryAfter(githubErr.Response.Header), isTransientStatusCode(githubErr.Response.StatusCode)
+	}
+	var gitlabErr *gitlab.ErrorResponse
+	if errors.As(err, &gitlabErr) {
+		if gitlabErr.Response == nil {
+			return 0, false
+		}
+		return getRetryAfter(gitlabErr.Response.Header), isTransientStatusCode(gitlabErr.Response.StatusCode)
+	}
+	statusCode, found := getHttpStatusCode(err)
+	return 0, found && isTransientStatusCode(statusCode)
+}
+
+// getHttpStatusCode returns the HTTP status code of an error returned by a VCS client, if the error holds one.
+func getHttpStatusCode(err error) (int, bool) {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode, true
+	}
+	var azureErr *azuredevops.WrappedError
+	if errors.As(err, &azureErr) && azureErr.StatusCode != nil {
+		return *azureErr.StatusCode, true
+	}
+	var azureErrValue azuredevops.WrappedError
+	if errors.As(err, &azureErrValue) && azureErrValue.StatusCode != nil {
+		return *azureErrValue.StatusCode, true
+	}
+	if match := statusLineRegex.FindStringSubmatch(err.Error()); match != nil {
+		statusCode, convErr := strconv.Atoi(match[1])
+		return statusCode, convErr == nil
+	}
+	return 0, false
+}
+
+func isTransientStatusCode(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// getRetryAfter parses the Retry-After header, which holds either a number of seconds or an HTTP date.
+func getRetryAfter(header http.Header) time.Duration {
+	retryAfter := header.Get("Retry-After")
+	if retryAfter == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// isTransientGitError returns true for errors of git remote operations that may be resolved by running the same operation again,
+// such as network errors and server side errors. Any other error, such as a rejected push or a missing ref, isn't retried.
+func isTransientGitError(err error) (time.Duration, bool) {
+	var cliErr *transientGitCliError
+	if errors.As(err, &cliErr) {
+		return 0, true
+	}
+	// go-git wraps the HTTP errors which don't have a matching transport error
+	var unexpectedErr *plumbing.UnexpectedError
+	if errors.As(err, &unexpectedErr) {
+		var httpErr *githttp.Err
+		if errors.As(unexpectedErr.Err, &httpErr) && httpErr.Response != nil {
+			return getRetryAfter(httpErr.Response.Header), isTransientStatusCode(httpErr.StatusCode())
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return 0, true
+	}
+	for _, transientErr := range []error{io.ErrUnexpectedEOF, syscall.ECONNRESET, syscall.ECONNREFUSED, syscall.ECONNABORTED, syscall.EPIPE, syscall.ETIMEDOUT} {
+		if errors.Is(err, transientErr) {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// retryingVcsClient wraps a VcsClient and retries the REST API calls Frogbot performs,
+// when they fail due to rate limits or transient server errors.
+type retryingVcsClient struct {
+	vcsclient.VcsClient
+	executor *retryExecutor
+}
+
+func NewRetryingVcsClient(client vcsclient.VcsClient, maxRetries int) vcsclient.VcsClient {
+	if maxRetries <= 0 {
+		return client
+	}
+	return &retryingVcsClient{VcsClient: client, executor: newRetryExecutor(maxRetries)}
+}
+
+func (rc *retryingVcsClient) retry(operationName string, operation func() error) error {
+	return rc.executor.execute(operationName, isTransientVcsError, operation)
+}
+
+func (rc *retryingVcsClient) DownloadRepository(ctx context.Context, owner, repository, branch, localPath string) error {
+	return rc.retry("Downloading repository", func() error {
+		return rc.VcsClient.DownloadRepository(ctx, owner, repository, branch, localPath)
+	})
+}
+
+// CreatePullRequest isn't idempotent, so before retrying it, the pull request is looked for in case the failed attempt created it.
+func (rc *retryingVcsClient) CreatePullRequest(ctx context.Context, owner, repository, sourceBranch, targetBranch, title, description string) error {
+	attempted := false
+	return rc.retry("Creating pull request", func() error {
+		if attempted {
+			if exists, err := rc.pullRequestExists(ctx, owner, repository, sourceBranch, targetBranch); err != nil || exists {
+				return err
+			}
+		}
+		attempted = true
+		return rc.VcsClient.CreatePullRequest(ctx, owner, repository, sourceBranch, targetBranch, title, description)
+	})
+}
+
+func (rc *retryingVcsClient) pullRequestExists(ctx context.Context, owner, repository, sourceBranch, targetBranch string) (bool, error) {
+	pullRequests, err := rc.VcsClient.ListOpenPullRequests(ctx, owner, repository)
+	if err != nil {
+		return false, err
+	}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.Source.Name == sourceBranch && pullRequest.Target.Name == targetBranch {
+			log.Debug(fmt.Sprintf("Pull request %d from %s to %s was created by the failed attempt", pullRequest.ID, sourceBranch, targetBranch))
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (rc *retryingVcsClient) UpdatePullRequest(ctx context.Context, owner, repository, title, body, targetBranchName string, prId int, state vcsutils.PullRequestState) error {
+	return rc.retry("Updating pull request", func() error {
+		return rc.VcsClient.UpdatePullRequest(ctx, owner, repository, title, body, targetBranchName, prId, state)
+	})
+}
+
+// AddPullRequestComment isn't idempotent, so before retrying it, the comment is looked for in case the failed attempt added it.
+func (rc *retryingVcsClient) AddPullRequestComment(ctx context.Context, owner, repository, content string, pullRequestID int) error {
+	attempted := false
+	return rc.retry("Adding pull request comment", func() error {
+		if attempted {
+			if exists, err := rc.commentExists(ctx, owner, repository, content, pullRequestID); err != nil || exists {
+				return err
+			}
+		}
+		attempted = true
+		return rc.VcsClient.AddPullRequestComment(ctx, owner, repository, content, pullRequestID)
+	})
+}
+
+func (rc *retryingVcsClient) commentExists(ctx context.Context, owner, repository, content string, pullRequestID int) (bool, error) {
+	comments, err := rc.VcsClient.ListPullRequestComments(ctx, owner, repository, pullRequestID)
+	if err != nil {
+		return false, err
+	}
+	for _, comment := range comments {
+		if comment.Content == content {
+			log.Debug(fmt.Sprintf("The comment on pull request %d was added by the failed attempt", pullRequestID))
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (rc *retryingVcsClient) ListPullRequestComments(ctx context.Context, owner, repository string, pullRequestID int) (comments []vcsclient.CommentInfo, err error) {
+	err = rc.retry("Listing pull request comments", func() (e error) {
+		comments, e = rc.VcsClient.ListPullRequestComments(ctx, owner, repository, pullRequestID)
+		return
+	})
+	return
+}
+
+func (rc *retryingVcsClient) ListOpenPullRequestsWithBody(ctx context.Context, owner, repository string) (pullRequests []vcsclient.PullRequestInfo, err error) {
+	err = rc.retry("Listing open pull requests", func() (e error) {
+		pullRequests, e = rc.VcsClient.ListOpenPullRequestsWithBody(ctx, owner, repository)
+		return
+	})
+	return
+}
+
+func (rc *retryingVcsClient) ListOpenPullRequests(ctx context.Context, owner, repository string) (pullRequests []vcsclient.PullRequestInfo, err error) {
+	err = rc.retry("Listing open pull requests", func() (e error) {
+		pullRequests, e = rc.VcsClient.ListOpenPullRequests(ctx, owner, repository)
+		return
+	})
+	return
+}
+
+func (rc *retryingVcsClient) GetRepositoryInfo(ctx context.Context, owner, repository string) (repositoryInfo vcsclient.RepositoryInfo, err error) {
+	err = rc.retry("Getting repository info", func() (e error) {
+		repositoryInfo, e = rc.VcsClient.GetRepositoryInfo(ctx, owner, repository)
+		return
+	})
+	return
+}
+
+func (rc *retryingVcsClient) GetRepositoryEnvironmentInfo(ctx context.Context, owner, repository, name string) (environmentInfo vcsclient.RepositoryEnvironmentInfo, err error) {
+	err = rc.retry("Getting repository environment info", func() (e error) {
+		environmentInfo, e = rc.VcsClient.GetRepositoryEnvironmentInfo(ctx, owner, repository, name)
+		return
+	})
+	return
+}
+
+func (rc *retryingVcsClient) UploadCodeScanning(ctx context.Context, owner, repository, branch, scanResults string) (id string, err error) {
+	err = rc.retry("Uploading code scanning results", func() (e error) {
+		id, e = rc.VcsClient.UploadCodeScanning(ctx, owner, repository, branch, scanResults)
+		return
+	})
+	return
+}
+
+// DownloadFileFromRepo isn't retried when the file doesn't exist, as a missing file is a valid response.
+func (rc *retryingVcsClient) DownloadFileFromRepo(ctx context.Context, owner, repository, branch, path string) (content []byte, statusCode int, err error) {
+	_ = rc.retry("Downloading "+path, func() error {
+		content, statusCode, err = rc.VcsClient.DownloadFileFromRepo(ctx, owner, repository, branch, path)
+		if statusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	})
+	return
+}
+
+// The following methods forward the optional operations of the wrapped client, which aren't part of the VcsClient interface.
+
+func (rc *retryingVcsClient) AssignPullRequest(ctx context.Context, owner, repository string, pullRequestID int, assignments *PullRequestAssignments) error {
+	return rc.retry("Assigning pull request", func() error {
+		return AssignPullRequest(ctx, rc.VcsClient, owner, repository, pullRequestID, assignments)
+	})
+}
+
+func (rc *retryingVcsClient) EnableAutoMerge(ctx context.Context, owner, repository string, pullRequestID int, mergeMethod string) error {
+	return rc.retry("Enabling auto-merge", func() error {
+		return EnableAutoMerge(ctx, rc.VcsClient, owner, repository, pullRequestID, mergeMethod)
+	})
+}
+
+func (rc *retryingVcsClient) ConvertPullRequestToDraft(ctx context.Context, owner, repository string, pullRequestID int) error {
+	return rc.retry("Converting pull request to a draft", func() error {
+		return ConvertPullRequestToDraft(ctx, rc.VcsClient, owner, repository, pullRequestID)
+	})
+}
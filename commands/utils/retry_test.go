@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const secondaryRateLimitResponse = `{"message":"You have exceeded a secondary rate limit","documentation_url":"https://docs.github.com/en/rest/overview/resources-in-the-rest-api#secondary-rate-limits"}`
+
+func TestRetryingVcsClient_ListPullRequestComments(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         int
+		failureStatus    int
+		failureBody      string
+		retryAfter       string
+		expectedRequests int
+		expectedWaits    []time.Duration
+		expectError      bool
+	}{
+		{
+			name:             "Server error",
+			failures:         2,
+			failureStatus:    http.StatusBadGateway,
+			expectedRequests: 3,
+			expectedWaits:    []time.Duration{retryBaseInterval, 2 * retryBaseInterval},
+		},
+		{
+			name:             "Too many requests with Retry-After",
+			failures:         1,
+			failureStatus:    http.StatusTooManyRequests,
+			retryAfter:       "3",
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{3 * time.Second},
+		},
+		{
+			name:             "Secondary rate limit",
+			failures:         1,
+			failureStatus:    http.StatusForbidden,
+			failureBody:      secondaryRateLimitResponse,
+			retryAfter:       "7",
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{7 * time.Second},
+		},
+		{
+			name:             "Not found isn't retried",
+			failures:         1,
+			failureStatus:    http.StatusNotFound,
+			expectedRequests: 1,
+			expectError:      true,
+		},
+		{
+			name:             "Retries exhausted",
+			failures:         5,
+			failureStatus:    http.StatusServiceUnavailable,
+			expectedRequests: 4,
+			expectedWaits:    []time.Duration{retryBaseInterval, 2 * retryBaseInterval, 4 * retryBaseInterval},
+			expectError:      true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= test.failures {
+					if test.retryAfter != "" {
+						w.Header().Set("Retry-After", test.retryAfter)
+					}
+					w.WriteHeader(test.failureStatus)
+					_, _ = w.Write([]byte(test.failureBody))
+					return
+				}
+				_, _ = w.Write([]byte(`[{"id":1,"body":"rescan","created_at":"2023-06-01T10:00:00Z"}]`))
+			}))
+			defer server.Close()
+
+			githubClient, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token("token").Build()
+			assert.NoError(t, err)
+			client := NewRetryingVcsClient(githubClient, defaultMaxRetries).(*retryingVcsClient)
+			var waits []time.Duration
+			client.executor.sleep = func(wait time.Duration) { waits = append(waits, wait) }
+
+			comments, err := client.ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1)
+			assert.Equal(t, test.expectedRequests, requests)
+			assert.Equal(t, test.expectedWaits, waits)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, comments, 1)
+		})
+	}
+}
+
+func TestRetryingVcsClient_DownloadFileFromRepoNotFound(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	githubClient, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token("token").Build()
+	assert.NoError(t, err)
+	client := NewRetryingVcsClient(githubClient, defaultMaxRetries)
+	_, statusCode, err := client.DownloadFileFromRepo(context.Background(), "jfrog", "frogbot", "master", ".frogbot/frogbot-config.yml")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, 1, requests)
+}
+
+func TestNewRetryingVcsClientNoRetries(t *testing.T) {
+	githubClient, err := vcsclient.NewClientBuilder(vcsutils.GitHub).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, githubClient, NewRetryingVcsClient(githubClient, 0))
+}
+
+func TestIsTransientVcsError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{err: errors.New("Status: 503 Service Unavailable, Body: "), expected: true},
+		{err: &gitlab.ErrorResponse{Response: &http.Response{StatusCode: http.StatusTooManyRequests, Request: &http.Request{Method: http.MethodPost, URL: &url.URL{}}}}, expected: true},
+		{err: &gitlab.ErrorResponse{Response: &http.Response{StatusCode: http.StatusConflict, Request: &http.Request{Method: http.MethodPost, URL: &url.URL{}}}}, expected: false},
+		{err: fmt.Errorf("failed to assign: %w", newHttpStatusError("Gitea", "repos/jfrog/frogbot/pulls", &http.Response{Status: "502 Bad Gateway", StatusCode: http.StatusBadGateway})), expected: true},
+		{err: errors.New("500 Internal Server Error"), expected: true},
+		{err: errors.New("server response: 504 Gateway Timeout"), expected: true},
+		{err: errors.New("Status: 401 Unauthorized, Body: "), expected: false},
+		{err: errors.New("pull request 5029 not found"), expected: false},
+		{err: errors.New("the version 1.2.500 of minimist wasn't found"), expected: false},
+		{err: errors.New("Status: 422 Unprocessable Entity, Body: issue 503 is closed"), expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			_, retry := isTransientVcsError(test.err)
+			assert.Equal(t, test.expected, retry)
+		})
+	}
+}
+
+func TestBackoffInterval(t *testing.T) {
+	assert.Equal(t, retryBaseInterval, backoffInterval(0))
+	assert.Equal(t, 4*retryBaseInterval, backoffInterval(2))
+	assert.Equal(t, retryMaxInterval, backoffInterval(10))
+	assert.Equal(t, retryMaxInterval, backoffInterval(100))
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), getRetryAfter(http.Header{}))
+	assert.Equal(t, 120*time.Second, getRetryAfter(http.Header{"Retry-After": []string{"120"}}))
+	retryAfterDate := getRetryAfter(http.Header{"Retry-After": []string{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}})
+	assert.True(t, retryAfterDate > 0 && retryAfterDate <= time.Minute)
+}
+
+func TestRetryingVcsClientForwardsOptionalOperations(t *testing.T) {
+	var requests []recordedRequest
+	server := newAssignmentsStandIn(t, map[string]string{"/repos/jfrog/frogbot/pulls/3": `{"number": 3, "node_id": "PR_kwDOA"}`}, &requests)
+	defer server.Close()
+	// The client is built the same way as the client of the Frogbot commands
+	client, err := newVcsClient(&Git{ClientInfo: ClientInfo{GitProvider: vcsutils.GitHub, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "github-token"}}})
+	require.NoError(t, err)
+	client = NewRetryingVcsClient(client, defaultMaxRetries)
+	require.IsType(t, &retryingVcsClient{}, client)
+
+	assert.NoError(t, AssignPullRequest(context.Background(), client, "jfrog", "frogbot", 3, &PullRequestAssignments{Labels: []string{"security"}}))
+	assert.NoError(t, EnableAutoMerge(context.Background(), client, "jfrog", "frogbot", 3, MergeMethodMerge))
+	assert.NoError(t, ConvertPullRequestToDraft(context.Background(), client, "jfrog", "frogbot", 3))
+	require.Len(t, requests, 3)
+	assert.Equal(t, "/repos/jfrog/frogbot/issues/3/labels", requests[0].path)
+	assert.Contains(t, requests[1].body.(map[string]interface{})["query"], "enablePullRequestAutoMerge")
+	assert.Contains(t, requests[2].body.(map[string]interface{})["query"], "convertPullRequestToDraft")
+}
+
+func TestRetryingVcsClient_CreatePullRequestCreatedByFailedAttempt(t *testing.T) {
+	creations := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			// The pull request is created, but the response times out
+			creations++
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"number":4,"head":{"ref":"frogbot-fix","repo":{"name":"frogbot"}},"base":{"ref":"master","repo":{"name":"frogbot"}}}]`))
+	}))
+	defer server.Close()
+
+	githubClient, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token("token").Build()
+	require.NoError(t, err)
+	client := NewRetryingVcsClient(githubClient, defaultMaxRetries).(*retryingVcsClient)
+	client.executor.sleep = func(time.Duration) {}
+
+	assert.NoError(t, client.CreatePullRequest(context.Background(), "jfrog", "frogbot", "frogbot-fix", "master", "title", "body"))
+	assert.Equal(t, 1, creations)
+}
+
+func TestRetryingVcsClient_AddPullRequestCommentAddedByFailedAttempt(t *testing.T) {
+	additions := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			additions++
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":1,"body":"rescan","created_at":"2023-06-01T10:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	githubClient, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token("token").Build()
+	require.NoError(t, err)
+	client := NewRetryingVcsClient(githubClient, defaultMaxRetries).(*retryingVcsClient)
+	client.executor.sleep = func(time.Duration) {}
+
+	assert.NoError(t, client.AddPullRequestComment(context.Background(), "jfrog", "frogbot", "rescan", 1))
+	assert.Equal(t, 1, additions)
+	// A comment that wasn't added by the failed attempt is added again
+	assert.Error(t, client.AddPullRequestComment(context.Background(), "jfrog", "frogbot", "another comment", 1))
+	assert.Equal(t, 1+defaultMaxRetries+1, additions)
+}
+
+func TestIsTransientGitError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Server error", err: plumbing.NewUnexpectedError(&githttp.Err{Response: &http.Response{StatusCode: http.StatusBadGateway}}), expected: true},
+		{name: "Client error", err: plumbing.NewUnexpectedError(&githttp.Err{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}}), expected: false},
+		{name: "Network error", err: fmt.Errorf("push failed: %w", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), expected: true},
+		{name: "Unexpected EOF", err: io.ErrUnexpectedEOF, expected: true},
+		{name: "Authentication", err: transport.ErrAuthenticationRequired, expected: false},
+		{name: "Non fast-forward", err: git.ErrNonFastForwardUpdate, expected: false},
+		{name: "Missing reference", err: plumbing.ErrReferenceNotFound, expected: false},
+		{name: "Rejected push", err: toGitCliError("push", "! [rejected]        frogbot-fix -> frogbot-fix (non-fast-forward)", nil), expected: false},
+		{name: "Connection reset", err: toGitCliError("fetch", "fatal: unable to access 'https://github.com/jfrog/frogbot.git/': Connection reset by peer", nil), expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, transient := isTransientGitError(test.err)
+			assert.Equal(t, test.expected, transient)
+		})
+	}
+}
@@ -22,6 +22,29 @@ func NewScanDetails(client vcsclient.VcsClient, server *config.ServerDetails, gi
 	return &ScanDetails{client: client, ServerDetails: server, Git: git}
 }
 
+// NewProjectScanDetails returns the scan details of a project in the repository.
+// Scan settings the project doesn't set are taken from the repository. Since Xray prefers watches over the JFrog project key,
+// a project that sets either of them replaces both repository settings.
+func NewProjectScanDetails(client vcsclient.VcsClient, repository *Repository, project *Project) *ScanDetails {
+	minSeverity := repository.MinSeverity
+	if project.MinSeverity != "" {
+		minSeverity = project.MinSeverity
+	}
+	fixableOnly := repository.FixableOnly
+	if project.FixableOnly != nil {
+		fixableOnly = *project.FixableOnly
+	}
+	watches, jfrogProjectKey := repository.Watches, repository.JFrogProjectKey
+	if len(project.Watches) > 0 || project.JFrogProjectKey != "" {
+		watches, jfrogProjectKey = project.Watches, project.JFrogProjectKey
+	}
+	return NewScanDetails(client, &repository.Server, &repository.Git).
+		SetProject(project).
+		SetXrayGraphScanParams(watches, jfrogProjectKey).
+		SetMinSeverity(minSeverity).
+		SetFixableOnly(fixableOnly)
+}
+
 func (sc *ScanDetails) SetFailOnInstallationErrors(toFail bool) *ScanDetails {
 	sc.failOnInstallationErrors = toFail
 	return sc
@@ -72,6 +95,20 @@ func (sc *ScanDetails) MinSeverityFilter() string {
 	return sc.minSeverityFilter
 }
 
+// WithDiscoveredInstallCommand returns a copy of the scan details, with the install command needed by the auto-discovered project in workDir.
+// The copy keeps the project shared by the other working directories unchanged.
+func (sc *ScanDetails) WithDiscoveredInstallCommand(workDir string) (*ScanDetails, error) {
+	installCommand, err := GetDiscoveredInstallCommand(workDir)
+	if err != nil || installCommand == "" {
+		return sc, err
+	}
+	project := *sc.Project
+	setProjectInstallCommand(installCommand, &project)
+	scanDetails := *sc
+	scanDetails.Project = &project
+	return &scanDetails, nil
+}
+
 func createXrayScanParams(watches []string, project string) (params *services.XrayGraphScanParams) {
 	params = &services.XrayGraphScanParams{
 		ScanType:        services.Dependency,
@@ -28,3 +28,27 @@ func TestCreateXrayScanParams(t *testing.T) {
 	assert.False(t, scanDetails.IncludeVulnerabilities)
 	assert.False(t, scanDetails.IncludeLicenses)
 }
+
+func TestNewProjectScanDetails(t *testing.T) {
+	fixableOnly := false
+	repository := &Repository{Params: Params{
+		Scan:          Scan{MinSeverity: "High", FixableOnly: true},
+		JFrogPlatform: JFrogPlatform{Watches: []string{"repo-watch"}},
+	}}
+
+	// Inherit the repository settings
+	project := &Project{}
+	scanDetails := NewProjectScanDetails(nil, repository, project)
+	assert.Equal(t, project, scanDetails.Project)
+	assert.Equal(t, "High", scanDetails.MinSeverityFilter())
+	assert.True(t, scanDetails.FixableOnly())
+	assert.Equal(t, []string{"repo-watch"}, scanDetails.XrayGraphScanParams.Watches)
+
+	// Override the repository settings
+	project = &Project{MinSeverity: "Low", FixableOnly: &fixableOnly, JFrogPlatform: JFrogPlatform{JFrogProjectKey: "payments"}}
+	scanDetails = NewProjectScanDetails(nil, repository, project)
+	assert.Equal(t, "Low", scanDetails.MinSeverityFilter())
+	assert.False(t, scanDetails.FixableOnly())
+	assert.Empty(t, scanDetails.XrayGraphScanParams.Watches)
+	assert.Equal(t, "payments", scanDetails.ProjectKey)
+}
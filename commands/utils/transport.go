@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// The timeout of the requests that the REST clients of Frogbot send to the Git provider
+const gitProviderRequestTimeout = 2 * time.Minute
+
+// The default transport of the process, which the connections without connection settings use
+var defaultHttpTransport = http.DefaultTransport
+
+// HttpTransportSettings holds the connection settings of the HTTP requests to the Git provider.
+type HttpTransportSettings struct {
+	// The URL of the proxy server. If empty, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are used.
+	Proxy string
+	// PEM encoded certificates of the CAs to trust, in addition to the system CAs
+	CaCerts string
+	// PEM encoded client certificate and private key, for Git servers that require mutual TLS
+	ClientCert string
+	ClientKey  string
+}
+
+func (ts *HttpTransportSettings) IsSet() bool {
+	return ts.Proxy != "" || ts.CaCerts != "" || ts.ClientCert != ""
+}
+
+// readHttpTransportSettingsFromEnv reads the connection settings. The certificates and the key may be set as PEM, or as the paths of PEM files.
+func readHttpTransportSettingsFromEnv() (settings HttpTransportSettings, err error) {
+	settings.Proxy = getTrimmedEnv(GitProxyEnv)
+	if settings.CaCerts, err = readPemEnv(GitCaCertEnv, "certificate"); err != nil {
+		return
+	}
+	if settings.ClientCert, err = readPemEnv(GitClientCertEnv, "certificate"); err != nil {
+		return
+	}
+	if settings.ClientKey, err = readPrivateKeyEnv(GitClientKeyEnv); err != nil {
+		return
+	}
+	if (settings.ClientCert == "") != (settings.ClientKey == "") {
+		err = fmt.Errorf("%s and %s should be set together", GitClientCertEnv, GitClientKeyEnv)
+	}
+	return
+}
+
+// configureHttpTransport creates the transport of the connections to the Git provider, which holds the connection settings.
+// The REST clients and the git operations of Frogbot are given the transport explicitly, and the default transport of the process isn't changed.
+// The REST clients of froggit-go can't be given a transport, so their requests use only the proxy environment variables and the system CAs.
+func configureHttpTransport(clientInfo *ClientInfo) error {
+	if !clientInfo.HttpTransport.IsSet() {
+		return nil
+	}
+	transport, err := newHttpTransport(clientInfo.HttpTransport)
+	if err != nil {
+		return err
+	}
+	clientInfo.httpTransport = transport
+	log.Warn(fmt.Sprintf("Some of the requests to the Git provider don't use %s, %s and %s. Set the HTTPS_PROXY environment variable and add the CA certificates to the system as well.", GitProxyEnv, GitCaCertEnv, GitClientCertEnv))
+	return nil
+}
+
+func newHttpTransport(settings HttpTransportSettings) (*http.Transport, error) {
+	transport := defaultHttpTransport.(*http.Transport).Clone()
+	if settings.Proxy != "" {
+		proxyUrl, err := url.Parse(settings.Proxy)
+		if err != nil || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("%s should be the URL of a proxy server, such as http://proxy.company.info:8080", GitProxyEnv)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if settings.CaCerts != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			log.Debug("Couldn't load the system CAs:", err.Error())
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM([]byte(settings.CaCerts)) {
+			return nil, errors.New("failed to parse the CA certificates set in " + GitCaCertEnv)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	if settings.ClientCert != "" {
+		clientCert, err := tls.X509KeyPair([]byte(settings.ClientCert), []byte(settings.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate set in %s and %s: %s", GitClientCertEnv, GitClientKeyEnv, err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// generateClientCert returns a PEM encoded self-signed client certificate and its private key.
+func generateClientCert(t *testing.T) (*x509.Certificate, string, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "frogbot"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+		// Required for the certificate to be a CA of the client certificates pool
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	return cert, string(certPem), string(keyPem)
+}
+
+func TestNewHttpTransportTls(t *testing.T) {
+	clientCert, clientCertPem, clientKeyPem := generateClientCert(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	serverCaPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	// The server's CA and the client certificate are trusted
+	transport, err := newHttpTransport(HttpTransportSettings{CaCerts: serverCaPem, ClientCert: clientCertPem, ClientKey: clientKeyPem})
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Without the client certificate
+	transport, err = newHttpTransport(HttpTransportSettings{CaCerts: serverCaPem})
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: transport}).Get(server.URL)
+	assert.Error(t, err)
+
+	// Without the server's CA
+	transport, err = newHttpTransport(HttpTransportSettings{ClientCert: clientCertPem, ClientKey: clientKeyPem})
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: transport}).Get(server.URL)
+	assert.ErrorContains(t, err, "certificate")
+
+	_, err = newHttpTransport(HttpTransportSettings{CaCerts: "-----BEGIN CERTIFICATE-----\ninvalid\n-----END CERTIFICATE-----"})
+	assert.EqualError(t, err, "failed to parse the CA certificates set in JF_GIT_CA_CERT")
+	_, err = newHttpTransport(HttpTransportSettings{ClientCert: clientCertPem, ClientKey: serverCaPem})
+	assert.ErrorContains(t, err, "failed to load the client certificate set in JF_GIT_CLIENT_CERT and JF_GIT_CLIENT_KEY")
+}
+
+func TestNewHttpTransportProxy(t *testing.T) {
+	var proxiedUrl string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedUrl = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	transport, err := newHttpTransport(HttpTransportSettings{Proxy: proxy.URL})
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get("http://git.company.info/api/v4/projects")
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "http://git.company.info/api/v4/projects", proxiedUrl)
+
+	_, err = newHttpTransport(HttpTransportSettings{Proxy: "proxy.company.info"})
+	assert.EqualError(t, err, "JF_GIT_PROXY should be the URL of a proxy server, such as http://proxy.company.info:8080")
+}
+
+func TestReadHttpTransportSettingsFromEnv(t *testing.T) {
+	_, clientCertPem, clientKeyPem := generateClientCert(t)
+	keyFile := filepath.Join(t.TempDir(), "client.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(clientKeyPem), 0600))
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+
+	settings, err := readHttpTransportSettingsFromEnv()
+	assert.NoError(t, err)
+	assert.False(t, settings.IsSet())
+
+	SetEnvAndAssert(t, map[string]string{GitProxyEnv: "http://proxy.company.info:8080", GitClientCertEnv: clientCertPem, GitClientKeyEnv: keyFile})
+	settings, err = readHttpTransportSettingsFromEnv()
+	assert.NoError(t, err)
+	assert.True(t, settings.IsSet())
+	assert.Equal(t, HttpTransportSettings{Proxy: "http://proxy.company.info:8080", ClientCert: clientCertPem[:len(clientCertPem)-1], ClientKey: clientKeyPem}, settings)
+
+	SetEnvAndAssert(t, map[string]string{GitClientKeyEnv: ""})
+	_, err = readHttpTransportSettingsFromEnv()
+	assert.EqualError(t, err, "JF_GIT_CLIENT_CERT and JF_GIT_CLIENT_KEY should be set together")
+
+	SetEnvAndAssert(t, map[string]string{GitCaCertEnv: filepath.Join(t.TempDir(), "missing")})
+	_, err = readHttpTransportSettingsFromEnv()
+	assert.ErrorContains(t, err, "JF_GIT_CA_CERT should be a certificate or the path of a certificate file")
+}
+
+func TestConfigureHttpTransport(t *testing.T) {
+	var proxiedUrls []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedUrls = append(proxiedUrls, r.URL.String())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	defaultTransport := http.DefaultTransport
+	clientInfo := &ClientInfo{GitProvider: vcsutils.GitHub, HttpTransport: HttpTransportSettings{Proxy: proxy.URL}}
+	require.NoError(t, configureHttpTransport(clientInfo))
+	// The default transport of the process isn't changed, and only the clients that are given the transport use the settings
+	assert.Equal(t, defaultTransport, http.DefaultTransport)
+	assert.NotEqual(t, defaultHttpTransport, clientInfo.getHttpTransport())
+	resp, err := (&http.Client{Transport: clientInfo.getHttpTransport()}).Get("http://api.github.com/repos")
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, []string{"http://api.github.com/repos"}, proxiedUrls)
+
+	// The clients without connection settings use the default transport
+	assert.NoError(t, configureHttpTransport(&ClientInfo{}))
+	assert.Equal(t, defaultHttpTransport, (&ClientInfo{}).getHttpTransport())
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/artifactory/usage"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/url"
 	"os"
 	"regexp"
 	"sort"
@@ -39,12 +40,16 @@ const (
 	skipBuildToolDependencyMsg     = "Skipping vulnerable package %s since it is not defined in your package descriptor file. " +
 		"Update %s version to %s to fix this vulnerability."
 	JfrogHomeDirEnv = "JFROG_CLI_HOME_DIR"
+
+	fixPullRequestMetadataPrefix = "Frogbot fix: "
 )
 
 var (
 	TrueVal                 = true
 	FrogbotVersion          = "0.0.0"
 	branchInvalidCharsRegex = regexp.MustCompile(branchNameRegex)
+	// The metadata is written by FixPullRequestMetadata.ToMarkdownComment
+	fixPullRequestMetadataRegex = regexp.MustCompile(`\[comment\]: <> \(` + fixPullRequestMetadataPrefix + `([^)\s]*)\)`)
 )
 
 var BuildToolsDependenciesMap = map[coreutils.Technology][]string{
@@ -76,6 +81,10 @@ type VulnerabilityDetails struct {
 	IsDirectDependency bool
 	// Cves as a list of string
 	Cves []string
+	// States whether no fix version complies with the fix version strategy, so the minimal fix version is suggested instead
+	NonCompliantFixVersion bool
+	// Other vulnerabilities of the dependency, whose fixed versions don't name a version to update to, so the suggested fix version may not fix them
+	UnfixedVulnerabilities []string
 }
 
 func NewVulnerabilityDetails(vulnerability *formats.VulnerabilityOrViolationRow, fixVersion string) *VulnerabilityDetails {
@@ -104,6 +113,45 @@ func (vd *VulnerabilityDetails) UpdateFixVersionIfMax(fixVersion string) {
 	}
 }
 
+// FixPullRequestMetadata identifies the vulnerable package that a pull request fixes, and the working directory of the fixed project.
+// It's written to the body of the pull request as a hidden comment, to find the pull requests that a new fix supersedes.
+type FixPullRequestMetadata struct {
+	ImpactedDependencyName string
+	FixVersion             string
+	// The working directory of the project, relative to the root directory of the repository
+	WorkingDir string
+}
+
+func (fm *FixPullRequestMetadata) ToMarkdownComment() string {
+	values := url.Values{}
+	values.Set("package", fm.ImpactedDependencyName)
+	values.Set("version", fm.FixVersion)
+	values.Set("workingDir", fm.WorkingDir)
+	// The values are escaped, so they don't include the parentheses that end the comment
+	return MarkdownComment(fixPullRequestMetadataPrefix + values.Encode())
+}
+
+// ParseFixPullRequestMetadata returns nil if the body of the pull request doesn't include the metadata of a fix.
+func ParseFixPullRequestMetadata(pullRequestBody string) *FixPullRequestMetadata {
+	match := fixPullRequestMetadataRegex.FindStringSubmatch(pullRequestBody)
+	if len(match) != 2 {
+		return nil
+	}
+	values, err := url.ParseQuery(match[1])
+	if err != nil || values.Get("package") == "" {
+		return nil
+	}
+	return &FixPullRequestMetadata{ImpactedDependencyName: values.Get("package"), FixVersion: values.Get("version"), WorkingDir: values.Get("workingDir")}
+}
+
+// GetPullRequestReference returns the reference to the pull request, which the Git provider links in comments.
+func GetPullRequestReference(provider vcsutils.VcsProvider, pullRequestID int64) string {
+	if provider == vcsutils.GitLab || provider == vcsutils.AzureRepos {
+		return fmt.Sprintf("!%d", pullRequestID)
+	}
+	return fmt.Sprintf("#%d", pullRequestID)
+}
+
 type ErrMissingEnv struct {
 	VariableName string
 }
@@ -189,8 +237,8 @@ func VulnerabilityDetailsToMD5Hash(vulnerabilities ...*VulnerabilityDetails) (st
 
 // UploadScanToGitProvider uploads scan results to the relevant git provider in order to view the scan in the Git provider code scanning UI
 func UploadScanToGitProvider(scanResults *audit.Results, repo *Repository, branch string, client vcsclient.VcsClient) error {
-	if repo.GitProvider.String() != vcsutils.GitHub.String() {
-		log.Debug("Upload Scan to " + repo.GitProvider.String() + " is currently unsupported.")
+	if repo.GitProvider != vcsutils.GitHub {
+		log.Debug("Upload Scan to " + GetVcsProviderName(repo.GitProvider) + " is currently unsupported.")
 		return nil
 	}
 
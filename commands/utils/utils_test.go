@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
@@ -339,3 +340,20 @@ func TestBuildServerConfigFile(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedConfigurationFile, string(actualConfigurationFile))
 }
+
+func TestFixPullRequestMetadata(t *testing.T) {
+	metadata := FixPullRequestMetadata{ImpactedDependencyName: "github.com/jfrog/jfrog-cli-core/v2", FixVersion: "[2.31.0]", WorkingDir: "path/to/my project"}
+	body := "Pull request body" + metadata.ToMarkdownComment()
+	parsed := ParseFixPullRequestMetadata(body)
+	if assert.NotNil(t, parsed) {
+		assert.Equal(t, metadata, *parsed)
+	}
+	assert.Nil(t, ParseFixPullRequestMetadata("Pull request body"+MarkdownComment(CommentGeneratedByFrogbot)))
+}
+
+func TestGetPullRequestReference(t *testing.T) {
+	assert.Equal(t, "#12", GetPullRequestReference(vcsutils.GitHub, 12))
+	assert.Equal(t, "!12", GetPullRequestReference(vcsutils.GitLab, 12))
+	assert.Equal(t, "!12", GetPullRequestReference(vcsutils.AzureRepos, 12))
+	assert.Equal(t, "#12", GetPullRequestReference(vcsutils.BitbucketServer, 12))
+}
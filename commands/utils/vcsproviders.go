@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+)
+
+// The VcsProvider values of the registered providers start from this value, so that they never collide with the providers of froggit-go.
+const firstPluginVcsProvider vcsutils.VcsProvider = 1000
+
+// VcsProviderPlugin adds a Git provider which isn't supported by froggit-go.
+type VcsProviderPlugin struct {
+	// The JF_GIT_PROVIDER value that selects the provider
+	Name string
+	// Additional JF_GIT_PROVIDER values that select the provider, such as the names of compatible forks
+	Aliases []string
+	// Builds the REST API client of the provider, which sends its requests through the transport
+	NewClient func(vcsInfo vcsclient.VcsInfo, transport http.RoundTripper) (vcsclient.VcsClient, error)
+	// Returns the HTTPS URL the repository is cloned from and pushed to
+	HttpsCloneUrl func(git *Git) string
+	// Set if the provider doesn't render HTML in markdown
+	SimplifiedOutput bool
+}
+
+var vcsProviderPlugins []VcsProviderPlugin
+
+// RegisterVcsProvider registers a Git provider plugin, and returns the VcsProvider value which identifies it.
+func RegisterVcsProvider(plugin VcsProviderPlugin) vcsutils.VcsProvider {
+	vcsProviderPlugins = append(vcsProviderPlugins, plugin)
+	return firstPluginVcsProvider + vcsutils.VcsProvider(len(vcsProviderPlugins)-1)
+}
+
+// getVcsProviderPlugin returns the plugin registered for the provider, or nil if the provider is supported by froggit-go.
+func getVcsProviderPlugin(provider vcsutils.VcsProvider) *VcsProviderPlugin {
+	index := int(provider - firstPluginVcsProvider)
+	if index < 0 || index >= len(vcsProviderPlugins) {
+		return nil
+	}
+	return &vcsProviderPlugins[index]
+}
+
+// findVcsProviderPlugin returns the provider registered with the name, or false if no plugin is registered with it.
+func findVcsProviderPlugin(name string) (vcsutils.VcsProvider, bool) {
+	for index, plugin := range vcsProviderPlugins {
+		if plugin.Name == name {
+			return firstPluginVcsProvider + vcsutils.VcsProvider(index), true
+		}
+		for _, alias := range plugin.Aliases {
+			if alias == name {
+				return firstPluginVcsProvider + vcsutils.VcsProvider(index), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// GetVcsProviderName returns the name of the provider, including the providers registered as plugins.
+func GetVcsProviderName(provider vcsutils.VcsProvider) string {
+	if plugin := getVcsProviderPlugin(provider); plugin != nil {
+		return plugin.Name
+	}
+	return provider.String()
+}
+
+// newVcsClient builds the REST API client of the Git provider.
+func newVcsClient(gitParams *Git) (vcsclient.VcsClient, error) {
+	if plugin := getVcsProviderPlugin(gitParams.GitProvider); plugin != nil {
+		return plugin.NewClient(gitParams.VcsInfo, gitParams.getHttpTransport())
+	}
+	build := func(token string) (vcsclient.VcsClient, error) {
+		client, err := vcsclient.
+			NewClientBuilder(gitParams.GitProvider).
+			ApiEndpoint(gitParams.APIEndpoint).
+			Token(token).
+			Project(gitParams.Project).
+			Logger(log.GetLogger()).
+			Username(gitParams.Username).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		switch gitParams.GitProvider {
+		case vcsutils.BitbucketCloud:
+			client = newBitbucketCloudClient(client, gitParams.VcsInfo, gitParams.getHttpTransport())
+		case vcsutils.GitHub:
+			vcsInfo := gitParams.VcsInfo
+			vcsInfo.Token = token
+			return newGithubClient(client, vcsInfo, gitParams.getHttpTransport())
+		case vcsutils.GitLab:
+			vcsInfo := gitParams.VcsInfo
+			vcsInfo.Token = token
+			client = newGitlabClient(client, vcsInfo, gitParams.getHttpTransport())
+		}
+		return client, nil
+	}
+	if gitParams.githubApp != nil {
+		return newGithubAppClient(gitParams.githubApp, build)
+	}
+	return build(gitParams.Token)
+}
@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"strings"
+)
+
+// VersionRange is an interval of versions. An empty bound leaves the interval unbounded on its side.
+type VersionRange struct {
+	LowerBound     string
+	LowerInclusive bool
+	UpperBound     string
+	UpperInclusive bool
+	// Versions excluded from the interval, such as PEP 440 != clauses
+	Excluded []string
+}
+
+// Contains returns true if the version is in the range.
+func (vr *VersionRange) Contains(versionStr string) bool {
+	if vr.LowerBound != "" {
+		if compare := CompareVersions(versionStr, vr.LowerBound); compare < 0 || (compare == 0 && !vr.LowerInclusive) {
+			return false
+		}
+	}
+	if vr.UpperBound != "" {
+		if compare := CompareVersions(versionStr, vr.UpperBound); compare > 0 || (compare == 0 && !vr.UpperInclusive) {
+			return false
+		}
+	}
+	for _, excluded := range vr.Excluded {
+		if CompareVersions(versionStr, excluded) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MinimalVersion returns the lowest version that the range names as a fix version: its inclusive lower bound,
+// or otherwise the inclusive upper bound of a range bounded from below, such as (1.0,1.5], which is the only version the range names.
+// A range unbounded from below, such as (,1.5], holds the versions released before the vulnerability was introduced rather than its fixes,
+// so it names no fix version. Neither do ranges with exclusive bounds only, such as (1.0,). An empty string is returned for both.
+func (vr *VersionRange) MinimalVersion() string {
+	if vr.LowerBound == "" {
+		return ""
+	}
+	for _, bound := range []struct {
+		version   string
+		inclusive bool
+	}{{vr.LowerBound, vr.LowerInclusive}, {vr.UpperBound, vr.UpperInclusive}} {
+		if bound.version != "" && bound.inclusive && vr.Contains(bound.version) {
+			return bound.version
+		}
+	}
+	return ""
+}
+
+func (vr *VersionRange) String() string {
+	if vr.LowerBound != "" && vr.LowerBound == vr.UpperBound && vr.LowerInclusive && vr.UpperInclusive {
+		return "[" + vr.LowerBound + "]"
+	}
+	lower, upper := "(", ")"
+	if vr.LowerInclusive {
+		lower = "["
+	}
+	if vr.UpperInclusive {
+		upper = "]"
+	}
+	return lower + vr.LowerBound + "," + vr.UpperBound + upper
+}
+
+// CompareVersions returns 1 if first is greater than second, -1 if it's lower and 0 if they're equal. A 'v' prefix is ignored, as in Go modules.
+func CompareVersions(first, second string) int {
+	return version.NewVersion(strings.TrimPrefix(second, "v")).Compare(strings.TrimPrefix(first, "v"))
+}
+
+// ParseVersionRanges parses a fixed versions string of Xray into the ranges it consists of. The following notations are supported:
+// Maven and NuGet intervals of all technologies, such as [1.0], [1.0,2.0), (,1.0] or (1.0,),[2.0,3.0) for multiple ranges,
+// npm comparators, such as >=1.0.0 <2.0.0 || ^3.1.0,
+// PEP 440 specifiers of Python technologies, such as >=1.0,!=1.5,<2.0 or ~=1.4.5,
+// and a plain version, which is the lowest fix version: 1.0 means 1.0 ≤ x.
+func ParseVersionRanges(technology coreutils.Technology, fixVersions string) ([]VersionRange, error) {
+	fixVersions = strings.TrimSpace(fixVersions)
+	switch {
+	case fixVersions == "":
+		return nil, fmt.Errorf("the fixed versions string is empty")
+	case strings.HasPrefix(fixVersions, "[") || strings.HasPrefix(fixVersions, "("):
+		return parseIntervals(fixVersions)
+	case !strings.ContainsAny(fixVersions, "<>=!~^|* "):
+		return []VersionRange{{LowerBound: fixVersions, LowerInclusive: true}}, nil
+	}
+	switch technology {
+	case coreutils.Pip, coreutils.Pipenv, coreutils.Poetry:
+		versionRange, err := parsePep440Specifiers(fixVersions)
+		if err != nil {
+			return nil, err
+		}
+		return []VersionRange{*versionRange}, nil
+	default:
+		return parseNpmRanges(fixVersions)
+	}
+}
+
+// parseIntervals parses Maven and NuGet intervals, separated by commas.
+func parseIntervals(intervals string) (ranges []VersionRange, err error) {
+	remaining := intervals
+	for remaining = strings.TrimSpace(remaining); remaining != ""; remaining = strings.TrimLeft(remaining, ", ") {
+		end := strings.IndexAny(remaining, "])")
+		if end < 0 || (remaining[0] != '[' && remaining[0] != '(') {
+			return nil, fmt.Errorf("invalid version interval: %s", intervals)
+		}
+		versionRange := VersionRange{LowerInclusive: remaining[0] == '[', UpperInclusive: remaining[end] == ']'}
+		bounds := strings.Split(remaining[1:end], ",")
+		switch len(bounds) {
+		case 1:
+			// [1.0] is the only version in the interval
+			if !versionRange.LowerInclusive || !versionRange.UpperInclusive || strings.TrimSpace(bounds[0]) == "" {
+				return nil, fmt.Errorf("invalid version interval: %s", remaining[:end+1])
+			}
+			versionRange.LowerBound = strings.TrimSpace(bounds[0])
+			versionRange.UpperBound = versionRange.LowerBound
+		case 2:
+			versionRange.LowerBound, versionRange.UpperBound = strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+			if (versionRange.LowerBound == "" && versionRange.LowerInclusive) || (versionRange.UpperBound == "" && versionRange.UpperInclusive) {
+				return nil, fmt.Errorf("invalid version interval: %s", remaining[:end+1])
+			}
+		default:
+			return nil, fmt.Errorf("invalid version interval: %s", remaining[:end+1])
+		}
+		ranges = append(ranges, versionRange)
+		remaining = remaining[end+1:]
+	}
+	return
+}
+
+// parseNpmRanges parses npm ranges, separated by ||. The comparators of each range are separated by spaces.
+func parseNpmRanges(npmRanges string) (ranges []VersionRange, err error) {
+	for _, npmRange := range strings.Split(npmRanges, "||") {
+		var versionRange VersionRange
+		comparators := strings.Fields(npmRange)
+		// A hyphen range, such as 1.2.3 - 2.3.4
+		if len(comparators) == 3 && comparators[1] == "-" {
+			ranges = append(ranges, VersionRange{LowerBound: comparators[0], LowerInclusive: true, UpperBound: comparators[2], UpperInclusive: true})
+			continue
+		}
+		// Operators may be separated from their versions by spaces, such as >= 1.2.3
+		for i := 0; i < len(comparators); i++ {
+			comparator := comparators[i]
+			if strings.Trim(comparator, "<>=~^") == "" && i+1 < len(comparators) {
+				i++
+				comparator += comparators[i]
+			}
+			if err = versionRange.addComparator(comparator); err != nil {
+				return nil, fmt.Errorf("invalid npm version range '%s': %w", npmRange, err)
+			}
+		}
+		ranges = append(ranges, versionRange)
+	}
+	return
+}
+
+// parsePep440Specifiers parses PEP 440 version specifiers, separated by commas.
+func parsePep440Specifiers(specifiers string) (*VersionRange, error) {
+	versionRange := &VersionRange{}
+	for _, specifier := range strings.Split(specifiers, ",") {
+		specifier = strings.ReplaceAll(specifier, " ", "")
+		var err error
+		switch {
+		case strings.HasPrefix(specifier, "!="):
+			versionRange.Excluded = append(versionRange.Excluded, strings.TrimPrefix(specifier, "!="))
+		case strings.HasPrefix(specifier, "~="):
+			// ~=1.4.5 is >=1.4.5, ==1.4.*
+			compatibleVersion := strings.TrimPrefix(specifier, "~=")
+			segments := strings.Split(compatibleVersion, ".")
+			if len(segments) < 2 {
+				return nil, fmt.Errorf("invalid PEP 440 compatible release specifier: %s", specifier)
+			}
+			if err = versionRange.addComparator(">=" + compatibleVersion); err == nil {
+				err = versionRange.addComparator("<" + bumpVersion(segments[:len(segments)-1]))
+			}
+		case strings.HasPrefix(specifier, "==="):
+			err = versionRange.addComparator("=" + strings.TrimPrefix(specifier, "==="))
+		case strings.HasPrefix(specifier, "=="):
+			err = versionRange.addComparator("=" + strings.TrimPrefix(specifier, "=="))
+		default:
+			err = versionRange.addComparator(specifier)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid PEP 440 version specifiers '%s': %w", specifiers, err)
+		}
+	}
+	return versionRange, nil
+}
+
+// addComparator narrows the range by a comparator, such as >=1.2.3, <2, =1.0.0, ^1.2.3, ~1.2.3, 1.2.x or 1.2.3.
+func (vr *VersionRange) addComparator(comparator string) error {
+	operator := comparator[:len(comparator)-len(strings.TrimLeft(comparator, "<>=~^"))]
+	versionStr := strings.TrimPrefix(comparator[len(operator):], "v")
+	if versionStr == "" || strings.ContainsAny(versionStr, "<>=~^!") {
+		return fmt.Errorf("invalid comparator: %s", comparator)
+	}
+	if segments := strings.Split(versionStr, "."); isWildcard(segments[len(segments)-1]) {
+		// 1.2.x and 1.2.* are ~1.2
+		if operator != "" && operator != "=" {
+			return fmt.Errorf("invalid comparator: %s", comparator)
+		}
+		for len(segments) > 0 && isWildcard(segments[len(segments)-1]) {
+			segments = segments[:len(segments)-1]
+		}
+		if len(segments) == 0 {
+			return nil
+		}
+		vr.narrowLower(strings.Join(segments, "."), true)
+		vr.narrowUpper(bumpVersion(segments), false)
+		return nil
+	}
+	switch operator {
+	case ">=":
+		vr.narrowLower(versionStr, true)
+	case ">":
+		vr.narrowLower(versionStr, false)
+	case "<=":
+		vr.narrowUpper(versionStr, true)
+	case "<":
+		vr.narrowUpper(versionStr, false)
+	case "", "=":
+		vr.narrowLower(versionStr, true)
+		vr.narrowUpper(versionStr, true)
+	case "^":
+		// ^1.2.3 allows changes that don't modify the left-most non-zero segment
+		segments := strings.Split(strings.SplitN(versionStr, "-", 2)[0], ".")
+		significant := 0
+		for significant < len(segments)-1 && strings.TrimLeft(segments[significant], "0") == "" {
+			significant++
+		}
+		vr.narrowLower(versionStr, true)
+		vr.narrowUpper(bumpVersion(segments[:significant+1]), false)
+	case "~":
+		// ~1.2.3 allows patch-level changes, and ~1 allows minor-level changes
+		segments := strings.Split(strings.SplitN(versionStr, "-", 2)[0], ".")
+		if len(segments) > 2 {
+			segments = segments[:2]
+		}
+		vr.narrowLower(versionStr, true)
+		vr.narrowUpper(bumpVersion(segments), false)
+	default:
+		return fmt.Errorf("invalid comparator: %s", comparator)
+	}
+	return nil
+}
+
+func (vr *VersionRange) narrowLower(bound string, inclusive bool) {
+	if vr.LowerBound == "" {
+		vr.LowerBound, vr.LowerInclusive = bound, inclusive
+		return
+	}
+	if compare := CompareVersions(bound, vr.LowerBound); compare > 0 || (compare == 0 && !inclusive) {
+		vr.LowerBound, vr.LowerInclusive = bound, inclusive
+	}
+}
+
+func (vr *VersionRange) narrowUpper(bound string, inclusive bool) {
+	if vr.UpperBound == "" {
+		vr.UpperBound, vr.UpperInclusive = bound, inclusive
+		return
+	}
+	if compare := CompareVersions(bound, vr.UpperBound); compare < 0 || (compare == 0 && !inclusive) {
+		vr.UpperBound, vr.UpperInclusive = bound, inclusive
+	}
+}
+
+// bumpVersion increments the last of the segments, such as 1.2 to 1.3.
+func bumpVersion(segments []string) string {
+	bumped := append([]string{}, segments...)
+	last := len(bumped) - 1
+	var number int
+	if _, err := fmt.Sscanf(bumped[last], "%d", &number); err == nil {
+		bumped[last] = fmt.Sprint(number + 1)
+	}
+	return strings.Join(bumped, ".")
+}
+
+func isWildcard(segment string) bool {
+	return segment == "x" || segment == "X" || segment == "*"
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseVersionRanges(t *testing.T) {
+	tests := []struct {
+		technology     coreutils.Technology
+		fixVersions    string
+		expectedRanges []VersionRange
+	}{
+		{fixVersions: "1.2.3", expectedRanges: []VersionRange{{LowerBound: "1.2.3", LowerInclusive: true}}},
+		{fixVersions: "[1.2.3]", expectedRanges: []VersionRange{{LowerBound: "1.2.3", LowerInclusive: true, UpperBound: "1.2.3", UpperInclusive: true}}},
+		{fixVersions: "[1.2.3, 2.0.0]", expectedRanges: []VersionRange{{LowerBound: "1.2.3", LowerInclusive: true, UpperBound: "2.0.0", UpperInclusive: true}}},
+		{fixVersions: "(,1.2.3]", expectedRanges: []VersionRange{{UpperBound: "1.2.3", UpperInclusive: true}}},
+		{fixVersions: "(,1.2.3)", expectedRanges: []VersionRange{{UpperBound: "1.2.3"}}},
+		{fixVersions: "(1.2.3,)", expectedRanges: []VersionRange{{LowerBound: "1.2.3"}}},
+		{fixVersions: "(1.2.3, 2.0.0)", expectedRanges: []VersionRange{{LowerBound: "1.2.3", UpperBound: "2.0.0"}}},
+		{fixVersions: "[1.2.3,2.0.0),[2.1.0,)", expectedRanges: []VersionRange{
+			{LowerBound: "1.2.3", LowerInclusive: true, UpperBound: "2.0.0"},
+			{LowerBound: "2.1.0", LowerInclusive: true},
+		}},
+		{technology: coreutils.Npm, fixVersions: ">=1.2.3 <2.0.0 || ^3.1.0", expectedRanges: []VersionRange{
+			{LowerBound: "1.2.3", LowerInclusive: true, UpperBound: "2.0.0"},
+			{LowerBound: "3.1.0", LowerInclusive: true, UpperBound: "4"},
+		}},
+		{technology: coreutils.Yarn, fixVersions: "> 1.2.3", expectedRanges: []VersionRange{{LowerBound: "1.2.3"}}},
+		{technology: coreutils.Npm, fixVersions: "~1.2.3 || 2.x || ^0.0.3", expectedRanges: []VersionRange{
+			{LowerBound: "1.2.3", LowerInclusive: true, UpperBound: "1.3"},
+			{LowerBound: "2", LowerInclusive: true, UpperBound: "3"},
+			{LowerBound: "0.0.3", LowerInclusive: true, UpperBound: "0.0.4"},
+		}},
+		{technology: coreutils.Npm, fixVersions: "1.2.3 - 1.4.0", expectedRanges: []VersionRange{{LowerBound: "1.2.3", LowerInclusive: true, UpperBound: "1.4.0", UpperInclusive: true}}},
+		{technology: coreutils.Pip, fixVersions: ">=1.2, !=1.5, <2.0", expectedRanges: []VersionRange{{LowerBound: "1.2", LowerInclusive: true, UpperBound: "2.0", Excluded: []string{"1.5"}}}},
+		{technology: coreutils.Poetry, fixVersions: "~=1.4.5", expectedRanges: []VersionRange{{LowerBound: "1.4.5", LowerInclusive: true, UpperBound: "1.5"}}},
+		{technology: coreutils.Pipenv, fixVersions: "==2.31.0", expectedRanges: []VersionRange{{LowerBound: "2.31.0", LowerInclusive: true, UpperBound: "2.31.0", UpperInclusive: true}}},
+	}
+	for _, test := range tests {
+		t.Run(test.fixVersions, func(t *testing.T) {
+			ranges, err := ParseVersionRanges(test.technology, test.fixVersions)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRanges, ranges)
+		})
+	}
+}
+
+func TestParseInvalidVersionRanges(t *testing.T) {
+	for _, fixVersions := range []string{"", "[1.2.3", "(1.2.3)", "[,1.2.3]", "[1.0,2.0,3.0]", "[1.2.3],1.4"} {
+		_, err := ParseVersionRanges(coreutils.Maven, fixVersions)
+		assert.Error(t, err, fixVersions)
+	}
+	_, err := ParseVersionRanges(coreutils.Npm, ">=1.0 <=>2.0")
+	assert.Error(t, err)
+	_, err = ParseVersionRanges(coreutils.Pip, "~=1")
+	assert.Error(t, err)
+}
+
+func TestVersionRangeMinimalVersion(t *testing.T) {
+	tests := []struct {
+		versionRange    VersionRange
+		expectedVersion string
+	}{
+		{versionRange: VersionRange{LowerBound: "1.2.3", LowerInclusive: true}, expectedVersion: "1.2.3"},
+		{versionRange: VersionRange{LowerBound: "1.2.3", UpperBound: "1.2.8", UpperInclusive: true}, expectedVersion: "1.2.8"},
+		{versionRange: VersionRange{UpperBound: "1.2.8", UpperInclusive: true}, expectedVersion: ""},
+		{versionRange: VersionRange{LowerBound: "1.2.3"}, expectedVersion: ""},
+		{versionRange: VersionRange{LowerBound: "1.2.3", UpperBound: "2.0.0"}, expectedVersion: ""},
+		{versionRange: VersionRange{LowerBound: "1.5", LowerInclusive: true, Excluded: []string{"1.5"}}, expectedVersion: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.versionRange.String(), func(t *testing.T) {
+			assert.Equal(t, test.expectedVersion, test.versionRange.MinimalVersion())
+		})
+	}
+}
+
+func TestVersionRangeContains(t *testing.T) {
+	versionRange := VersionRange{LowerBound: "1.2.3", UpperBound: "2.0.0", UpperInclusive: true, Excluded: []string{"1.5.0"}}
+	assert.False(t, versionRange.Contains("1.2.3"))
+	assert.True(t, versionRange.Contains("1.2.4"))
+	assert.True(t, versionRange.Contains("v2.0.0"))
+	assert.False(t, versionRange.Contains("1.5.0"))
+	assert.False(t, versionRange.Contains("2.0.1"))
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"os"
+	"path/filepath"
+)
+
+// ValidateConfig checks the frogbot-config.yml file against the Frogbot configuration schema, and lists the Frogbot
+// environment variables which are ignored because the file sets the same parameters.
+// Unlike the other commands, it requires no JFrog Platform or Git provider details.
+func ValidateConfig(configPath string) (err error) {
+	var configFileContent []byte
+	var repositoryRoot string
+	if configPath == "" {
+		configFileContent, repositoryRoot, err = utils.ReadConfigAndRootFromFileSystem(filepath.Join(utils.FrogbotConfigDir, utils.FrogbotConfigFile))
+	} else {
+		configFileContent, err = os.ReadFile(configPath)
+		repositoryRoot = utils.GetConfigRepositoryRoot(configPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Shared configurations in Git repositories can't be downloaded, as the Git provider details aren't required by this command
+	_, envOverrides, err := utils.NewConfigExtendsResolver(nil, nil).SetRepositoryRoot(repositoryRoot).ResolveConfigExtends(configFileContent)
+	if err != nil {
+		var invalidConfigErr *utils.ErrInvalidConfig
+		if !errors.As(err, &invalidConfigErr) {
+			return err
+		}
+		for _, issue := range invalidConfigErr.Issues {
+			log.Error(issue.String())
+		}
+		return fmt.Errorf("found %d issues in the %s file", len(invalidConfigErr.Issues), utils.FrogbotConfigFile)
+	}
+
+	for _, issue := range envOverrides {
+		log.Warn(issue.String())
+	}
+	log.Info(fmt.Sprintf("The %s file is valid", utils.FrogbotConfigFile))
+	return nil
+}
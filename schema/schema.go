@@ -0,0 +1,8 @@
+package schema
+
+import _ "embed"
+
+// FrogbotSchema is the JSON schema the frogbot-config.yml file is validated against.
+//
+//go:embed frogbot-schema.json
+var FrogbotSchema []byte